@@ -23,18 +23,28 @@ var users = []models.User{
 
 var puzzles = []models.Puzzle{
 	models.Puzzle{
-		ID:     1,
-		Name:   "John's First Puzzle",
-		UserID: 1,
+		ID:         1,
+		Name:       "John's First Puzzle",
+		Difficulty: "easy",
+		Seed:       1,
+		UserID:     1,
 	},
 	models.Puzzle{
-		ID:     2,
-		Name:   "John's Second Puzzle",
-		UserID: 1,
+		ID:         2,
+		Name:       "John's Second Puzzle",
+		Difficulty: "hard",
+		Seed:       2,
+		UserID:     1,
 	},
 	models.Puzzle{
 		ID:     3,
 		Name:   "W's First puzzle",
 		UserID: 2,
 	},
+	models.Puzzle{
+		ID:     4,
+		Name:   "W's Hexadoku",
+		Size:   16,
+		UserID: 2,
+	},
 }
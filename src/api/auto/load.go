@@ -6,6 +6,7 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/config"
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
 )
 
 // Load attempts connection to the database
@@ -19,7 +20,7 @@ func Load() {
 
 	defer db.Close()
 
-	err = db.Debug().DropTableIfExists(&models.Board{}, &models.Puzzle{}, &models.User{}).Error
+	err = db.Debug().DropTableIfExists(&models.Cage{}, &models.Hint{}, &models.Move{}, &models.Session{}, &models.Board{}, &models.Puzzle{}, &models.User{}).Error
 
 	if err != nil {
 		// print error, followed by call to os.exit
@@ -27,7 +28,7 @@ func Load() {
 	}
 
 	// Creates tables for models based on schema defined in models
-	err = db.Debug().AutoMigrate(&models.Board{}, &models.Puzzle{}, &models.User{}).Error
+	err = db.Debug().AutoMigrate(&models.Board{}, &models.Cage{}, &models.Puzzle{}, &models.User{}, &models.Session{}, &models.Move{}, &models.Hint{}).Error
 
 	if err != nil {
 		// print error, followed by call to os.exit
@@ -50,6 +51,55 @@ func Load() {
 		log.Fatal(err)
 	}
 
+	// ID in Puzzle model(PK) - PuzzleID in Cage model (FK)
+	err = db.Debug().Model(&models.Cage{}).AddForeignKey("puzzle_id", "puzzles(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ID in Puzzle model(PK) - PuzzleID in Session model (FK)
+	err = db.Debug().Model(&models.Session{}).AddForeignKey("puzzle_id", "puzzles(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ID in User model(PK) - UserID in Session model (FK)
+	err = db.Debug().Model(&models.Session{}).AddForeignKey("user_id", "users(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ID in Puzzle model(PK) - PuzzleID in Move model (FK)
+	err = db.Debug().Model(&models.Move{}).AddForeignKey("puzzle_id", "puzzles(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ID in User model(PK) - UserID in Move model (FK)
+	err = db.Debug().Model(&models.Move{}).AddForeignKey("user_id", "users(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ID in Puzzle model(PK) - PuzzleID in Hint model (FK)
+	err = db.Debug().Model(&models.Hint{}).AddForeignKey("puzzle_id", "puzzles(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ID in User model(PK) - UserID in Hint model (FK)
+	err = db.Debug().Model(&models.Hint{}).AddForeignKey("user_id", "users(id)", "cascade", "cascade").Error
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Populate db with initial values
 	for i, _ := range users {
 
@@ -63,6 +113,33 @@ func Load() {
 	for j, _ := range puzzles {
 		// puzzles[j].UserID = users[i].ID
 
+		// Puzzles without a size are classic puzzles
+		if puzzles[j].Size == 0 {
+			puzzles[j].Size = models.DefaultGridSize
+		}
+
+		puzzles[j].BoxRows, puzzles[j].BoxCols = models.BoxShape(puzzles[j].Size)
+
+		// Generate and grade givens for puzzles with a difficulty, other puzzles start empty
+		givens := sudoku.NewGrid(puzzles[j].Size)
+
+		if puzzles[j].Difficulty != "" {
+			givens, err = sudoku.Generate(sudoku.Difficulty(puzzles[j].Difficulty), puzzles[j].Seed)
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			grade, err := sudoku.Rate(givens)
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			puzzles[j].Difficulty = string(grade.Difficulty)
+			puzzles[j].Score = grade.Score
+		}
+
 		err = db.Debug().Model(&models.Puzzle{}).Create(&puzzles[j]).Error
 
 		if err != nil {
@@ -70,12 +147,14 @@ func Load() {
 		}
 
 		// Create board for every new puzzle
-		for k := 1; k <= 9; k++ {
-			for l := 1; l <= 9; l++ {
+		for k := 1; k <= puzzles[j].Size; k++ {
+			for l := 1; l <= puzzles[j].Size; l++ {
 
 				board := models.Board{}
 				board.BoardRow = k
 				board.BoardCol = l
+				board.Value = givens[k-1][l-1]
+				board.Given = board.Value != 0
 				board.PuzzleID = puzzles[j].ID
 
 				err = db.Debug().Model(&models.Board{}).Create(&board).Error
@@ -1,6 +1,7 @@
 package caching
 
 import (
+	"strings"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -14,6 +15,15 @@ import (
 // GO-CACHE
 var Cache = cache.New(5*time.Minute, 10*time.Minute)
 
+// DeletePrefix removes every item of Cache whose key starts with prefix, e.g. "leaderboards/"
+func DeletePrefix(prefix string) {
+	for key := range Cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			Cache.Delete(key)
+		}
+	}
+}
+
 // GROUPCACHE
 // Experiment with groupcache
 // - In-code Distributed Cache
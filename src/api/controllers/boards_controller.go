@@ -2,13 +2,16 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
 	"github.com/patrickmn/go-cache"
 	"github.com/quattad/sudokubuddy-backend/src/api/auth"
 	"github.com/quattad/sudokubuddy-backend/src/api/caching"
@@ -17,8 +20,12 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
 	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
 )
 
+// errGivenBoard is returned when a request tries to modify one of the givens of a puzzle
+var errGivenBoard = errors.New("Board is a given and cannot be modified")
+
 // GetBoard fetches a board by boardID
 func GetBoard(w http.ResponseWriter, r *http.Request) {
 	/*
@@ -289,7 +296,7 @@ func CreateBoard(w http.ResponseWriter, r *http.Request) {
 
 	// Validate board
 	board.PrepareBoard()
-	err = board.ValidateBoard("") // default case
+	err = board.ValidateBoardSize("", models.MaxGridSize) // default case, for a puzzle of any size
 
 	if err != nil {
 		responses.ERROR(w, http.StatusUnprocessableEntity, err)
@@ -323,8 +330,18 @@ func UpdateBoard(w http.ResponseWriter, r *http.Request) {
 		1. Read from request body into bytes. If err, return status code 400.
 		2. Unmarshal from bytes to model. If err, return status code 400.
 		3. Extract tokenID from request and check if it matches userID. If no match, return status code 401.
-		4. Connect to db. If err, return status code 500.
-		5. Execute update. If successful, return status code 200 with number of rows updated.
+		4. Validate board. If err, return status code 422.
+		5. Connect to db. If err, return status code 500.
+		6. Fetch the puzzle owned by the user and its boards. If the user owns no such puzzle, return status code 404.
+		   Check that the board is not a given. If it is, return status code 403.
+		7. Check that the puzzle is not in a race that has not started or is finished. If it is, return status code 409.
+		8. Check the new value against the size and the other boards of the puzzle. If out of range, return status code 422.
+		   Fetch the rules of the variants of the puzzle. If err, return status code 400.
+		   If strict=true and it conflicts under those rules or completes a killer cage wrongly, return status code 409.
+		9. Execute update, which also appends the change to the move log. If err, return status code 400.
+		10. Publish the new value to the subscribers of the puzzle, count the move in the game session of the puzzle and
+		    complete it if the grid is solved, and update the progress of the player if the puzzle is in a race.
+		11. Return status code 200 with number of rows updated, any conflicts and the game session.
 	*/
 
 	// Extract id (boardID) from route variables
@@ -383,23 +400,45 @@ func UpdateBoard(w http.ResponseWriter, r *http.Request) {
 
 	board.BoardRow, err = strconv.Atoi(u["board_row"][0])
 
-	if err != nil || board.BoardRow < 1 || board.BoardRow > 9 {
+	if err != nil || board.BoardRow < 1 || board.BoardRow > models.MaxGridSize {
 		responses.ERROR(w, http.StatusBadRequest, err)
 	}
 
 	board.BoardCol, err = strconv.Atoi(u["board_col"][0])
 
-	if err != nil || board.BoardCol < 1 || board.BoardCol > 9 {
+	if err != nil || board.BoardCol < 1 || board.BoardCol > models.MaxGridSize {
 		responses.ERROR(w, http.StatusBadRequest, err)
 	}
 
-	// TODO - Resolve checking of userID
-	// Extract tokenID from request and check if it matches userID. If no match, return status code 401.
-	// userID, err := auth.TokenService.ExtractTokenID(r)
+	// The size of the puzzle is only known once it is fetched
+	board.PuzzleID = uint32(puzzleID)
+	err = board.ValidateBoardSize("update", models.MaxGridSize)
 
-	// if err != nil {
-	// 	responses.ERROR(w, http.StatusUnauthorized, err)
-	// }
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Refuse values that conflict with other cells if strict=true
+	strict := false
+
+	if u.Get("strict") != "" {
+		strict, err = strconv.ParseBool(u.Get("strict"))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// Only the owner writes to the boards of a puzzle through this endpoint, others play shared puzzles through
+	// CoopPuzzle. The move log records the user that made each change
+	userID, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
 
 	// Connect to database
 	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
@@ -410,25 +449,132 @@ func UpdateBoard(w http.ResponseWriter, r *http.Request) {
 
 	defer db.Close()
 
-	// Execute search
-	repo := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	// Place the new value in the current grid and check it for row, column and box conflicts
+	puzzle, boards, err := findPuzzleBoards(db, uint32(puzzleID), userID)
+
+	if err != nil {
+		responses.ERROR(w, puzzleErrorStatus(err), err)
+		return
+	}
 
-	rows, err := repo.Update(uint32(puzzleID), board)
+	// Givens are the clues of the puzzle and cannot be overwritten, so the value is an entry whatever the request says
+	for _, b := range boards {
+		if b.BoardRow == board.BoardRow && b.BoardCol == board.BoardCol && b.Given {
+			responses.ERROR(w, http.StatusForbidden, errGivenBoard)
+			return
+		}
+	}
+
+	board.Given = false
+
+	// Boards of a race can only be filled while the race is on
+	err = checkRaceMove(uint32(puzzleID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	// Variants of the puzzle add their own groups of cells to the rows, columns and boxes
+	rules, err := puzzleRules(db, puzzle)
 
 	if err != nil {
 		responses.ERROR(w, http.StatusBadRequest, err)
+		return
 	}
 
-	responses.JSON(w, http.StatusOK, rows)
+	grid, err := sudoku.FromBoards(puzzle, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = board.ValidateBoardSize("update", grid.Size())
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	grid[board.BoardRow-1][board.BoardCol-1] = board.Value
+	conflicts := rules.Conflicts(grid, board.BoardRow, board.BoardCol)
+	cages := rules.CageErrors(grid, board.BoardRow, board.BoardCol)
+
+	if strict && (len(conflicts) > 0 || len(cages) > 0) {
+		responses.JSON(w, http.StatusConflict, boardUpdate{Rows: 0, Conflicts: conflicts, Cages: cages})
+		return
+	}
+
+	rows, err := crud.BoardsCRUDService.NewBoardsCRUD(db).Update(uint32(puzzleID), userID, board)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	session := recordBoardChanges(db, puzzle, userID, boards, []models.Board{board}, grid, rules)
+
+	responses.JSON(w, http.StatusOK, boardUpdate{Rows: rows, Conflicts: conflicts, Cages: cages, Session: session})
+}
+
+// boardUpdate is the response body of UpdateBoard
+// Conflicts lists the cells that already hold the new value in the same row, column, box or unit of a variant,
+// Cages lists the completed killer cages of the board that have the wrong sum or repeat a value and
+// Session is the game session of the puzzle that counted the move, if any
+type boardUpdate struct {
+	Rows      int64              `json:"rows"`
+	Conflicts []sudoku.Conflict  `json:"conflicts"`
+	Cages     []sudoku.CageError `json:"cages,omitempty"`
+	Session   *models.Session    `json:"session,omitempty"`
+}
+
+// recordBoardChanges runs the hooks of the boards of changed once userID has committed them, with grid holding the
+// values of boards after the changes. The changes are published to the subscribers of the puzzle, and the values
+// entered by players are counted as moves of its game session and as progress in its race. Returns the game session
+// that counted the moves, if any
+// The boards are already updated, so moves that cannot be counted are only logged
+func recordBoardChanges(db *gorm.DB, puzzle models.Puzzle, userID uint32, boards []models.Board, changed []models.Board, grid sudoku.Grid, rules sudoku.Rules) *models.Session {
+	moves := []models.Board{}
+
+	for i := range changed {
+		publishPuzzleChange(puzzleBoardEvent, puzzleChange{
+			PuzzleID: changed[i].PuzzleID,
+			UserID:   userID,
+			BoardRow: changed[i].BoardRow,
+			BoardCol: changed[i].BoardCol,
+			Value:    &changed[i].Value,
+		})
+
+		if !changed[i].Given {
+			moves = append(moves, changed[i])
+		}
+	}
+
+	session, err := recordMove(db, puzzle, boards, moves, grid, rules)
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	recordRaceMove(moves, grid)
+
+	return session
 }
 
 // DeleteBoard deletes a board by id
 func DeleteBoard(w http.ResponseWriter, r *http.Request) {
 	/*
-		1. Extract UID from route variable
-		2. Connect to db. If err, return status code 500.
-		3. Extract the tokenID and check if it matches the userID. If it does not match, return status code 201 unauthorized.
-		4. Execute update. If successful, return status code 200 and number of rows updated.
+		1. Extract id (boardID) from route variables. If err, return status code 400.
+		2. Extract the tokenID. If err, return status code 401.
+		3. Connect to db. If err, return status code 500.
+		4. Fetch the board, and the puzzle of the board owned by the user with its boards. If err, return status code 400,
+		   or 404 if the user does not own the puzzle.
+		5. Check that the board is not a given. If it is, return status code 403.
+		6. Check that the puzzle is not in a race that has not started or has finished. If it is, return status code 409.
+		   Build the grid of the boards. If err, return status code 422.
+		7. Execute delete. If successful, update the progress of the race of the puzzle with the emptied cell,
+		and return status code 200 and number of rows deleted.
 	*/
 
 	routeVariables := mux.Vars(r)
@@ -436,29 +582,71 @@ func DeleteBoard(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tokenUID, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
 	}
 
 	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
 
 	if err != nil {
 		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
 	}
 
 	defer db.Close()
 
-	// tokenUID, err := auth.TokenService.ExtractTokenID(r)
+	repo := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	board, err := repo.FindByID(uint32(boardID))
 
-	// if err != nil {
-	// 	responses.ERROR(w, http.StatusUnauthorized, err)
-	// }
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
 
-	repo := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	puzzle, boards, err := findPuzzleBoards(db, board.PuzzleID, tokenUID)
+
+	if err != nil {
+		responses.ERROR(w, puzzleErrorStatus(err), err)
+		return
+	}
+
+	// Givens are the clues of the puzzle and cannot be deleted
+	if board.Given {
+		responses.ERROR(w, http.StatusForbidden, errGivenBoard)
+		return
+	}
+
+	err = checkRaceMove(board.PuzzleID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	grid, err := sudoku.FromBoards(puzzle, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
 
 	rows, err := repo.Delete(uint32(boardID))
 
 	if err != nil {
 		responses.ERROR(w, http.StatusBadRequest, err)
+		return
 	}
 
+	// A deleted board leaves its cell empty
+	board.Value = 0
+	grid[board.BoardRow-1][board.BoardCol-1] = 0
+	recordRaceMove([]models.Board{board}, grid)
+
 	responses.JSON(w, http.StatusOK, rows)
 }
@@ -1,7 +1,199 @@
 package controllers
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
 
 func TestDeleteBoardIfSuccessful(t *testing.T) {
 	t.Skip("Board is deleted when puzzle is deleted")
 }
+
+func TestDeleteBoardIfGiven(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusForbidden
+
+	boardID := uint32(1)
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// SELECT * FROM `boards` WHERE (id=?) LIMIT 1
+	rows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "given", "puzzle_id"}).
+		AddRow(boardID, 1, 1, 5, true, puzzleID)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(boardID).
+		WillReturnRows(rows)
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("DELETE", "/boards", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(boardID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	DeleteBoard(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no delete was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestDeleteBoardIfRaceNotStarted(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusConflict
+
+	boardID := uint32(3)
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	_, remove := addRace(&racePlayer{UserID: uid, PuzzleID: puzzleID})
+	defer remove()
+
+	// SELECT * FROM `boards` WHERE (id=?) LIMIT 1
+	rows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "given", "puzzle_id"}).
+		AddRow(boardID, 1, 3, 4, false, puzzleID)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(boardID).
+		WillReturnRows(rows)
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("DELETE", "/boards", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(boardID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	DeleteBoard(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no delete was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestDeleteBoardIfNotOwner(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusNotFound
+
+	boardID := uint32(3)
+	puzzleID := uint32(445)
+	uid := uint32(200)
+
+	// SELECT * FROM `boards` WHERE (id=?) LIMIT 1
+	rows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "given", "puzzle_id"}).
+		AddRow(boardID, 1, 3, 4, false, puzzleID)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(boardID).
+		WillReturnRows(rows)
+
+	// The puzzle of the board belongs to another user
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("DELETE", "/boards", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(boardID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	DeleteBoard(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no delete was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -7,16 +7,39 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jinzhu/gorm"
+	"github.com/patrickmn/go-cache"
 	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
 	"github.com/quattad/sudokubuddy-backend/src/api/tests"
 )
 
+// expectBoardUpdate mocks the transaction of BoardsCRUD.Update, which locks the board holding oldValue,
+// updates it to newValue and appends the change to the move log
+func expectBoardUpdate(s tests.Suite, puzzleID uint32, uid uint32, boardRow int, boardCol int, oldValue int, newValue int) {
+	rows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "puzzle_id"}).
+		AddRow(1, boardRow, boardCol, oldValue, puzzleID)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT (.+) FOR UPDATE").
+		WithArgs(puzzleID, boardRow, boardCol).
+		WillReturnRows(rows)
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), newValue, puzzleID, boardRow, boardCol).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs(models.MoveSet, boardRow, boardCol, oldValue, newValue, 0, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+}
+
 func TestUpdateBoardIfSuccessful(t *testing.T) {
 	// Populate DB
 	s := tests.CreateSuite()
@@ -40,12 +63,15 @@ func TestUpdateBoardIfSuccessful(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
 	}
 
-	// 'UPDATE `boards` SET `updated_at` = ?, `value` = ?  WHERE (puzzle_id=? AND board_row=? AND board_col=?)
-	s.Mock.ExpectBegin()
-	s.Mock.ExpectExec("UPDATE").
-		WithArgs(sqlmock.AnyArg(), expectedValue, puzzleID, boardRow, boardCol).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	s.Mock.ExpectCommit()
+	// SELECT * FROM `boards` WHERE (puzzle_id=?) ORDER BY board_row, board_col
+	expectOwnedPuzzle(s, puzzleID, uid, "")
+	expectPuzzleBoards(s, puzzleID, strings.Repeat(".", 81))
+	expectBoardUpdate(s, puzzleID, uid, boardRow, boardCol, 0, expectedValue)
+
+	// SELECT * FROM `sessions` WHERE (puzzle_id=? AND completed_at IS NULL) ORDER BY id desc LIMIT 1
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
 
 	// Initialize struct with modified interfaces
 	database.DBService = &dbMock{}
@@ -102,3 +128,570 @@ func TestUpdateBoardIfSuccessful(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+func TestUpdateBoardIfStrictConflict(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusConflict
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// (1, 3) would repeat the 5 in (1, 1), which shares its row and box
+	data := []models.Board{
+		models.Board{
+			Value: 5,
+		},
+	}
+
+	body, err := json.Marshal(data[0])
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	expectOwnedPuzzle(s, puzzleID, uid, "")
+	expectPuzzleBoards(s, puzzleID, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	q.Add("strict", "true")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	UpdateBoard(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	response := boardUpdate{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Conflicts) != 2 || response.Conflicts[0].Row != 1 || response.Conflicts[0].Col != 1 {
+		t.Errorf("Error: handler returned unexpected conflicts: %s", rr.Body.String())
+	}
+
+	// ensure no update was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateBoardIfStrictKnightConflict(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusConflict
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// (2, 5) is a knight's move away from the 5 entered in (1, 3), which only matters in an anti-knight puzzle
+	body, err := json.Marshal(models.Board{Value: 5})
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	expectOwnedPuzzle(s, puzzleID, uid, models.VariantAntiKnight)
+	expectPuzzleBoardsEntered(s, puzzleID, strings.Repeat(".", 81), "..5"+strings.Repeat(".", 78))
+
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "2")
+	q.Add("board_col", "5")
+	q.Add("strict", "true")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	req.Header.Set("Content-Type", "application/json")
+
+	UpdateBoard(rr, req)
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	response := boardUpdate{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Conflicts) != 1 || response.Conflicts[0].Unit != "knight" || response.Conflicts[0].Row != 1 || response.Conflicts[0].Col != 3 {
+		t.Errorf("Error: handler returned unexpected conflicts: %s", rr.Body.String())
+	}
+
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateBoardIfStrictWrongCageSum(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusConflict
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// (1, 2) completes the cage of (1, 1) and (1, 2) with a total of 5 instead of 3
+	body, err := json.Marshal(models.Board{Value: 4})
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	expectOwnedPuzzle(s, puzzleID, uid, models.VariantKiller)
+	expectPuzzleBoardsEntered(s, puzzleID, strings.Repeat(".", 81), "1"+strings.Repeat(".", 80))
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "cells", "sum", "puzzle_id"}).AddRow(1, "1112", 3, puzzleID))
+
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "2")
+	q.Add("strict", "true")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+	req.Header.Set("Content-Type", "application/json")
+
+	UpdateBoard(rr, req)
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	response := boardUpdate{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Conflicts) != 0 || len(response.Cages) != 1 || response.Cages[0].Total != 5 || response.Cages[0].Sum != 3 {
+		t.Errorf("Error: handler returned unexpected cages: %s", rr.Body.String())
+	}
+
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateBoardIfInvalidValue(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	// 10 is only a value of larger puzzles
+	expectOwnedPuzzle(s, 445, 100, "")
+	expectPuzzleBoards(s, 445, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return 100, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBufferString(`{"value": 10}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", "445")
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	UpdateBoard(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestUpdateBoardIfGiven(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusForbidden
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	data := []models.Board{
+		models.Board{
+			Value: 4,
+		},
+	}
+
+	body, err := json.Marshal(data[0])
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	// (1, 1) holds the given 5
+	expectOwnedPuzzle(s, puzzleID, uid, "")
+	expectPuzzleBoards(s, puzzleID, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "1")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	UpdateBoard(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no update was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateBoardIfNotOwner(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusNotFound
+
+	puzzleID := uint32(445)
+	uid := uint32(200)
+
+	// The puzzle belongs to another user, so it is not found for uid and nothing is written
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBufferString(`{"value": 4}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	UpdateBoard(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no update was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateBoardIfFinalCellCompletesSession(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+	sessionID := uint32(7)
+
+	// Only (1, 3) is empty and its solution is 4
+	invalidatePuzzleSolution(puzzleID)
+	expectOwnedPuzzle(s, puzzleID, uid, "")
+	expectPuzzleBoards(s, puzzleID, "530"+testSolution[3:])
+
+	expectBoardUpdate(s, puzzleID, uid, 1, 3, 0, 4)
+
+	sessionRows := s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(sessionID, time.Now().Add(-time.Minute), 50, 0, puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(sessionRows)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 51, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sessionID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// The completed session must not be missing from leaderboards that were cached before it
+	caching.Cache.Set(leaderboardsCachePrefix+"global/0//10", []byte("[]"), cache.DefaultExpiration)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBuffer([]byte(`{"value": 4}`)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	UpdateBoard(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	response := boardUpdate{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Session == nil || response.Session.Status != models.SessionSolved || response.Session.CompletedAt == nil {
+		t.Errorf("Error: handler returned unexpected session: %s", rr.Body.String())
+	}
+
+	if _, found := caching.Cache.Get(leaderboardsCachePrefix + "global/0//10"); found {
+		t.Errorf("Error: cached leaderboard was not invalidated")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateBoardIfSessionUpdateFails(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(446)
+	uid := uint32(100)
+	sessionID := uint32(8)
+
+	// The move is checked against the solution cached when the session started rather than solved again, so the 4
+	// in (1, 3) is a mistake
+	solution := givensFromString(testSolution)
+	solution[0][2] = 9
+	caching.Cache.Set("solutions/446", puzzleSolution{Grid: solution}, cache.DefaultExpiration)
+
+	expectOwnedPuzzle(s, puzzleID, uid, "")
+	expectPuzzleBoards(s, puzzleID, "530"+testSolution[3:])
+
+	expectBoardUpdate(s, puzzleID, uid, 1, 3, 0, 4)
+
+	sessionRows := s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(sessionID, time.Now().Add(-time.Minute), 50, 0, puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(sessionRows)
+
+	// The board is already updated when the session cannot be
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 51, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sessionID).
+		WillReturnError(fmt.Errorf("Lock wait timeout exceeded"))
+	s.Mock.ExpectRollback()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards", bytes.NewBuffer([]byte(`{"value": 4}`)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	UpdateBoard(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	response := boardUpdate{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Rows != 1 || response.Session != nil {
+		t.Errorf("Error: handler returned unexpected update: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
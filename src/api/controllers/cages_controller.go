@@ -0,0 +1,359 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// errNotKillerPuzzle is returned when a cage is added to a puzzle that does not have the killer variant
+var errNotKillerPuzzle = errors.New("Cages can only be added to killer puzzles")
+
+// GetCages fetches the cages of the puzzle at the puzzle_id query parameter
+func GetCages(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read puzzle_id from query parameters. If err, return status code 400.
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to db. If err, return status code 500.
+		4. Execute FindByID to check that the puzzle belongs to uid, return status code 400 if err
+		5. Execute FindAllByPuzzleID. If successful, return status code 200 and the cages.
+	*/
+
+	position, err := parseBoardPosition(r, false)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	_, err = crud.PuzzlesCRUDService.NewPuzzlesCRUD(db).FindByID(position.PuzzleID, uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cages, err := crud.CagesCRUDService.NewCagesCRUD(db).FindAllByPuzzleID(position.PuzzleID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, cages)
+}
+
+// CreateCage adds a cage to a killer puzzle of the user
+func CreateCage(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read from request body and unmarshal into models.Cage. If err, return status code 422.
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to db. If err, return status code 500.
+		4. Execute FindByID to check that the puzzle belongs to uid, return status code 400 if err
+		5. Execute FindAllByPuzzleID for the other cages of the puzzle, return status code 400 if err
+		6. Check that the puzzle is a killer puzzle and that the cage is valid, contiguous and does not overlap the
+		   other cages. If not, return status code 422.
+		7. Execute save. If err, return status code 422.
+		8. Return status code 201 with the cage.
+	*/
+
+	cage := models.Cage{}
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = json.Unmarshal(body, &cage)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, err := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db).FindByID(cage.PuzzleID, uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repo := crud.CagesCRUDService.NewCagesCRUD(db)
+	cages, err := repo.FindAllByPuzzleID(cage.PuzzleID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cage.ID = 0
+	cage.PrepareCage()
+	err = checkCage(puzzle, cage, cages)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	cage, err = repo.Save(cage)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Cages are rules of the puzzle, so its solution may have changed
+	invalidatePuzzleSolution(cage.PuzzleID)
+
+	w.Header().Set("Location", fmt.Sprintf("%s%s/%d", r.Host, r.RequestURI, cage.ID))
+	responses.JSON(w, http.StatusCreated, cage)
+}
+
+// UpdateCage replaces the cells and sum of a cage by id
+func UpdateCage(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (cageID) from route variables and convert to uint32, return status code 400 if err
+		2. Read from request body and unmarshal into models.Cage. If err, return status code 422.
+		3. Get uid (userID) from request, if not authorized, return status code 401
+		4. Connect to db. If err, return status code 500.
+		5. Fetch the cage and its puzzle, and check that the puzzle belongs to uid, return status code 400 if err
+		6. Check the new cells and sum against the other cages of the puzzle. If invalid, return status code 422.
+		7. Execute update. If successful, return status code 200 and number of rows updated.
+	*/
+
+	routeVariables := mux.Vars(r)
+	cageID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	update := models.Cage{}
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = json.Unmarshal(body, &update)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.CagesCRUDService.NewCagesCRUD(db)
+	cage, puzzle, err := findPuzzleCage(db, uint32(cageID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cages, err := repo.FindAllByPuzzleID(cage.PuzzleID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cage.Cells = update.Cells
+	cage.Sum = update.Sum
+	cage.PrepareCage()
+	err = checkCage(puzzle, cage, cages)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	rows, err := repo.Update(cage)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	invalidatePuzzleSolution(cage.PuzzleID)
+
+	responses.JSON(w, http.StatusOK, rows)
+}
+
+// DeleteCage deletes a cage by id
+func DeleteCage(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (cageID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to db. If err, return status code 500.
+		4. Fetch the cage and its puzzle, and check that the puzzle belongs to uid, return status code 400 if err
+		5. Execute delete. If successful, return status code 200 and number of rows deleted.
+	*/
+
+	routeVariables := mux.Vars(r)
+	cageID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	cage, _, err := findPuzzleCage(db, uint32(cageID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := crud.CagesCRUDService.NewCagesCRUD(db).Delete(uint32(cageID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	invalidatePuzzleSolution(cage.PuzzleID)
+
+	responses.JSON(w, http.StatusOK, rows)
+}
+
+// findPuzzleCage fetches a cage by id and the puzzle that it belongs to, which must be owned by userID
+func findPuzzleCage(db *gorm.DB, cageID uint32, userID uint32) (models.Cage, models.Puzzle, error) {
+	cage, err := crud.CagesCRUDService.NewCagesCRUD(db).FindByID(cageID)
+
+	if err != nil {
+		return models.Cage{}, models.Puzzle{}, err
+	}
+
+	puzzle, err := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db).FindByID(cage.PuzzleID, userID)
+
+	if err != nil {
+		return models.Cage{}, models.Puzzle{}, err
+	}
+
+	return cage, puzzle, nil
+}
+
+// checkCage checks that puzzle is a killer puzzle, and that cage fits its grid and the other cages in cages under the
+// killer rules. A cage in cages with the ID of cage is the previous version of cage and is left out
+func checkCage(puzzle models.Puzzle, cage models.Cage, cages []models.Cage) error {
+	if !puzzle.HasVariant(models.VariantKiller) {
+		return errNotKillerPuzzle
+	}
+
+	err := cage.ValidateCage(puzzle.GridSize())
+
+	if err != nil {
+		return err
+	}
+
+	others := []models.Cage{}
+
+	for _, c := range cages {
+		if c.ID != cage.ID {
+			others = append(others, c)
+		}
+	}
+
+	_, err = sudoku.PuzzleRules(puzzle, append(others, cage))
+	return err
+}
+
+// findPuzzleCages fetches the cages of puzzle if it is a killer puzzle, which are the only puzzles that have cages
+func findPuzzleCages(db *gorm.DB, puzzle models.Puzzle) ([]models.Cage, error) {
+	if !puzzle.HasVariant(models.VariantKiller) {
+		return nil, nil
+	}
+
+	return crud.CagesCRUDService.NewCagesCRUD(db).FindAllByPuzzleID(puzzle.ID)
+}
+
+// puzzleRules returns the rules of the variants of puzzle, with the cages of a killer puzzle read from the db
+func puzzleRules(db *gorm.DB, puzzle models.Puzzle) (sudoku.Rules, error) {
+	cages, err := findPuzzleCages(db, puzzle)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sudoku.PuzzleRules(puzzle, cages)
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// expectPuzzleWithCages mocks the query for a puzzle of variants owned by uid and the query for its cages
+func expectPuzzleWithCages(s tests.Suite, puzzleID uint32, uid uint32, variants string, cages []models.Cage) {
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "size", "box_rows", "box_cols", "variants", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", 9, 3, 3, variants, time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	cageRows := s.Mock.NewRows([]string{"id", "cells", "sum", "puzzle_id"})
+
+	for _, cage := range cages {
+		cageRows.AddRow(cage.ID, cage.Cells, cage.Sum, puzzleID)
+	}
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(cageRows)
+}
+
+// createCage runs CreateCage as uid with cage as the request body
+func createCage(t *testing.T, s tests.Suite, uid uint32, cage models.Cage) *httptest.ResponseRecorder {
+	body, err := json.Marshal(cage)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("POST", "/cages", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	CreateCage(rr, req)
+
+	return rr
+}
+
+// ========== CREATECAGE() ========== //
+func TestCreateCageIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	expectPuzzleWithCages(s, puzzleID, uid, models.VariantKiller, []models.Cage{{ID: 1, Cells: "1112", Sum: 3}})
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs("2122", 17, sqlmock.AnyArg(), sqlmock.AnyArg(), puzzleID).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	s.Mock.ExpectCommit()
+
+	rr := createCage(t, s, uid, models.Cage{Cells: "21 22", Sum: 17, PuzzleID: puzzleID})
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	cage := models.Cage{}
+
+	if err := json.Unmarshal(rr.Body.Bytes(), &cage); err != nil {
+		t.Fatal(err)
+	}
+
+	if cage.ID != 2 || cage.Cells != "2122" {
+		t.Errorf("Error: handler returned unexpected cage: %s", rr.Body.String())
+	}
+
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestCreateCageIfOverlapping(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// (1, 2) already belongs to the first cage
+	expectPuzzleWithCages(s, puzzleID, uid, models.VariantKiller, []models.Cage{{ID: 1, Cells: "1112", Sum: 3}})
+
+	rr := createCage(t, s, uid, models.Cage{Cells: "1213", Sum: 5, PuzzleID: puzzleID})
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if !bytes.Contains(rr.Body.Bytes(), []byte(sudoku.ErrCagesOverlap.Error())) {
+		t.Errorf("Error: handler returned unexpected body: %s", rr.Body.String())
+	}
+
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestCreateCageIfNotContiguous(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	expectPuzzleWithCages(s, puzzleID, uid, models.VariantKiller, nil)
+
+	rr := createCage(t, s, uid, models.Cage{Cells: "1122", Sum: 5, PuzzleID: puzzleID})
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if !bytes.Contains(rr.Body.Bytes(), []byte(sudoku.ErrCageNotContiguous.Error())) {
+		t.Errorf("Error: handler returned unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCreateCageIfNotKillerPuzzle(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	expectPuzzleWithCages(s, puzzleID, uid, models.VariantX, nil)
+
+	rr := createCage(t, s, uid, models.Cage{Cells: "1112", Sum: 3, PuzzleID: puzzleID})
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if !bytes.Contains(rr.Body.Bytes(), []byte(errNotKillerPuzzle.Error())) {
+		t.Errorf("Error: handler returned unexpected body: %s", rr.Body.String())
+	}
+}
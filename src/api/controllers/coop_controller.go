@@ -0,0 +1,422 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/websocket"
+)
+
+// Types of messages exchanged with the players of a cooperative puzzle
+const (
+	coopState    = "state"
+	coopJoin     = "join"
+	coopLeave    = "leave"
+	coopCursor   = "cursor"
+	coopSet      = "set"
+	coopConflict = "conflict"
+	coopError    = "error"
+)
+
+// coopSendBuffer is the number of messages queued for a player before they are disconnected for falling behind
+const coopSendBuffer = 64
+
+var (
+	errInvalidCoopMessage = errors.New("Message type must be one of 'cursor' or 'set'")
+	errMissingCoopValue   = errors.New("Message must have defined property 'value'")
+)
+
+// coopMessage is a message of the cooperative protocol
+// Players send cursor messages with a cell, and set messages with a cell, the value to write and the value they saw
+// in the cell before. The server sends the state of the puzzle on joining, and relays moves, cursors, joins and leaves
+type coopMessage struct {
+	Type     string          `json:"type"`
+	UserID   uint32          `json:"user_id,omitempty"`
+	BoardRow int             `json:"board_row,omitempty"`
+	BoardCol int             `json:"board_col,omitempty"`
+	Value    *int            `json:"value,omitempty"`
+	Previous *int            `json:"previous,omitempty"`
+	Grid     string          `json:"grid,omitempty"`
+	Givens   string          `json:"givens,omitempty"`
+	Users    []uint32        `json:"users,omitempty"`
+	Session  *models.Session `json:"session,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// coopPlayer is a user connected to a cooperative puzzle
+// Messages to the player are queued on send and written by a single goroutine, so a slow player cannot block the room
+type coopPlayer struct {
+	userID uint32
+	conn   *websocket.Conn
+	send   chan []byte
+}
+
+// coopRoom is the set of players connected to a puzzle
+// mu serialises writes to the boards of the puzzle, so that every write sees the result of the one before it
+type coopRoom struct {
+	puzzle  models.Puzzle
+	rules   sudoku.Rules
+	mu      sync.Mutex
+	players map[*coopPlayer]bool
+}
+
+// coopRooms holds the rooms of the puzzles that have players connected
+var coopRooms = struct {
+	sync.Mutex
+	rooms map[uint32]*coopRoom
+}{rooms: map[uint32]*coopRoom{}}
+
+// CoopPuzzle connects the user to a puzzle by id over a WebSocket, to solve it together with other users
+// The owner of the puzzle can always connect, and other users can connect with the share code of the puzzle
+func CoopPuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID to check that the puzzle belongs to uid, or FindByShareCode with the code query parameter
+		   to check that it is shared, and read the rules of its variants, return status code 400 if err
+		5. Upgrade the connection to a WebSocket, return status code 400 if it is not a WebSocket handshake
+		6. Join the room of the puzzle, send the state of the puzzle and close the connection to the DB
+		7. Apply the messages of the player until they disconnect, connecting to the DB for every move, then leave
+		   the room
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	puzzle, err := findSharedPuzzle(db, uint32(puzzleID), uid, r.URL.Query().Get("code"))
+
+	if err != nil {
+		db.Close()
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		db.Close()
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := websocket.Upgrade(w, r)
+
+	if err != nil {
+		db.Close()
+		return
+	}
+
+	player := &coopPlayer{
+		userID: uid,
+		conn:   conn,
+		send:   make(chan []byte, coopSendBuffer),
+	}
+
+	go player.writeMessages()
+
+	room := joinCoopRoom(db, puzzle, rules, player)
+	defer room.leave(player)
+
+	// The socket can stay open for hours, so the connection is not held for it
+	db.Close()
+
+	for {
+		message := coopMessage{}
+		_, b, err := conn.ReadMessage()
+
+		if err != nil {
+			return
+		}
+
+		if err = json.Unmarshal(b, &message); err != nil {
+			player.queue(coopMessage{Type: coopError, Error: err.Error()})
+			continue
+		}
+
+		switch message.Type {
+		case coopCursor:
+			room.broadcast(coopMessage{Type: coopCursor, UserID: uid, BoardRow: message.BoardRow, BoardCol: message.BoardCol}, player)
+		case coopSet:
+			room.set(player, message)
+		default:
+			player.queue(coopMessage{Type: coopError, Error: errInvalidCoopMessage.Error()})
+		}
+	}
+}
+
+// findSharedPuzzle fetches the puzzle if it belongs to userID, or if it is shared with code
+func findSharedPuzzle(db *gorm.DB, puzzleID uint32, userID uint32, code string) (models.Puzzle, error) {
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzle, err := repo.FindByID(puzzleID, userID)
+
+	if err == nil || code == "" {
+		return puzzle, err
+	}
+
+	shared, err := repo.FindByShareCode(code)
+
+	if err != nil {
+		return models.Puzzle{}, err
+	}
+
+	if shared.ID != puzzleID {
+		return models.Puzzle{}, errors.New("Puzzle not found")
+	}
+
+	return shared, nil
+}
+
+// joinCoopRoom adds player to the room of puzzle, creating the room with the rules of the puzzle if needed, and sends
+// the state of the puzzle read from db to the player and a join message to the other players
+func joinCoopRoom(db *gorm.DB, puzzle models.Puzzle, rules sudoku.Rules, player *coopPlayer) *coopRoom {
+	coopRooms.Lock()
+	room, ok := coopRooms.rooms[puzzle.ID]
+
+	if !ok {
+		room = &coopRoom{puzzle: puzzle, rules: rules, players: map[*coopPlayer]bool{}}
+		coopRooms.rooms[puzzle.ID] = room
+	}
+
+	// Join before releasing coopRooms, so that the room cannot be removed by its last player leaving in between
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	room.players[player] = true
+	coopRooms.Unlock()
+
+	state := coopMessage{Type: coopState, UserID: player.userID}
+
+	for p := range room.players {
+		state.Users = append(state.Users, p.userID)
+	}
+
+	boards, err := crud.BoardsCRUDService.NewBoardsCRUD(db).FindAllByPuzzleID(puzzle.ID)
+
+	if err != nil {
+		state = coopMessage{Type: coopError, Error: err.Error()}
+	} else {
+		grid, givens := coopGrids(room.puzzle, boards)
+		state.Grid = grid.String()
+		state.Givens = givens.String()
+	}
+
+	player.queue(state)
+	room.broadcastLocked(coopMessage{Type: coopJoin, UserID: player.userID}, player)
+
+	return room
+}
+
+// leave removes player from the room and tells the other players, removing the room once it is empty
+func (room *coopRoom) leave(player *coopPlayer) {
+	room.mu.Lock()
+	delete(room.players, player)
+	close(player.send)
+	empty := len(room.players) == 0
+	room.broadcastLocked(coopMessage{Type: coopLeave, UserID: player.userID}, nil)
+	room.mu.Unlock()
+
+	if empty {
+		coopRooms.Lock()
+
+		// Another player may have joined the room since it was found empty
+		room.mu.Lock()
+		if len(room.players) == 0 && coopRooms.rooms[room.puzzle.ID] == room {
+			delete(coopRooms.rooms, room.puzzle.ID)
+		}
+		room.mu.Unlock()
+
+		coopRooms.Unlock()
+	}
+}
+
+// set writes the value of a set message through BoardsCRUD and relays it to every player, including the sender
+// Writes are applied one at a time in the order they reach the server. A write is only applied if the cell still holds
+// the value that the player saw, so of several players writing to the same cell, the first one wins and the others
+// receive a conflict message with the value that won
+func (room *coopRoom) set(player *coopPlayer, message coopMessage) {
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if err != nil {
+		player.queue(coopMessage{Type: coopError, Error: err.Error()})
+		return
+	}
+
+	defer db.Close()
+
+	board := models.Board{
+		BoardRow: message.BoardRow,
+		BoardCol: message.BoardCol,
+		PuzzleID: room.puzzle.ID,
+	}
+
+	if message.Value == nil {
+		player.queue(coopMessage{Type: coopError, Error: errMissingCoopValue.Error()})
+		return
+	}
+
+	board.Value = *message.Value
+	err = board.ValidateBoardSize("update", models.MaxGridSize)
+
+	if err != nil {
+		player.queue(coopMessage{Type: coopError, Error: err.Error()})
+		return
+	}
+
+	// Read the boards again, since they can also be changed outside of the room
+	repo := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	boards, err := repo.FindAllByPuzzleID(room.puzzle.ID)
+
+	if err != nil {
+		player.queue(coopMessage{Type: coopError, Error: err.Error()})
+		return
+	}
+
+	grid, _ := coopGrids(room.puzzle, boards)
+
+	if err = board.ValidateBoardSize("update", grid.Size()); err != nil {
+		player.queue(coopMessage{Type: coopError, Error: err.Error()})
+		return
+	}
+
+	for _, b := range boards {
+		if b.BoardRow == board.BoardRow && b.BoardCol == board.BoardCol && b.Given {
+			player.queue(coopMessage{Type: coopError, BoardRow: board.BoardRow, BoardCol: board.BoardCol, Error: errGivenBoard.Error()})
+			return
+		}
+	}
+
+	if err = checkRaceMove(room.puzzle.ID); err != nil {
+		player.queue(coopMessage{Type: coopError, Error: err.Error()})
+		return
+	}
+
+	current := grid[board.BoardRow-1][board.BoardCol-1]
+
+	if message.Previous == nil || *message.Previous != current {
+		player.queue(coopMessage{Type: coopConflict, BoardRow: board.BoardRow, BoardCol: board.BoardCol, Value: &current})
+		return
+	}
+
+	_, err = repo.Update(room.puzzle.ID, player.userID, board)
+
+	if err != nil {
+		player.queue(coopMessage{Type: coopError, Error: err.Error()})
+		return
+	}
+
+	grid[board.BoardRow-1][board.BoardCol-1] = board.Value
+	session := recordBoardChanges(db, room.puzzle, player.userID, boards, []models.Board{board}, grid, room.rules)
+
+	room.broadcastLocked(coopMessage{
+		Type:     coopSet,
+		UserID:   player.userID,
+		BoardRow: board.BoardRow,
+		BoardCol: board.BoardCol,
+		Value:    &board.Value,
+		Previous: &current,
+		Session:  session,
+	}, nil)
+}
+
+// broadcast sends message to every player in the room except skip, which may be nil
+func (room *coopRoom) broadcast(message coopMessage, skip *coopPlayer) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	room.broadcastLocked(message, skip)
+}
+
+// broadcastLocked is broadcast for callers that already hold room.mu
+func (room *coopRoom) broadcastLocked(message coopMessage, skip *coopPlayer) {
+	for p := range room.players {
+		if p != skip {
+			p.queue(message)
+		}
+	}
+}
+
+// queue adds message to the messages to send to the player, disconnecting the player if too many are queued
+// It must only be called while the player is in a room, since the queue is closed when the player leaves
+func (player *coopPlayer) queue(message coopMessage) {
+	b, err := json.Marshal(message)
+
+	if err != nil {
+		return
+	}
+
+	select {
+	case player.send <- b:
+	default:
+		player.conn.Close()
+	}
+}
+
+// writeMessages writes the queued messages to the player until the player leaves
+func (player *coopPlayer) writeMessages() {
+	for b := range player.send {
+		if err := player.conn.WriteMessage(websocket.TextMessage, b); err != nil {
+			player.conn.Close()
+		}
+	}
+
+	player.conn.WriteClose(websocket.CloseGoingAway)
+	player.conn.Close()
+}
+
+// coopGrids returns the current values and the givens of the boards of puzzle, in grids of the size of the puzzle
+// Boards outside the grid are skipped, and cells without a board are left empty
+func coopGrids(puzzle models.Puzzle, boards []models.Board) (sudoku.Grid, sudoku.Grid) {
+	size := puzzle.GridSize()
+
+	grid := sudoku.NewGrid(size)
+	givens := sudoku.NewGrid(size)
+
+	for _, b := range boards {
+		if b.BoardRow < 1 || b.BoardRow > size || b.BoardCol < 1 || b.BoardCol > size {
+			continue
+		}
+
+		grid[b.BoardRow-1][b.BoardCol-1] = b.Value
+
+		if b.Given {
+			givens[b.BoardRow-1][b.BoardCol-1] = b.Value
+		}
+	}
+
+	return grid, givens
+}
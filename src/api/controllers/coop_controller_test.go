@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+	"github.com/quattad/sudokubuddy-backend/src/api/websocket"
+)
+
+// readCoopMessage reads the next message from conn, failing the test if none arrives within a second
+func readCoopMessage(t *testing.T, conn *websocket.Conn) coopMessage {
+	t.Helper()
+
+	message := coopMessage{}
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conn.ReadJSON(&message)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Error: %s, expected message", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Error: no message received, expected message")
+	}
+
+	return message
+}
+
+// ========== COOPPUZZLE() ========== //
+func TestCoopPuzzleIfConflictingWrites(t *testing.T) {
+	puzzleID := uint32(125)
+	owner := uint32(100)
+	guest := uint32(200)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	// Joining and every move connect to the DB on their own, in this order
+	ownerJoin := tests.CreateSuite()
+	guestJoin := tests.CreateSuite()
+	ownerSet := tests.CreateSuite()
+	guestSet := tests.CreateSuite()
+	suites := []tests.Suite{ownerJoin, guestJoin, ownerSet, guestSet}
+
+	// The owner joins
+	ownerJoin.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, owner).
+		WillReturnRows(ownerJoin.Mock.NewRows([]string{"id", "name", "user_id"}).AddRow(puzzleID, "testpuzzle1", owner))
+	expectPuzzleBoards(ownerJoin, puzzleID, testGivens)
+
+	// The guest joins with the share code
+	guestJoin.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, guest).
+		WillReturnRows(guestJoin.Mock.NewRows([]string{"id"}))
+	guestJoin.Mock.ExpectQuery("SELECT *").
+		WithArgs(code).
+		WillReturnRows(guestJoin.Mock.NewRows([]string{"id", "name", "share_code", "user_id"}).AddRow(puzzleID, "testpuzzle1", code, owner))
+	expectPuzzleBoards(guestJoin, puzzleID, testGivens)
+
+	// The owner writes 4 to (1, 3) first
+	expectPuzzleBoards(ownerSet, puzzleID, testGivens)
+	expectBoardUpdate(ownerSet, puzzleID, owner, 1, 3, 0, 4)
+	ownerSet.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(ownerSet.Mock.NewRows([]string{"id"}))
+
+	// The guest writes 5 to (1, 3) after seeing it empty, and loses
+	expectPuzzleBoardsEntered(guestSet, puzzleID, testGivens, "..4"+strings.Repeat(".", 78))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	connects := 0
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		if connects == len(suites) {
+			t.Errorf("Error: %d connections to the DB, expected %d", connects+1, len(suites))
+			return nil, errors.New("Unexpected connection")
+		}
+
+		connects++
+		return suites[connects-1].DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		if r.URL.Query().Get("token") == "guest" {
+			return guest, nil
+		}
+
+		return owner, nil
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/puzzles/{id}/coop", CoopPuzzle)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/puzzles/125/coop"
+
+	ownerConn, _, err := websocket.Dial(url+"?token=owner", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ownerConn.Close()
+
+	state := readCoopMessage(t, ownerConn)
+
+	if state.Type != coopState || state.Givens != strings.Replace(testGivens, "0", ".", -1) || len(state.Users) != 1 {
+		t.Fatalf("Error: owner received: %+v, expected state of the puzzle", state)
+	}
+
+	guestConn, _, err := websocket.Dial(url+"?token=guest&code="+code, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer guestConn.Close()
+
+	if state = readCoopMessage(t, guestConn); state.Type != coopState || len(state.Users) != 2 {
+		t.Fatalf("Error: guest received: %+v, expected state with 2 users", state)
+	}
+
+	if join := readCoopMessage(t, ownerConn); join.Type != coopJoin || join.UserID != guest {
+		t.Fatalf("Error: owner received: %+v, expected join of the guest", join)
+	}
+
+	// Both players receive the write of the owner
+	if err = ownerConn.WriteMessage(websocket.TextMessage, []byte(`{"type": "set", "board_row": 1, "board_col": 3, "value": 4, "previous": 0}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, conn := range []*websocket.Conn{ownerConn, guestConn} {
+		set := readCoopMessage(t, conn)
+
+		if set.Type != coopSet || set.UserID != owner || set.BoardRow != 1 || set.BoardCol != 3 || set.Value == nil || *set.Value != 4 {
+			t.Fatalf("Error: player received: %+v, expected 4 at (1, 3) from the owner", set)
+		}
+	}
+
+	// The stale write of the guest is refused with the value that won
+	if err = guestConn.WriteMessage(websocket.TextMessage, []byte(`{"type": "set", "board_row": 1, "board_col": 3, "value": 5, "previous": 0}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if conflict := readCoopMessage(t, guestConn); conflict.Type != coopConflict || conflict.Value == nil || *conflict.Value != 4 {
+		t.Fatalf("Error: guest received: %+v, expected conflict with 4", conflict)
+	}
+
+	// Cursors are relayed to the other players
+	if err = guestConn.WriteMessage(websocket.TextMessage, []byte(`{"type": "cursor", "board_row": 2, "board_col": 2}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if cursor := readCoopMessage(t, ownerConn); cursor.Type != coopCursor || cursor.UserID != guest || cursor.BoardRow != 2 || cursor.BoardCol != 2 {
+		t.Fatalf("Error: owner received: %+v, expected cursor of the guest at (2, 2)", cursor)
+	}
+
+	// ensure all expectations have been met
+	for _, s := range suites {
+		if err = s.Mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectation error: %s", err)
+		}
+	}
+}
+
+func TestCoopPuzzleIfNotShared(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(125)
+	guest := uint32(200)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, guest).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return guest, nil
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/puzzles/{id}/coop", CoopPuzzle)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	_, resp, err := websocket.Dial(server.URL+"/puzzles/125/coop", nil)
+
+	if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Error: handshake returned %v, expected status code: %v", err, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/patrickmn/go-cache"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+var (
+	errInvalidDailyDate = errors.New("Daily puzzle date must be formatted as YYYY-MM-DD")
+	errFutureDailyDate  = errors.New("Daily puzzle is not available yet")
+	errDailyAssist      = errors.New("Daily puzzles cannot be solved or hinted")
+)
+
+// dailyPuzzle is the puzzle of the day for a date, with the copy of the user if they have started it
+type dailyPuzzle struct {
+	Date       string         `json:"date"`
+	Difficulty string         `json:"difficulty"`
+	Score      int            `json:"score"`
+	Grid       string         `json:"grid"`
+	Puzzle     *models.Puzzle `json:"puzzle"`
+}
+
+// GetDailyPuzzle fetches the puzzle of the day for the date in the route variables, or for today if there is none
+func GetDailyPuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Parse date from route variables, return status code 400 if it is invalid or in the future
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Generate the daily puzzle of the date, return status code 500 if err
+		4. Connect to the DB, return status code 500 if err
+		5. Execute FindByDaily to fetch the copy of the user, return status code 400 if err
+		6. Return status 200 with the daily puzzle, and the copy of the user if they have started it
+	*/
+
+	date, err := parseDailyDate(r, time.Now())
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	daily, err := findDailyPuzzle(date)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzle, err := repo.FindByDaily(uid, daily.Date)
+
+	if err == nil {
+		daily.Puzzle = &puzzle
+	} else if !gorm.IsRecordNotFoundError(err) {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, daily)
+}
+
+// StartDailyPuzzle copies the puzzle of the day for the date in the route variables, or for today if there is none,
+// into a puzzle of the user, or returns the copy that the user already started
+func StartDailyPuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Parse date from route variables, return status code 400 if it is invalid or in the future
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Generate the daily puzzle of the date, return status code 500 if err
+		4. Connect to the DB, return status code 500 if err
+		5. Return status 200 with the copy of the user if they have started the daily puzzle
+		6. Otherwise save a copy of the givens for the user, return status code 422 if err
+		7. Return status 201 with the copy
+	*/
+
+	date, err := parseDailyDate(r, time.Now())
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	daily, err := findDailyPuzzle(date)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzle, err := repo.FindByDaily(uid, daily.Date)
+
+	if err == nil {
+		responses.JSON(w, http.StatusOK, puzzle)
+		return
+	}
+
+	if !gorm.IsRecordNotFoundError(err) {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	givens, err := sudoku.Parse(daily.Grid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, seed := sudoku.Daily(date)
+
+	// Names are unique, so each copy is named after the date and its user
+	puzzle = models.Puzzle{
+		Name:       fmt.Sprintf("%s #%d", date.Format("20060102"), uid),
+		Difficulty: daily.Difficulty,
+		Score:      daily.Score,
+		Seed:       seed,
+		Daily:      daily.Date,
+		UserID:     uid,
+		Givens:     givens,
+	}
+
+	puzzle.PreparePuzzle()
+	err = puzzle.ValidatePuzzle("")
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	puzzle, err = repo.Save(puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	invalidatePuzzleLists(uid)
+
+	w.Header().Set("Location", fmt.Sprintf("%s/puzzles/%d", r.Host, puzzle.ID))
+	responses.JSON(w, http.StatusCreated, puzzle)
+}
+
+// parseDailyDate returns the date in the route variables as midnight UTC, or today if there is none
+// Dates after today are rejected, so that daily puzzles cannot be played ahead of time
+func parseDailyDate(r *http.Request, now time.Time) (time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	routeVariables := mux.Vars(r)
+
+	if routeVariables["date"] == "" {
+		return today, nil
+	}
+
+	date, err := time.Parse(sudoku.DailyDateFormat, routeVariables["date"])
+
+	if err != nil {
+		return date, errInvalidDailyDate
+	}
+
+	if date.After(today) {
+		return date, errFutureDailyDate
+	}
+
+	return date, nil
+}
+
+// findDailyPuzzle generates the daily puzzle of date, or fetches it from the cache since generation is slow
+func findDailyPuzzle(date time.Time) (dailyPuzzle, error) {
+	daily := dailyPuzzle{}
+	cacheString := "daily/" + date.Format(sudoku.DailyDateFormat)
+
+	if it, found := caching.Cache.Get(cacheString); found {
+		err := json.Unmarshal(it.([]byte), &daily)
+		return daily, err
+	}
+
+	difficulty, seed := sudoku.Daily(date)
+	givens, err := sudoku.Generate(difficulty, seed)
+
+	if err != nil {
+		return daily, err
+	}
+
+	grade, err := sudoku.Rate(givens)
+
+	if err != nil {
+		return daily, err
+	}
+
+	daily = dailyPuzzle{
+		Date:       date.Format(sudoku.DailyDateFormat),
+		Difficulty: string(grade.Difficulty),
+		Score:      grade.Score,
+		Grid:       givens.String(),
+	}
+
+	b, err := json.Marshal(daily)
+
+	if err != nil {
+		return daily, err
+	}
+
+	// GOCACHE
+	caching.Cache.Set(cacheString, b, cache.DefaultExpiration)
+
+	return daily, nil
+}
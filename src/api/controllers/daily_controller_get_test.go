@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== GETDAILYPUZZLE() ========== //
+func TestGetDailyPuzzleIfNotStarted(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusOK
+	uid := uint32(100)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(uid, "2020-01-06").
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/daily/2020-01-06", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"date": "2020-01-06",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetDailyPuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	daily := dailyPuzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &daily); err != nil {
+		t.Fatal(err)
+	}
+
+	if daily.Date != "2020-01-06" || daily.Puzzle != nil {
+		t.Errorf("Error: handler returned daily puzzle: %s, expected 2020-01-06 without a copy", rr.Body.String())
+	}
+
+	// The daily puzzle is the same on every request
+	date, err := time.Parse(sudoku.DailyDateFormat, "2020-01-06")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	givens, err := sudoku.Generate(sudoku.Daily(date))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if daily.Grid != givens.String() {
+		t.Errorf("Error: handler returned grid: %s, expected: %s", daily.Grid, givens.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
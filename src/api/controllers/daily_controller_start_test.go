@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== STARTDAILYPUZZLE() ========== //
+func TestStartDailyPuzzleIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+
+	// The user has not started the daily puzzle yet
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(uid, "2020-01-06").
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// 6 January 2020 is a Monday, which has an easy daily puzzle
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs("20200106 #100", "easy", sqlmock.AnyArg(), 20200106, "2020-01-06", nil, 0, 9, 3, 3, "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	for i := 0; i < 81; i++ {
+		s.Mock.ExpectBegin()
+		s.Mock.ExpectExec("INSERT INTO").
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+		s.Mock.ExpectCommit()
+	}
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/daily/2020-01-06", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"date": "2020-01-06",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartDailyPuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	puzzle := models.Puzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &puzzle); err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.Daily != "2020-01-06" || puzzle.UserID != uid {
+		t.Errorf("Error: handler returned puzzle: %s, expected the daily puzzle of 2020-01-06 for user %d", rr.Body.String(), uid)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestStartDailyPuzzleIfStarted(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusOK
+	uid := uint32(100)
+
+	// The copy of the user is returned instead of a new one
+	rows := s.Mock.NewRows([]string{"id", "name", "daily", "user_id"}).
+		AddRow(125, "20200106 #100", "2020-01-06", uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(uid, "2020-01-06").
+		WillReturnRows(rows)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/daily/2020-01-06", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"date": "2020-01-06",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartDailyPuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	puzzle := models.Puzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &puzzle); err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.ID != 125 {
+		t.Errorf("Error: handler returned puzzle: %s, expected puzzle 125", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestStartDailyPuzzleIfInvalidDate(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+
+	for _, date := range []string{"06-01-2020", "2020-02-30", tomorrow} {
+		// Build request and response objects
+		req, err := http.NewRequest("POST", "/daily/"+date, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req = mux.SetURLVars(req, map[string]string{
+			"date": date,
+		})
+
+		rr := httptest.NewRecorder()
+
+		// Execute function to be tested
+		StartDailyPuzzle(rr, req)
+
+		// Check status code
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("Error: handler returned status code: %v for %s, expected: %v", status, date, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/events"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+)
+
+// Types of the events published to the subscribers of a puzzle
+const (
+	puzzleBoardEvent  = "board"
+	puzzleRenameEvent = "rename"
+	puzzleDeleteEvent = "delete"
+)
+
+// puzzleChange is the data of an event published to the subscribers of a puzzle
+// Board events hold the cell and its new value, and rename events hold the new name
+type puzzleChange struct {
+	PuzzleID uint32 `json:"puzzle_id"`
+	UserID   uint32 `json:"user_id"`
+	Name     string `json:"name,omitempty"`
+	BoardRow int    `json:"board_row,omitempty"`
+	BoardCol int    `json:"board_col,omitempty"`
+	Value    *int   `json:"value,omitempty"`
+}
+
+// GetPuzzleEvents streams the changes of a puzzle by id as Server-Sent Events, for clients that cannot use
+// CoopPuzzle. The owner can subscribe, and so can anyone with the share code of the puzzle in the code query parameter
+// The stream ends after the delete event of the puzzle
+func GetPuzzleEvents(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Check that the puzzle belongs to uid or is shared with the code, return status code 400 if err
+		5. Subscribe to the changes of the puzzle and stream them until the client disconnects, return status code 500
+		   if the response cannot stream, and only log errors once the stream has started
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, err = findSharedPuzzle(db, uint32(puzzleID), uid, r.URL.Query().Get("code"))
+
+	// The stream can stay open for hours, so the connection is not held for it
+	db.Close()
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ch, unsubscribe := events.Events.Subscribe(puzzleTopic(uint32(puzzleID)))
+	defer unsubscribe()
+
+	err = events.Stream(w, r, ch, nil)
+
+	// Once the stream has started, its status code is sent and the error can only be logged
+	switch {
+	case err == events.ErrNotFlushable:
+		responses.ERROR(w, http.StatusInternalServerError, err)
+	case err != nil:
+		log.Println(err)
+	}
+}
+
+// publishPuzzleChange invalidates the cached puzzle and boards and sends change to the subscribers of the puzzle
+// Renames and deletions also invalidate the cached puzzle lists of the owner, and a deletion ends the streams of the puzzle
+func publishPuzzleChange(eventType string, change puzzleChange) {
+	invalidatePuzzleBoards(change.PuzzleID)
+
+	if eventType != puzzleBoardEvent {
+		invalidatePuzzleLists(change.UserID)
+	}
+
+	topic := puzzleTopic(change.PuzzleID)
+	events.Events.Publish(topic, events.Event{Type: eventType, Data: change})
+
+	if eventType == puzzleDeleteEvent {
+		events.Events.Close(topic)
+	}
+}
+
+// invalidatePuzzleBoards deletes the cached puzzle, which includes its boards, and the cached boards. The boards are
+// cached by id and by position as well as in the list of all boards, so all of them are deleted
+func invalidatePuzzleBoards(puzzleID uint32) {
+	caching.Cache.Delete("puzzles/" + strconv.Itoa(int(puzzleID)))
+	caching.DeletePrefix("boards/")
+}
+
+// puzzleTopic is the topic of the changes of a puzzle
+func puzzleTopic(puzzleID uint32) string {
+	return "puzzles/" + strconv.Itoa(int(puzzleID))
+}
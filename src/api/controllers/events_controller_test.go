@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/patrickmn/go-cache"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// readEvent reads the next Server-Sent Event from br into data and returns its type, failing the test if
+// none arrives within a second
+func readEvent(t *testing.T, br *bufio.Reader, data interface{}) string {
+	t.Helper()
+
+	type line struct {
+		text string
+		err  error
+	}
+
+	eventType := ""
+
+	for {
+		done := make(chan line, 1)
+
+		go func() {
+			text, err := br.ReadString('\n')
+			done <- line{text, err}
+		}()
+
+		select {
+		case l := <-done:
+			if l.err != nil {
+				t.Fatalf("Error: %s, expected event", l.err)
+			}
+
+			if strings.HasPrefix(l.text, "event: ") {
+				eventType = strings.TrimSpace(strings.TrimPrefix(l.text, "event: "))
+			}
+
+			if strings.HasPrefix(l.text, "data: ") {
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(l.text, "data: ")), data); err != nil {
+					t.Fatal(err)
+				}
+
+				return eventType
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Error: no event received, expected event")
+		}
+	}
+}
+
+// ========== GETPUZZLEEVENTS() ========== //
+func TestGetPuzzleEventsIfRenamedAndDeleted(t *testing.T) {
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	// Each request closes its connection, so each one gets its own
+	subscribe := tests.CreateSuite()
+	subscribe.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(subscribe.Mock.NewRows([]string{"id", "name", "user_id"}).AddRow(puzzleID, "testpuzzle1", uid))
+
+	rename := tests.CreateSuite()
+	rename.Mock.ExpectBegin()
+	rename.Mock.ExpectExec("UPDATE").
+		WithArgs("renamed", sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	rename.Mock.ExpectCommit()
+
+	remove := tests.CreateSuite()
+	remove.Mock.ExpectBegin()
+	remove.Mock.ExpectExec("DELETE").
+		WithArgs(puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	remove.Mock.ExpectCommit()
+
+	connections := []*gorm.DB{subscribe.DB, rename.DB, remove.DB}
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		db := connections[0]
+		connections = connections[1:]
+		return db, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/puzzles/{id}/events", GetPuzzleEvents).Methods(http.MethodGet)
+	router.HandleFunc("/puzzles/{id}", UpdatePuzzle).Methods(http.MethodPut)
+	router.HandleFunc("/puzzles/{id}", DeletePuzzle).Methods(http.MethodDelete)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/puzzles/%d/events", server.URL, puzzleID))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+	br := bufio.NewReader(resp.Body)
+
+	// The rename invalidates the cached puzzle, its cached boards and the cached puzzle lists
+	caching.Cache.Set("puzzles/125", []byte("{}"), cache.DefaultExpiration)
+	caching.Cache.Set("puzzles/all/100", []byte("[]"), cache.DefaultExpiration)
+	caching.Cache.Set("puzzles/all/100/easy", []byte("[]"), cache.DefaultExpiration)
+	caching.Cache.Set("boards/3", []byte("{}"), cache.DefaultExpiration)
+	caching.Cache.Set("boards/12513", []byte("{}"), cache.DefaultExpiration)
+	caching.Cache.Set("boards/all", []byte("[]"), cache.DefaultExpiration)
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/puzzles/%d", server.URL, puzzleID), bytes.NewBuffer([]byte(`{"name": "renamed"}`)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changed, err := http.DefaultClient.Do(req)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changed.Body.Close()
+
+		if changed.StatusCode != http.StatusOK {
+			t.Fatalf("Error: %s returned status code: %v, expected: %v", method, changed.StatusCode, http.StatusOK)
+		}
+	}
+
+	change := puzzleChange{}
+
+	if eventType := readEvent(t, br, &change); eventType != puzzleRenameEvent || change.PuzzleID != puzzleID || change.Name != "renamed" {
+		t.Errorf("Error: received %s event: %+v, expected rename to renamed", eventType, change)
+	}
+
+	for _, key := range []string{"puzzles/125", "puzzles/all/100", "puzzles/all/100/easy", "boards/3", "boards/12513", "boards/all"} {
+		if _, found := caching.Cache.Get(key); found {
+			t.Errorf("Error: cached %s was not invalidated", key)
+		}
+	}
+
+	if eventType := readEvent(t, br, &change); eventType != puzzleDeleteEvent || change.PuzzleID != puzzleID {
+		t.Errorf("Error: received %s event: %+v, expected deletion", eventType, change)
+	}
+
+	// The stream ends with the puzzle
+	if rest, err := ioutil.ReadAll(br); err != nil || strings.TrimSpace(string(rest)) != "" {
+		t.Errorf("Error: %v, received %q, expected the stream to end", err, rest)
+	}
+
+	// ensure all expectations have been met
+	for _, s := range []tests.Suite{subscribe, rename, remove} {
+		if err = s.Mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectation error: %s", err)
+		}
+	}
+}
+
+func TestGetPuzzleEventsIfNotShared(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusBadRequest
+	guest := uint32(200)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(125, guest).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return guest, nil
+	}
+
+	req, err := http.NewRequest("GET", "/puzzles/125/events", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{"id": "125"})
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzleEvents(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/patrickmn/go-cache"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// Number of entries returned by a leaderboard when the request does not set a limit, and the largest limit allowed
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// leaderboardsCachePrefix prefixes the cache keys of all leaderboards, which are invalidated together when a
+// session is completed
+const leaderboardsCachePrefix = "leaderboards/"
+
+var errInvalidLimit = fmt.Errorf("Leaderboard limit must be between 1 and %d", maxLeaderboardLimit)
+
+// GetPuzzleLeaderboard fetches the users who solved a puzzle by id, ranked by their fastest solve time
+// Users solve their own copies, so the puzzle ranks with the clones of the same shared puzzle and the copies of the same
+// daily puzzle
+func GetPuzzleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Parse window and limit from the query, return status code 400 if err
+		3. Return status 200 with the cached leaderboard, if any
+		4. Connect to the DB, return status code 500 if err
+		5. Execute FindByPuzzleID, return status code 400 if err
+		6. Rank the entries, with equal best times sharing a rank
+		7. Cache the leaderboard and return status 200 with it
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	since, limit, err := parseLeaderboardQuery(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cacheString := fmt.Sprintf("%spuzzles/%d/%d/%d", leaderboardsCachePrefix, puzzleID, since.Unix(), limit)
+
+	getLeaderboard(w, cacheString, func(repo *crud.LeaderboardsCRUD) ([]models.LeaderboardEntry, error) {
+		entries, err := repo.FindByPuzzleID(uint32(puzzleID), since, limit)
+
+		return models.RankLeaderboard(entries, func(a, b models.LeaderboardEntry) bool {
+			return a.BestMillis == b.BestMillis
+		}), err
+	})
+}
+
+// GetDailyLeaderboard fetches the users who solved the daily puzzle of a date, ranked by their fastest solve time
+func GetDailyLeaderboard(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Parse date from route variables, return status code 400 if it is invalid or in the future
+		2. Parse limit from the query, return status code 400 if err
+		3. Return status 200 with the cached leaderboard, if any
+		4. Connect to the DB, return status code 500 if err
+		5. Execute FindByDaily, return status code 400 if err
+		6. Rank the entries, with equal best times sharing a rank
+		7. Cache the leaderboard and return status 200 with it
+	*/
+
+	date, err := parseDailyDate(r, time.Now())
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	_, limit, err := parseLeaderboardQuery(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	day := date.Format(sudoku.DailyDateFormat)
+	cacheString := fmt.Sprintf("%sdaily/%s/%d", leaderboardsCachePrefix, day, limit)
+
+	getLeaderboard(w, cacheString, func(repo *crud.LeaderboardsCRUD) ([]models.LeaderboardEntry, error) {
+		entries, err := repo.FindByDaily(day, limit)
+
+		return models.RankLeaderboard(entries, func(a, b models.LeaderboardEntry) bool {
+			return a.BestMillis == b.BestMillis
+		}), err
+	})
+}
+
+// GetGlobalLeaderboard fetches the users who solved the most puzzles, optionally of a difficulty, ranked by
+// the number of puzzles solved and then by their average solve time
+func GetGlobalLeaderboard(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Parse window, difficulty and limit from the query, return status code 400 if err
+		2. Return status 200 with the cached leaderboard, if any
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindAll, return status code 400 if err
+		5. Rank the entries, with equal counts and average times sharing a rank
+		6. Cache the leaderboard and return status 200 with it
+	*/
+
+	since, limit, err := parseLeaderboardQuery(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var difficulty sudoku.Difficulty
+
+	if d := r.URL.Query().Get("difficulty"); d != "" {
+		difficulty, err = sudoku.ParseDifficulty(d)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	cacheString := fmt.Sprintf("%sglobal/%d/%s/%d", leaderboardsCachePrefix, since.Unix(), difficulty, limit)
+
+	getLeaderboard(w, cacheString, func(repo *crud.LeaderboardsCRUD) ([]models.LeaderboardEntry, error) {
+		entries, err := repo.FindAll(since, string(difficulty), limit)
+
+		return models.RankLeaderboard(entries, func(a, b models.LeaderboardEntry) bool {
+			return a.Solved == b.Solved && a.AverageMillis == b.AverageMillis
+		}), err
+	})
+}
+
+// getLeaderboard responds with the leaderboard cached under cacheString, or fetches it with find and caches it
+func getLeaderboard(w http.ResponseWriter, cacheString string, find func(*crud.LeaderboardsCRUD) ([]models.LeaderboardEntry, error)) {
+	if it, found := caching.Cache.Get(cacheString); found {
+		var entries []models.LeaderboardEntry
+		err := json.Unmarshal(it.([]byte), &entries)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		responses.JSON(w, http.StatusOK, entries)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	entries, err := find(crud.LeaderboardsCRUDService.NewLeaderboardsCRUD(db))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	b, err := json.Marshal(entries)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// GOCACHE
+	caching.Cache.Set(cacheString, b, cache.DefaultExpiration)
+
+	responses.JSON(w, http.StatusOK, entries)
+}
+
+// parseLeaderboardQuery returns the start of the window and the limit of a leaderboard request
+// The window defaults to all time and the limit defaults to defaultLeaderboardLimit
+func parseLeaderboardQuery(r *http.Request) (time.Time, int, error) {
+	q := r.URL.Query()
+	since, err := models.WindowStart(q.Get("window"), time.Now())
+
+	if err != nil {
+		return since, 0, err
+	}
+
+	limit := defaultLeaderboardLimit
+
+	if l := q.Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+
+		if err != nil || limit < 1 || limit > maxLeaderboardLimit {
+			return since, 0, errInvalidLimit
+		}
+	}
+
+	return since, limit, nil
+}
+
+// invalidateLeaderboards removes all cached leaderboards, so that a completed session is ranked on the next request
+func invalidateLeaderboards() {
+	caching.DeletePrefix(leaderboardsCachePrefix)
+}
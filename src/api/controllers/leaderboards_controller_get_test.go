@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== GETPUZZLELEADERBOARD() ========== //
+func TestGetPuzzleLeaderboardIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(125)
+
+	caching.DeletePrefix(leaderboardsCachePrefix)
+
+	rows := s.Mock.NewRows([]string{"user_id", "username", "solved", "best_millis", "average_millis"}).
+		AddRow(100, "johndoe", 2, 61000, 75500).
+		AddRow(101, "janedoe", 1, 61000, 61000).
+		AddRow(102, "jimdoe", 3, 90000, 95000)
+
+	// The window is all time, so the puzzle id is the only argument
+	s.Mock.ExpectQuery("SELECT sessions.user_id, users.username").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	// The second request is served from the cache without querying the DB
+	for i := 0; i < 2; i++ {
+		// Build request and response objects
+		req, err := http.NewRequest("GET", "/leaderboards/puzzles/125?window=all&limit=3", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req = mux.SetURLVars(req, map[string]string{
+			"id": strconv.Itoa(int(puzzleID)),
+		})
+
+		rr := httptest.NewRecorder()
+
+		// Execute function to be tested
+		GetPuzzleLeaderboard(rr, req)
+
+		// Check status code and body
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+		}
+
+		entries := []models.LeaderboardEntry{}
+
+		if err = json.Unmarshal(rr.Body.Bytes(), &entries); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 3 || entries[0].Rank != 1 || entries[1].Rank != 1 || entries[2].Rank != 3 {
+			t.Errorf("Error: handler returned leaderboard: %s, expected ranks 1, 1, 3", rr.Body.String())
+		}
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== GETGLOBALLEADERBOARD() ========== //
+func TestGetGlobalLeaderboardIfInvalidQuery(t *testing.T) {
+	queries := []string{"window=month", "limit=0", "limit=101", "difficulty=impossible"}
+
+	for _, query := range queries {
+		// Build request and response objects
+		req, err := http.NewRequest("GET", "/leaderboards/global?"+query, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+
+		// Execute function to be tested
+		GetGlobalLeaderboard(rr, req)
+
+		// Check status code
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("Error: handler returned status code: %v for %s, expected: %v", status, query, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// errInvalidReplayFormat is returned when a replay is requested in an unsupported format
+var errInvalidReplayFormat = errors.New("Replay format must be one of 'json' or 'text'")
+
+// UndoMove reverts the latest move of a puzzle by id that is in effect
+func UndoMove(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Fetch the puzzle owned by uid, its boards and its rules, return status code 400 if err
+		5. Check that the puzzle is not in a race that has not started or is finished, return status code 409 if it is
+		6. Execute Undo, return status code 409 if there is no move to undo, or 403 if its board is now a given
+		7. Publish the reverted board, count it in the game session and race of the puzzle
+		8. Return status 200 with the undo move appended to the move log
+	*/
+
+	revertMove(w, r, models.MoveUndo)
+}
+
+// RedoMove repeats the latest undone move of a puzzle by id
+func RedoMove(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Fetch the puzzle owned by uid, its boards and its rules, return status code 400 if err
+		5. Check that the puzzle is not in a race that has not started or is finished, return status code 409 if it is
+		6. Execute Redo, return status code 409 if there is no move to redo, or 403 if its board is now a given
+		7. Publish the reverted board, count it in the game session and race of the puzzle
+		8. Return status 200 with the redo move appended to the move log
+	*/
+
+	revertMove(w, r, models.MoveRedo)
+}
+
+// revertMove undoes or redoes a move of the puzzle in the route variables, depending on kind
+func revertMove(w http.ResponseWriter, r *http.Request, kind string) {
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Boards of a race can only be changed while the race is on
+	err = checkRaceMove(puzzle.ID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	grid, err := sudoku.FromBoards(puzzle, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repo := crud.BoardsCRUDService.NewBoardsCRUD(db)
+
+	var move models.Move
+
+	if kind == models.MoveUndo {
+		move, err = repo.Undo(uint32(puzzleID), uid)
+	} else {
+		move, err = repo.Redo(uint32(puzzleID), uid)
+	}
+
+	if err == crud.ErrNothingToUndo || err == crud.ErrNothingToRedo {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	if err == crud.ErrBoardGiven {
+		responses.ERROR(w, http.StatusForbidden, err)
+		return
+	}
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The reverted board goes through the same hooks as any other change of a board
+	board := models.Board{
+		BoardRow: move.BoardRow,
+		BoardCol: move.BoardCol,
+		Value:    move.NewValue,
+		PuzzleID: puzzle.ID,
+	}
+
+	grid[board.BoardRow-1][board.BoardCol-1] = board.Value
+	recordBoardChanges(db, puzzle, uid, boards, []models.Board{board}, grid, rules)
+
+	responses.JSON(w, http.StatusOK, move)
+}
+
+// replay is the response body of GetReplay
+// Start is the grid of givens that the moves are applied to, written as a grid string
+type replay struct {
+	PuzzleID uint32       `json:"puzzle_id"`
+	Start    string       `json:"start"`
+	Steps    []replayStep `json:"steps"`
+}
+
+// replayStep is a move of a replay, made OffsetMillis after the first move
+// Grid is the state of the grid after the move, which is only included if requested
+type replayStep struct {
+	models.Move
+	OffsetMillis int64  `json:"offset_millis"`
+	Grid         string `json:"grid,omitempty"`
+}
+
+// GetReplay fetches the move log of a puzzle by id as a time-ordered replay from its givens
+// The format query parameter selects 'json', the default, or the compact 'text' format, and states=true
+// includes the grid after every move in the json format
+func GetReplay(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Parse the format and states query parameters, return status code 400 if err
+		3. Get uid (userID) from request, if not authorized, return status code 401
+		4. Connect to the DB, return status code 500 if err
+		5. Fetch the puzzle owned by uid and its boards, return status code 400 if err
+		6. Execute FindAllByPuzzleID on the move log, return status code 400 if err
+		7. Return status 200 with the replay
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+
+	if format != "" && format != "json" && format != "text" {
+		responses.ERROR(w, http.StatusBadRequest, errInvalidReplayFormat)
+		return
+	}
+
+	states := false
+
+	if q.Get("states") != "" {
+		states, err = strconv.ParseBool(q.Get("states"))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repo := crud.MovesCRUDService.NewMovesCRUD(db)
+	moves, err := repo.FindAllByPuzzleID(uint32(puzzleID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	start, err := sudoku.GivensFromBoards(puzzle, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rp := buildReplay(uint32(puzzleID), start, moves, states)
+
+	if format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(rp.Text()))
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, rp)
+}
+
+// buildReplay applies moves in order to a copy of start, recording the grid after each move if states is true
+func buildReplay(puzzleID uint32, start sudoku.Grid, moves []models.Move, states bool) replay {
+	rp := replay{
+		PuzzleID: puzzleID,
+		Start:    start.String(),
+		Steps:    []replayStep{},
+	}
+
+	grid := start.Clone()
+
+	for _, move := range moves {
+		step := replayStep{Move: move}
+
+		if len(rp.Steps) > 0 {
+			step.OffsetMillis = move.CreatedAt.Sub(moves[0].CreatedAt).Nanoseconds() / 1e6
+		}
+
+		if move.BoardRow >= 1 && move.BoardRow <= grid.Size() && move.BoardCol >= 1 && move.BoardCol <= grid.Size() {
+			grid[move.BoardRow-1][move.BoardCol-1] = move.NewValue
+		}
+
+		if states {
+			step.Grid = grid.String()
+		}
+
+		rp.Steps = append(rp.Steps, step)
+	}
+
+	return rp
+}
+
+// Text writes the replay in the compact text format, which holds the start grid on the first line followed by one
+// line per move of the offset in milliseconds, the row, column and new value as three characters, and the kind of
+// the move if it is an undo or redo, e.g. "1520 134" or "3000 134 undo". Numbers above 9 are written as letters
+// as in grid strings, e.g. "1520 AG0" for clearing (10, 16)
+func (rp replay) Text() string {
+	var b strings.Builder
+
+	b.WriteString(rp.Start + "\n")
+
+	for _, step := range rp.Steps {
+		fmt.Fprintf(&b, "%d %c%c%c", step.OffsetMillis, replaySymbol(step.BoardRow), replaySymbol(step.BoardCol),
+			replaySymbol(step.NewValue))
+
+		if step.Kind != models.MoveSet {
+			b.WriteString(" " + step.Kind)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// replaySymbol returns the character of a row, column or value in the text format, with '0' for an empty value
+func replaySymbol(n int) byte {
+	if n == 0 {
+		return '0'
+	}
+
+	return models.ValueSymbol(n)
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// expectPuzzleMoves mocks the move log of a puzzle with a move at (1, 3), followed 1.5 seconds later by its undo
+func expectPuzzleMoves(s tests.Suite, puzzleID uint32, uid uint32) {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	rows := s.Mock.NewRows([]string{"id", "kind", "board_row", "board_col", "old_value", "new_value", "ref", "created_at", "puzzle_id", "user_id"}).
+		AddRow(1, models.MoveSet, 1, 3, 0, 4, 0, start, puzzleID, uid).
+		AddRow(2, models.MoveUndo, 1, 3, 4, 0, 1, start.Add(1500*time.Millisecond), puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+}
+
+// ========== GETREPLAY() ========== //
+func TestGetReplayIfSuccessfulText(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+	expectPuzzleMoves(s, puzzleID, uid)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/replay?format=text", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetReplay(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	expected := "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79\n" +
+		"0 134\n" +
+		"1500 130 undo\n"
+
+	if rr.Body.String() != expected {
+		t.Errorf("Error: handler returned replay:\n%s\nexpected:\n%s", rr.Body.String(), expected)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetReplayIfSuccessfulWithStates(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+	expectPuzzleMoves(s, puzzleID, uid)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/replay?states=true", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetReplay(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	response := replay{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Steps) != 2 || response.Steps[0].Grid[2] != '4' || response.Steps[1].Grid != response.Start {
+		t.Errorf("Error: handler returned unexpected replay: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/events"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== UNDOMOVE() ========== //
+func TestUndoMoveIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	// The 4 entered in (1, 3) is the only move in the move log
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoardsEntered(s, puzzleID, testGivens, "..4"+strings.Repeat(".", 78))
+
+	moveRows := s.Mock.NewRows([]string{"id", "kind", "board_row", "board_col", "old_value", "new_value", "puzzle_id", "user_id"}).
+		AddRow(1, models.MoveSet, 1, 3, 0, 4, puzzleID, uid)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(moveRows)
+	s.Mock.ExpectQuery("SELECT (.+) FOR UPDATE").
+		WithArgs(puzzleID, 1, 3).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "puzzle_id"}).AddRow(3, 1, 3, 4, puzzleID))
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), 0, puzzleID, 1, 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs(models.MoveUndo, 1, 3, 4, 0, 1, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	s.Mock.ExpectCommit()
+
+	// The undo is counted like any other move, but no session is in progress
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	ch, unsubscribe := events.Events.Subscribe(puzzleTopic(puzzleID))
+	defer unsubscribe()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/undo", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UndoMove(rr, req)
+
+	// Check status code and the published change
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	select {
+	case event := <-ch:
+		if change := event.Data.(puzzleChange); change.BoardRow != 1 || change.BoardCol != 3 || *change.Value != 0 {
+			t.Errorf("Error: handler published unexpected change: %+v", change)
+		}
+	default:
+		t.Errorf("Error: handler did not publish the reverted board")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUndoMoveIfNothingToUndo(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusConflict
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// The move log of the puzzle is empty
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+	s.Mock.ExpectRollback()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/undo", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UndoMove(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUndoMoveIfBoardGiven(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusForbidden
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	// The 4 entered in (1, 3) became a given after it was moved
+	givens := "534" + testGivens[3:]
+	expectPuzzleWithBoards(s, puzzleID, uid, givens)
+
+	moveRows := s.Mock.NewRows([]string{"id", "kind", "board_row", "board_col", "old_value", "new_value", "puzzle_id", "user_id"}).
+		AddRow(1, models.MoveSet, 1, 3, 0, 4, puzzleID, uid)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(moveRows)
+	s.Mock.ExpectQuery("SELECT (.+) FOR UPDATE").
+		WithArgs(puzzleID, 1, 3).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "given", "puzzle_id"}).AddRow(3, 1, 3, 4, true, puzzleID))
+	s.Mock.ExpectRollback()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/undo", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UndoMove(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure the given was not written
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,398 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+var (
+	// errInvalidNote is returned when a note to toggle is not a value of the grid of the puzzle
+	errInvalidNote = errors.New("Note has invalid value for property 'value'")
+
+	// errInvalidPosition is returned when a board position lies outside the grid of the puzzle
+	errInvalidPosition = errors.New("Board has invalid position")
+)
+
+// SetBoardNotes replaces the candidate notes of the board at the puzzle_id, board_row and board_col query parameters
+func SetBoardNotes(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read board position from query parameters. If err, return status code 400.
+		2. Read notes from request body into models.Board. If err, return status code 422.
+		3. Get uid (userID) from request, if not authorized, return status code 401
+		4. Connect to db. If err, return status code 500.
+		5. Fetch the puzzle owned by uid and its boards. If err, return status code 400.
+		6. Validate notes against the size of the puzzle. If err, return status code 422.
+		7. Find the board and check that it is not a given. If it is, return status code 403.
+		8. Execute update. If successful, return status code 200 and the updated board.
+	*/
+
+	position, err := parseBoardPosition(r, true)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	board := models.Board{}
+	err = json.Unmarshal(body, &board)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, position.PuzzleID, uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	board.PuzzleID = position.PuzzleID
+	board.BoardRow = position.BoardRow
+	board.BoardCol = position.BoardCol
+	err = board.ValidateBoardSize("notes", puzzle.GridSize())
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	current, err := findPuzzleBoard(boards, position)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if current.Given {
+		responses.ERROR(w, http.StatusForbidden, errGivenBoard)
+		return
+	}
+
+	current.Notes = board.Notes
+	_, err = crud.BoardsCRUDService.NewBoardsCRUD(db).UpdateNotes(position.PuzzleID, current)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	invalidatePuzzleBoards(position.PuzzleID)
+
+	responses.JSON(w, http.StatusOK, current)
+}
+
+// ToggleBoardNote adds the note in the value query parameter to the board at the puzzle_id, board_row and
+// board_col query parameters, or removes it if the board already holds it
+func ToggleBoardNote(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read board position and value from query parameters. If err, return status code 400.
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to db. If err, return status code 500.
+		4. Fetch the puzzle owned by uid and its boards. If err, return status code 400.
+		5. Check that the value is from 1 to the size of the puzzle. If not, return status code 422.
+		6. Find the board and check that it is not a given. If it is, return status code 403.
+		7. Toggle the note and execute update. If successful, return status code 200 and the updated board.
+	*/
+
+	position, err := parseBoardPosition(r, true)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	value, err := strconv.Atoi(r.URL.Query().Get("value"))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, position.PuzzleID, uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if value < 1 || value > puzzle.GridSize() {
+		responses.ERROR(w, http.StatusUnprocessableEntity, errInvalidNote)
+		return
+	}
+
+	board, err := findPuzzleBoard(boards, position)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if board.Given {
+		responses.ERROR(w, http.StatusForbidden, errGivenBoard)
+		return
+	}
+
+	board.ToggleNote(value)
+	_, err = crud.BoardsCRUDService.NewBoardsCRUD(db).UpdateNotes(position.PuzzleID, board)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	invalidatePuzzleBoards(position.PuzzleID)
+
+	responses.JSON(w, http.StatusOK, board)
+}
+
+// ClearBoardNotes clears the notes of the board at the board_row and board_col query parameters, or of every
+// board of the puzzle at the puzzle_id query parameter if no position is given
+func ClearBoardNotes(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read puzzle_id and optional board position from query parameters. If err, return status code 400.
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to db. If err, return status code 500.
+		4. Fetch the puzzle owned by uid and its boards, and check that the board position lies in the grid of the
+		   puzzle. If err, return status code 400.
+		5. Execute update on the board, or on all boards of the puzzle. If successful, return status code 200
+		and number of rows updated.
+	*/
+
+	q := r.URL.Query()
+	single := q.Get("board_row") != "" || q.Get("board_col") != ""
+	position, err := parseBoardPosition(r, single)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	_, boards, err := findPuzzleBoards(db, position.PuzzleID, uid)
+
+	if err == nil && single {
+		_, err = findPuzzleBoard(boards, position)
+	}
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repo := crud.BoardsCRUDService.NewBoardsCRUD(db)
+
+	var rows int64
+
+	if single {
+		rows, err = repo.UpdateNotes(position.PuzzleID, position)
+	} else {
+		rows, err = repo.ClearNotes(position.PuzzleID)
+	}
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	invalidatePuzzleBoards(position.PuzzleID)
+
+	responses.JSON(w, http.StatusOK, rows)
+}
+
+// FillBoardNotes replaces the notes of every empty board of the puzzle at the puzzle_id query parameter with the
+// values that do not repeat in its row, column, box or the units of the variants of the puzzle. Filled boards have
+// their notes cleared
+func FillBoardNotes(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read puzzle_id from query parameters. If err, return status code 400.
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to db. If err, return status code 500.
+		4. Fetch the puzzle owned by uid and its boards. If err, return status code 400.
+		5. Fetch the rules of the variants of the puzzle. If err, return status code 400.
+		   Compute the candidates of the grid under those rules. If the grid is invalid, return status code 422.
+		6. Execute update on all boards. If successful, return status code 200 and the updated boards.
+	*/
+
+	position, err := parseBoardPosition(r, false)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, position.PuzzleID, uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	grid, err := sudoku.FromBoards(puzzle, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	candidates, err := rules.Candidates(grid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	for i := range boards {
+		boards[i].Notes = models.FormatNotes(candidates[boards[i].BoardRow-1][boards[i].BoardCol-1])
+	}
+
+	_, err = crud.BoardsCRUDService.NewBoardsCRUD(db).UpdateAllNotes(position.PuzzleID, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	invalidatePuzzleBoards(position.PuzzleID)
+
+	responses.JSON(w, http.StatusOK, boards)
+}
+
+// parseBoardPosition reads the puzzle_id query parameter, and the board_row and board_col query parameters if
+// withCell is true, into an otherwise empty board
+func parseBoardPosition(r *http.Request, withCell bool) (models.Board, error) {
+	q := r.URL.Query()
+	board := models.Board{}
+
+	puzzleID, err := strconv.ParseUint(q.Get("puzzle_id"), 10, 32)
+
+	if err != nil {
+		return models.Board{}, err
+	}
+
+	board.PuzzleID = uint32(puzzleID)
+
+	if !withCell {
+		return board, nil
+	}
+
+	board.BoardRow, err = strconv.Atoi(q.Get("board_row"))
+
+	if err != nil {
+		return models.Board{}, err
+	}
+
+	board.BoardCol, err = strconv.Atoi(q.Get("board_col"))
+
+	if err != nil {
+		return models.Board{}, err
+	}
+
+	// Positions outside of the largest grid have no board to find, whatever the size of the puzzle
+	if board.BoardRow < 1 || board.BoardRow > models.MaxGridSize || board.BoardCol < 1 || board.BoardCol > models.MaxGridSize {
+		return models.Board{}, errInvalidPosition
+	}
+
+	return board, nil
+}
+
+// findPuzzleBoard returns the board of boards at the board_row and board_col of position
+// Returns errInvalidPosition if the position lies outside the grid of the puzzle of boards
+func findPuzzleBoard(boards []models.Board, position models.Board) (models.Board, error) {
+	for _, board := range boards {
+		if board.BoardRow == position.BoardRow && board.BoardCol == position.BoardCol {
+			return board, nil
+		}
+	}
+
+	return models.Board{}, errInvalidPosition
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+func TestFillBoardNotesIfSuccessful(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Notes of all 81 boards are updated within a single transaction
+	s.Mock.ExpectBegin()
+
+	for i := 0; i < 81; i++ {
+		s.Mock.ExpectExec("UPDATE").
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), puzzleID, i/9+1, i%9+1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/boards/notes/fill", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	FillBoardNotes(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	boards := []models.Board{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &boards); err != nil {
+		t.Fatal(err)
+	}
+
+	// (1, 1) holds a given and (1, 3) sees 3, 5, 6, 7, 8 and 9
+	if boards[0].Notes != "" || boards[2].Notes != "124" {
+		t.Errorf("Error: handler returned notes: %q and %q, expected: %q and %q", boards[0].Notes, boards[2].Notes, "", "124")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// setBoardNotes runs SetBoardNotes as uid with body for the board at (1, 3) of puzzleID
+func setBoardNotes(t *testing.T, s tests.Suite, uid uint32, puzzleID uint32, body []byte) *httptest.ResponseRecorder {
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards/notes", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	SetBoardNotes(rr, req)
+
+	return rr
+}
+
+func TestSetBoardNotesIfInvalidNotes(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Notes must be ascending digits from 1 to 9
+	rr := setBoardNotes(t, s, uid, puzzleID, []byte(`{"notes": "931"}`))
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestSetBoardNotesIfLargerThanGrid(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// A of a 16x16 grid is 10, which a 4x4 puzzle cannot hold
+	expectPuzzleWithBoards(s, puzzleID, uid, "1200340000000000")
+
+	rr := setBoardNotes(t, s, uid, puzzleID, []byte(`{"notes": "3A"}`))
+
+	// Check status code and that no notes were written
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/patrickmn/go-cache"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+func TestToggleBoardNoteIfSuccessful(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+	boardRow := 5
+	boardCol := 5
+	expectedNotes := "5"
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// 'UPDATE `boards` SET `notes` = ?, `updated_at` = ?  WHERE (puzzle_id=? AND board_row=? AND board_col=?)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(expectedNotes, sqlmock.AnyArg(), puzzleID, boardRow, boardCol).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/boards/notes/toggle", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", strconv.Itoa(boardRow))
+	q.Add("board_col", strconv.Itoa(boardCol))
+	q.Add("value", "5")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// The notes are cached in the puzzle and in its boards
+	caching.Cache.Set("puzzles/445", []byte("{}"), cache.DefaultExpiration)
+	caching.Cache.Set("boards/44555", []byte("{}"), cache.DefaultExpiration)
+	caching.Cache.Set("boards/all", []byte("[]"), cache.DefaultExpiration)
+
+	// Execute function to be tested
+	ToggleBoardNote(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	board := models.Board{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &board); err != nil {
+		t.Fatal(err)
+	}
+
+	if board.Notes != expectedNotes {
+		t.Errorf("Error: handler returned notes: %v, expected: %v", board.Notes, expectedNotes)
+	}
+
+	for _, key := range []string{"puzzles/445", "boards/44555", "boards/all"} {
+		if _, found := caching.Cache.Get(key); found {
+			t.Errorf("Error: cached %s was not invalidated", key)
+		}
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestToggleBoardNoteIfInvalidValue(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// 12 is a value of larger grids, but not of a 9x9 puzzle
+	for _, value := range []string{"0", "12"} {
+		s := tests.CreateSuite()
+
+		expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+		// Initialize struct with modified interfaces
+		database.DBService = &dbMock{}
+		auth.TokenService = &tokenMock{}
+
+		// Define custom functions
+		mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+			return s.DB, nil
+		}
+
+		mockExtractTokenID = func(r *http.Request) (uint32, error) {
+			return uid, nil
+		}
+
+		req, err := http.NewRequest("PUT", "/boards/notes/toggle", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		q := req.URL.Query()
+		q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+		q.Add("board_row", "1")
+		q.Add("board_col", "3")
+		q.Add("value", value)
+		req.URL.RawQuery = q.Encode()
+
+		rr := httptest.NewRecorder()
+
+		// Execute function to be tested
+		ToggleBoardNote(rr, req)
+
+		// Check status code
+		if status := rr.Code; status != expectedStatusCode {
+			t.Errorf("Error: handler returned status code for value %s: %v, expected: %v", value, status, expectedStatusCode)
+		}
+	}
+}
+
+func TestToggleBoardNoteIfNotOwner(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusBadRequest
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// The puzzle belongs to another user, so no puzzle is found for uid
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "name", "user_id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("PUT", "/boards/notes/toggle", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := req.URL.Query()
+	q.Add("puzzle_id", strconv.Itoa(int(puzzleID)))
+	q.Add("board_row", "1")
+	q.Add("board_col", "3")
+	q.Add("value", "5")
+	req.URL.RawQuery = q.Encode()
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ToggleBoardNote(rr, req)
+
+	// Check status code and that no notes were written
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
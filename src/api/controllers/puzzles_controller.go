@@ -2,12 +2,20 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io/ioutil"
+	"log"
 	"net/http"
+	"net/url"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
 	"github.com/patrickmn/go-cache"
 	"github.com/quattad/sudokubuddy-backend/src/api/auth"
 	"github.com/quattad/sudokubuddy-backend/src/api/caching"
@@ -16,6 +24,7 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
 	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
 )
 
 // GetPuzzle fetches a puzzle by id and user_id
@@ -27,7 +36,8 @@ func GetPuzzle(w http.ResponseWriter, r *http.Request) {
 		4. Create a new pointer to *PuzzlesCRUD, return status code 500 if err
 		5. Execute FindByID, return status code 400 if err.
 		6. Check if puzzles.UserID == uid, if not match return status code 201
-		6. Return status 200 and retrieved puzzle if successful
+		7. Execute FindAllByPuzzleID to include the boards of the puzzle, return status code 400 if err.
+		8. Return status 200 and retrieved puzzle if successful
 	*/
 
 	// Extract ID from route variables
@@ -76,6 +86,15 @@ func GetPuzzle(w http.ResponseWriter, r *http.Request) {
 			responses.ERROR(w, http.StatusBadRequest, err)
 		}
 
+		// Include boards so that clients can tell givens from player entries
+		repoBoards := crud.BoardsCRUDService.NewBoardsCRUD(db)
+
+		puzzle.Boards, err = repoBoards.FindAllByPuzzleID(uint32(pid))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+		}
+
 		b, err := json.Marshal(puzzle)
 
 		if err != nil {
@@ -89,15 +108,16 @@ func GetPuzzle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetPuzzles fetches all puzzles
+// GetPuzzles fetches all puzzles, optionally filtered by difficulty
 func GetPuzzles(w http.ResponseWriter, r *http.Request) {
 	/*
 		1. Get uid (userID) from request, if not authorized, return status code 201
-		2. Connect to the DB, return status code 500 if err
-		3. Create a new pointer to *PuzzlesCRUD, return status code 500 if err
-		4. Execute FindAll, return status code 400 if err.
-		5. Check if puzzles.UserID == uid, if not match return status code 201
-		6. Return status 200 and retrieved puzzles if successful
+		2. Parse difficulty from the query string if present, return status code 400 if invalid
+		3. Connect to the DB, return status code 500 if err
+		4. Create a new pointer to *PuzzlesCRUD, return status code 500 if err
+		5. Execute FindAll or FindAllByDifficulty, return status code 400 if err.
+		6. Check if puzzles.UserID == uid, if not match return status code 201
+		7. Return status 200 and retrieved puzzles if successful
 	*/
 
 	// Fetch user ID from request body
@@ -107,7 +127,28 @@ func GetPuzzles(w http.ResponseWriter, r *http.Request) {
 		responses.ERROR(w, http.StatusUnauthorized, err)
 	}
 
-	if it, found := caching.Cache.Get("puzzles/all"); found {
+	values, err := url.ParseQuery(r.URL.RawQuery)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var difficulty sudoku.Difficulty
+	cacheString := puzzleListsKey(uid)
+
+	if values.Get("difficulty") != "" {
+		difficulty, err = sudoku.ParseDifficulty(values.Get("difficulty"))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+
+		cacheString += "/" + string(difficulty)
+	}
+
+	if it, found := caching.Cache.Get(cacheString); found {
 
 		var puzzles []models.Puzzle
 		err := json.Unmarshal(it.([]byte), &puzzles)
@@ -131,7 +172,13 @@ func GetPuzzles(w http.ResponseWriter, r *http.Request) {
 
 		repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
 
-		puzzles, err := repo.FindAll(uid)
+		var puzzles []models.Puzzle
+
+		if difficulty != "" {
+			puzzles, err = repo.FindAllByDifficulty(uid, string(difficulty))
+		} else {
+			puzzles, err = repo.FindAll(uid)
+		}
 
 		if err != nil {
 			responses.ERROR(w, http.StatusBadRequest, err)
@@ -144,12 +191,25 @@ func GetPuzzles(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// GOCACHE
-		caching.Cache.Set("puzzles/all", b, cache.DefaultExpiration)
+		caching.Cache.Set(cacheString, b, cache.DefaultExpiration)
 
 		responses.JSON(w, http.StatusOK, puzzles)
 	}
 }
 
+// puzzleListsKey is the cache key of the puzzles of the user, followed by "/<difficulty>" for those of a difficulty
+func puzzleListsKey(userID uint32) string {
+	return "puzzles/all/" + strconv.Itoa(int(userID))
+}
+
+// invalidatePuzzleLists deletes the cached puzzle lists of the user, which change whenever a puzzle of the user is
+// created, renamed, graded or deleted
+func invalidatePuzzleLists(userID uint32) {
+	key := puzzleListsKey(userID)
+	caching.Cache.Delete(key)
+	caching.DeletePrefix(key + "/")
+}
+
 // CreatePuzzle creates a puzzle in the Puzzle resource
 func CreatePuzzle(w http.ResponseWriter, r *http.Request) {
 	/*
@@ -162,6 +222,7 @@ func CreatePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
 	}
 
 	// Unmarshal body
@@ -169,12 +230,14 @@ func CreatePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
 	}
 
 	uid, err := auth.TokenService.ExtractTokenID(r)
 
 	if err != nil {
 		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
 	}
 
 	puzzle.UserID = uid
@@ -190,6 +253,39 @@ func CreatePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Reject givens that break the rules or do not have exactly one solution
+	// and grade valid givens by the techniques needed to solve them
+	puzzle.Difficulty = ""
+	puzzle.Score = 0
+
+	// Cages of killer puzzles are added once the puzzle is created
+	rules, err := sudoku.PuzzleRules(puzzle, nil)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if puzzle.Givens != nil {
+		err = rules.Validate(puzzle.Givens)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		grade, err := rules.Rate(puzzle.Givens)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		puzzle.Difficulty = string(grade.Difficulty)
+		puzzle.Score = grade.Score
 	}
 
 	// Connect to DB
@@ -197,6 +293,7 @@ func CreatePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
 	}
 
 	defer db.Close()
@@ -208,12 +305,178 @@ func CreatePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
 	}
 
+	invalidatePuzzleLists(puzzle.UserID)
+
 	w.Header().Set("Location", fmt.Sprintf("%s%s/%d", r.Host, r.RequestURI, puzzle.ID))
 	responses.JSON(w, http.StatusCreated, puzzle)
 }
 
+// generateRequest is the request body of GeneratePuzzle
+// Seed is optional, a random seed is picked if it is not provided
+type generateRequest struct {
+	Name       string `json:"name"`
+	Difficulty string `json:"difficulty"`
+	Seed       *int64 `json:"seed"`
+}
+
+// GeneratePuzzle creates a puzzle with generated givens in the Puzzle resource
+func GeneratePuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read from request body into bytes. If err, return status code 422.
+		2. Unmarshal from bytes to generateRequest. If err, return status code 422.
+		3. Parse difficulty. If invalid, return status code 422.
+		4. Generate givens for the difficulty and seed, and validate the puzzle. If err, return status code 422.
+		5. Connect to db. If err, return status code 500.
+		6. Save the puzzle with its givens. If successful, return status code 201 with the created puzzle.
+	*/
+	request := generateRequest{}
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = json.Unmarshal(body, &request)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	difficulty, err := sudoku.ParseDifficulty(request.Difficulty)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	seed := time.Now().UnixNano()
+
+	if request.Seed != nil {
+		seed = *request.Seed
+	}
+
+	givens, err := sudoku.Generate(difficulty, seed)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	grade, err := sudoku.Rate(givens)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	puzzle := models.Puzzle{
+		Name:       request.Name,
+		Difficulty: string(grade.Difficulty),
+		Score:      grade.Score,
+		Seed:       seed,
+		UserID:     uid,
+		Givens:     givens,
+	}
+
+	puzzle.PreparePuzzle()
+	err = puzzle.ValidatePuzzle("")
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Connect to DB
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+
+	puzzle, err = repo.Save(puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	invalidatePuzzleLists(puzzle.UserID)
+
+	w.Header().Set("Location", fmt.Sprintf("%s/puzzles/%d", r.Host, puzzle.ID))
+	responses.JSON(w, http.StatusCreated, puzzle)
+}
+
+// ValidatePuzzle checks the givens of a puzzle without saving it
+func ValidatePuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read from request body into bytes. If err, return status code 422.
+		2. Unmarshal from bytes to model. If err, return status code 422.
+		3. Check that givens is a grid of a supported size that obeys the rules of its variants and has exactly one
+		   solution. If not, return status code 422.
+		4. Return status 200 if the givens are valid.
+	*/
+	puzzle := models.Puzzle{}
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = json.Unmarshal(body, &puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = puzzle.ValidatePuzzle("givens")
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	rules, err := sudoku.PuzzleRules(puzzle, nil)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = rules.Validate(puzzle.Givens)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	data := struct {
+		Valid bool `json:"valid"`
+	}{
+		Valid: true,
+	}
+
+	responses.JSON(w, http.StatusOK, data)
+}
+
 // UpdatePuzzle updates a puzzle by id
 func UpdatePuzzle(w http.ResponseWriter, r *http.Request) {
 	/*
@@ -221,7 +484,9 @@ func UpdatePuzzle(w http.ResponseWriter, r *http.Request) {
 		2. Unmarshal from bytes to model. If err, return status code 400.
 		3. Extract tokenID from request and check if it matches userID. If no match, return status code 401.
 		4. Connect to db. If err, return status code 500.
-		5. Execute update. If successful, return status code 200 with number of rows updated.
+		5. Execute update. If err, return status code 400.
+		6. Publish the new name to the subscribers of the puzzle if it was updated.
+		7. Return status code 200 with number of rows updated.
 	*/
 
 	// Extract id (puzzleID) from route variables
@@ -275,6 +540,12 @@ func UpdatePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// No rows are updated if the puzzle does not belong to the user
+	if rows > 0 {
+		publishPuzzleChange(puzzleRenameEvent, puzzleChange{PuzzleID: puzzle.ID, UserID: userID, Name: puzzle.Name})
 	}
 
 	responses.JSON(w, http.StatusOK, rows)
@@ -286,7 +557,9 @@ func DeletePuzzle(w http.ResponseWriter, r *http.Request) {
 		1. Extract UID from route variable
 		2. Connect to db. If err, return status code 500.
 		3. Extract the tokenID and check if it matches the userID. If it does not match, return status code 201 unauthorized.
-		4. Execute update. If successful, return status code 200 and number of rows updated.
+		4. Execute delete. If err, return status code 400.
+		5. Publish the deletion to the subscribers of the puzzle if it was deleted.
+		6. Return status code 200 and number of rows deleted.
 	*/
 
 	routeVariables := mux.Vars(r)
@@ -316,7 +589,727 @@ func DeletePuzzle(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// No rows are deleted if the puzzle does not belong to the user
+	if rows > 0 {
+		publishPuzzleChange(puzzleDeleteEvent, puzzleChange{PuzzleID: uint32(puzzleID), UserID: tokenUID})
 	}
 
 	responses.JSON(w, http.StatusOK, rows)
 }
+
+// SolvePuzzle solves a puzzle by id from the values stored in its boards
+func SolvePuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID to check that the puzzle belongs to uid, return status code 400 if err
+		5. Execute FindAllByPuzzleID and build the grid, return status code 400 if err
+		6. Check that the puzzle is not a race or daily copy, return status code 409 if it is
+		7. Solve the grid, return status code 422 if the givens are contradictory or unsolvable
+		8. Return status 200 with the solved grid and whether the solution is unique
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Connect to db
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, grid, rules, err := findPuzzleGrid(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = checkPuzzleAssist(puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	solution, err := rules.Solve(grid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, solution)
+}
+
+// GetPuzzleHint fetches the next logical deduction for the current boards of a puzzle by id
+func GetPuzzleHint(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Fetch the puzzle owned by uid and build the grid from its boards, return status code 400 if err
+		5. Check that the puzzle is not a race or daily copy, return status code 409 if it is
+		6. Find the next logical step, return status code 422 if there is none or the boards have no solution
+		7. Save the technique of the hint for the statistics of uid, return status code 400 if err
+		8. Return status 200 with the hint
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Connect to db
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, grid, rules, err := findPuzzleGrid(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = checkPuzzleAssist(puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	hint, err := rules.NextHint(grid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Hints are counted in the statistics of the user
+	repoHints := crud.HintsCRUDService.NewHintsCRUD(db)
+	_, err = repoHints.Save(models.Hint{Technique: hint.Technique, PuzzleID: uint32(puzzleID), UserID: uid})
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, hint)
+}
+
+// errGridSize is returned when a grid string does not have one character for every board of the puzzle
+var errGridSize = errors.New("Grid string does not match the size of the puzzle")
+
+// puzzleGrid is the body of full grid requests, holding the cells of a puzzle row by row
+type puzzleGrid struct {
+	Grid string `json:"grid"`
+}
+
+// GetPuzzleGrid fetches the current values of all boards of a puzzle by id as a single grid string, e.g. of
+// 81 characters for a 9x9 puzzle
+func GetPuzzleGrid(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Fetch the puzzle owned by uid and build the grid from its boards, return status code 400 if err
+		5. Return status 200 with the grid string, with empty cells written as '.'
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Connect to db
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	_, grid, _, err := findPuzzleGrid(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, puzzleGrid{Grid: grid.String()})
+}
+
+// UpdatePuzzleGrid writes all boards of a puzzle by id from a single grid string of the size of the puzzle
+// If the puzzle has no givens yet, the filled cells of the string become its givens. Otherwise every given
+// must keep its value and the remaining cells are written as player entries
+func UpdatePuzzleGrid(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Read from request body and unmarshal into puzzleGrid, return status code 422 if err
+		3. Parse the grid string, return status code 422 if it has the wrong length or invalid characters
+		4. Get uid (userID) from request, if not authorized, return status code 401
+		5. Connect to the DB, return status code 500 if err
+		6. Fetch the puzzle owned by uid, its boards and its rules, return status code 400 if err
+		7. Check that the grid has the size of the puzzle, return status code 422 if not
+		8. Check that the puzzle is not in a race that has not started or is finished, return status code 409 if it is
+		9. Check that no given is changed, return status code 403 if one is
+		10. If the puzzle is being set up, check that the new givens obey its rules and have exactly one solution,
+		    return status code 422 if not, and grade them
+		11. Execute UpdateAll on the changed boards in a single transaction, which also appends the entered values to
+		    the move log, return status code 400 if err
+		12. Save the grade of the new givens, publish the changed boards and count the entered values in the game
+		    session and race of the puzzle
+		13. Return status 200 with the number of rows updated
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	request := puzzleGrid{}
+	err = json.Unmarshal(body, &request)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	grid, err := sudoku.Parse(request.Grid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Connect to db
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	size := grid.Size()
+
+	if len(boards) != size*size {
+		responses.ERROR(w, http.StatusUnprocessableEntity, errGridSize)
+		return
+	}
+
+	// Boards of a race can only be changed while the race is on
+	err = checkRaceMove(puzzle.ID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// A puzzle without givens is being set up, so its filled cells become the givens
+	setup := true
+
+	for _, board := range boards {
+		if board.Given {
+			setup = false
+			break
+		}
+	}
+
+	// Boards are changed in place, so that they hold the new givens and values for the hooks of the changes
+	changed := []models.Board{}
+
+	for i, board := range boards {
+		if board.BoardRow < 1 || board.BoardRow > size || board.BoardCol < 1 || board.BoardCol > size {
+			continue
+		}
+
+		value := grid[board.BoardRow-1][board.BoardCol-1]
+
+		if board.Given && value != board.Value {
+			responses.ERROR(w, http.StatusForbidden, errGivenBoard)
+			return
+		}
+
+		given := board.Given || (setup && value != 0)
+
+		if value == board.Value && given == board.Given {
+			continue
+		}
+
+		boards[i].Value = value
+		boards[i].Given = given
+		changed = append(changed, boards[i])
+	}
+
+	// New givens are held to the same standard as those of CreatePuzzle
+	setup = setup && grid.Filled() > 0
+	grade := sudoku.Grade{}
+
+	if setup {
+		err = rules.Validate(grid)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		grade, err = rules.Rate(grid)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+
+	repoBoards := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	rows, err := repoBoards.UpdateAll(puzzle.ID, uid, changed)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The boards are already updated, so a grade that cannot be saved is only logged
+	if setup {
+		invalidatePuzzleSolution(puzzle.ID)
+		_, err = crud.PuzzlesCRUDService.NewPuzzlesCRUD(db).UpdateGrade(puzzle.ID, uid, string(grade.Difficulty), grade.Score)
+
+		if err != nil {
+			log.Println(err)
+		}
+
+		// The grade moves the puzzle to the list of its difficulty
+		invalidatePuzzleLists(uid)
+	}
+
+	recordBoardChanges(db, puzzle, uid, boards, changed, grid, rules)
+
+	responses.JSON(w, http.StatusOK, rows)
+}
+
+// maxImportSize is the largest request body accepted by ImportPuzzles, in bytes
+const maxImportSize = 32 << 20
+
+// importFile is a file of puzzles read from an import request
+type importFile struct {
+	Name string
+	Data string
+}
+
+// ImportPuzzles creates a puzzle with its boards for every puzzle held in the uploaded files
+// Files are read from the 'file' fields of a multipart form, or from the raw request body
+func ImportPuzzles(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Get uid (userID) from request, if not authorized, return status code 401
+		2. Read the files from the request, return status code 400 if err
+		3. Decode the puzzles of each file in the format query parameter, the format of the file extension or the
+		format detected from its layout, return status code 422 if err
+		4. Validate and grade every puzzle before saving any, return status code 422 if any puzzle is invalid
+		5. Connect to the DB, return status code 500 if err
+		6. Save every puzzle with its givens in one transaction, return status code 422 if err
+		7. Return status 201 with the created puzzles
+	*/
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	files, err := readImportFiles(w, r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	q := r.URL.Query()
+	entries := []sudoku.Entry{}
+
+	for _, file := range files {
+		format := sudoku.DetectFormat(file.Data)
+
+		if q.Get("format") != "" {
+			format, err = sudoku.ParseFormat(q.Get("format"))
+		} else if ext := filepath.Ext(file.Name); ext != "" {
+			format, err = sudoku.ParseFormat(ext)
+		}
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		decoded, err := sudoku.Decode(file.Data, format)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, fmt.Errorf("%s: %v", file.Name, err))
+			return
+		}
+
+		entries = append(entries, decoded...)
+	}
+
+	// Puzzles without a name in the file are numbered after the name query parameter
+	base := q.Get("name")
+
+	if base == "" {
+		base = "Import " + strconv.FormatInt(time.Now().Unix(), 36)
+	}
+
+	puzzles := []models.Puzzle{}
+
+	for i, entry := range entries {
+		puzzle := models.Puzzle{
+			Name:   entry.Name,
+			UserID: uid,
+			Givens: entry.Grid,
+		}
+
+		// Names are shortened to fit the puzzles table once escaped, keeping the number of a numbered name
+		if puzzle.Name == "" && len(entries) == 1 {
+			puzzle.Name = fitPuzzleName(base, "")
+		} else if puzzle.Name == "" {
+			puzzle.Name = fitPuzzleName(base, fmt.Sprintf(" %d", i+1))
+		} else {
+			puzzle.Name = fitPuzzleName(puzzle.Name, "")
+		}
+
+		puzzle.PreparePuzzle()
+		err = puzzle.ValidatePuzzle("")
+
+		if err == nil {
+			err = sudoku.Validate(puzzle.Givens)
+		}
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, fmt.Errorf("Puzzle %d: %v", i+1, err))
+			return
+		}
+
+		grade, err := sudoku.Rate(puzzle.Givens)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, fmt.Errorf("Puzzle %d: %v", i+1, err))
+			return
+		}
+
+		puzzle.Difficulty = string(grade.Difficulty)
+		puzzle.Score = grade.Score
+		puzzles = append(puzzles, puzzle)
+	}
+
+	// Connect to DB
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzles, err = repo.SaveAll(puzzles)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	invalidatePuzzleLists(uid)
+
+	responses.JSON(w, http.StatusCreated, puzzles)
+}
+
+// ExportPuzzle writes the givens of a puzzle by id as a file in the format query parameter
+// Puzzles without any given, such as those created before givens were flagged, export their current values
+func ExportPuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Parse the format query parameter, which defaults to 'txt', return status code 400 if err
+		3. Get uid (userID) from request, if not authorized, return status code 401
+		4. Connect to the DB, return status code 500 if err
+		5. Execute FindByID and FindAllByPuzzleID, return status code 400 if err
+		6. Return status 200 with the puzzle file as an attachment
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	format := sudoku.FormatLine
+
+	if f := r.URL.Query().Get("format"); f != "" {
+		format, err = sudoku.ParseFormat(f)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Connect to db
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repoPuzzles := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzle, err := repoPuzzles.FindByID(uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repoBoards := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	boards, err := repoBoards.FindAllByPuzzleID(uint32(puzzleID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	grid, err := sudoku.GivensFromBoards(puzzle, boards)
+
+	if err == nil && grid.Filled() == 0 {
+		grid, err = sudoku.FromBoards(puzzle, boards)
+	}
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := sudoku.Encode(grid, html.UnescapeString(puzzle.Name), format)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"puzzle-%d.%s\"", puzzleID, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(data))
+}
+
+// readImportFiles reads the 'file' fields of a multipart form, or the raw request body as a single file
+func readImportFiles(w http.ResponseWriter, r *http.Request) ([]importFile, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []importFile{importFile{Name: "body", Data: string(body)}}, nil
+	}
+
+	err := r.ParseMultipartForm(maxImportSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []importFile{}
+
+	for _, header := range r.MultipartForm.File["file"] {
+		f, err := header.Open()
+
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ioutil.ReadAll(f)
+		f.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, importFile{Name: header.Filename, Data: string(data)})
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("Request must have a field 'file'")
+	}
+
+	return files, nil
+}
+
+// deletePuzzleCopy deletes a copy that was saved for a user but could not be completed, along with its boards and
+// cages, such as the copy of a player that could not join a race or a clone whose cages could not be saved
+// The user already gets an error, so an error deleting the copy is only logged
+func deletePuzzleCopy(repo *crud.PuzzlesCRUD, puzzle models.Puzzle) {
+	_, err := repo.Delete(puzzle.ID, puzzle.UserID)
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	invalidatePuzzleLists(puzzle.UserID)
+}
+
+// findPuzzleGrid fetches a puzzle owned by userID and builds a grid from the current values of its boards, along
+// with the rules of its variants
+func findPuzzleGrid(db *gorm.DB, puzzleID uint32, userID uint32) (models.Puzzle, sudoku.Grid, sudoku.Rules, error) {
+	puzzle, boards, err := findPuzzleBoards(db, puzzleID, userID)
+
+	if err != nil {
+		return models.Puzzle{}, nil, nil, err
+	}
+
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		return models.Puzzle{}, nil, nil, err
+	}
+
+	grid, err := sudoku.FromBoards(puzzle, boards)
+
+	return puzzle, grid, rules, err
+}
+
+// checkPuzzleAssist returns an error if the puzzle is a copy that is ranked against other users, which are the copies
+// of races and of daily puzzles, and so cannot be solved or hinted
+func checkPuzzleAssist(puzzle models.Puzzle) error {
+	if puzzle.Daily != "" {
+		return errDailyAssist
+	}
+
+	races.mu.Lock()
+	defer races.mu.Unlock()
+
+	if _, ok := races.byPuzzle[puzzle.ID]; ok {
+		return errRaceAssist
+	}
+
+	return nil
+}
+
+// findPuzzleBoards fetches a puzzle owned by userID and returns it with its boards
+func findPuzzleBoards(db *gorm.DB, puzzleID uint32, userID uint32) (models.Puzzle, []models.Board, error) {
+	repoPuzzles := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+
+	puzzle, err := repoPuzzles.FindByID(puzzleID, userID)
+
+	if err != nil {
+		return models.Puzzle{}, nil, err
+	}
+
+	repoBoards := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	boards, err := repoBoards.FindAllByPuzzleID(puzzleID)
+
+	return puzzle, boards, err
+}
+
+// puzzleErrorStatus returns the status code of an error fetching a puzzle owned by the user, which is 404 if the user
+// owns no puzzle with that id
+func puzzleErrorStatus(err error) int {
+	if err == crud.ErrPuzzleNotFound {
+		return http.StatusNotFound
+	}
+
+	return http.StatusBadRequest
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -14,6 +15,7 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/auth"
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
 	"github.com/quattad/sudokubuddy-backend/src/api/tests"
 )
 
@@ -274,3 +276,116 @@ func TestCreatePuzzleIfDBCannotConnect(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+func TestCreatePuzzleIfContradictoryGivens(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	uid := uint32(100)
+
+	// Second cell duplicates the 5 in the first cell
+	data := []models.Puzzle{
+		models.Puzzle{
+			Name:   "testpuzzle1",
+			UserID: uid,
+			Givens: givensFromString("55" + testGivens[2:]),
+		},
+	}
+
+	expected, err := json.Marshal(data[0])
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles", bytes.NewBuffer(expected))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	CreatePuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no insert was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestCreatePuzzleIfMissingNameWithContradictoryGivens(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	uid := uint32(100)
+
+	data := models.Puzzle{
+		Givens: givensFromString("55" + testGivens[2:]),
+	}
+
+	expected, err := json.Marshal(data)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles", bytes.NewBuffer(expected))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	CreatePuzzle(rr, req)
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// The handler stops at the missing name, before checking the givens
+	if body := rr.Body.String(); !strings.Contains(body, "'name'") || strings.Contains(body, sudoku.ErrContradictory.Error()) {
+		t.Errorf("Error: handler returned unexpected body: %s", body)
+	}
+}
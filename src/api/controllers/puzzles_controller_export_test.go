@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== EXPORTPUZZLE() ========== //
+func TestExportPuzzleIfSuccessfulSS(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/export?format=ss", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ExportPuzzle(rr, req)
+
+	// Check status code, headers and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	if disposition := rr.Header().Get("Content-Disposition"); disposition != `attachment; filename="puzzle-125.ss"` {
+		t.Errorf("Error: handler returned Content-Disposition: %v", disposition)
+	}
+
+	if rows := strings.Split(rr.Body.String(), "\n"); rows[0] != "53.|.7.|..." {
+		t.Errorf("Error: handler returned unexpected file: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestExportPuzzleIfInvalidFormat(t *testing.T) {
+	expectedStatusCode := http.StatusBadRequest
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/export?format=pdf", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "125",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ExportPuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== GENERATEPUZZLE() ========== //
+func TestGeneratePuzzleIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+	seed := int64(42)
+
+	expectedGivens, err := sudoku.Generate(sudoku.Hard, seed)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Expect insert for puzzle, followed by its 81 boards
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs("testpuzzle1", "hard", sqlmock.AnyArg(), seed, "", nil, 0, 9, 3, 3, "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	for i := 0; i < 81; i++ {
+		s.Mock.ExpectBegin()
+		s.Mock.ExpectExec("INSERT INTO").
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+		s.Mock.ExpectCommit()
+	}
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	body := []byte(fmt.Sprintf(`{"name": "testpuzzle1", "difficulty": "hard", "seed": %d}`, seed))
+	req, err := http.NewRequest("POST", "/puzzles/generate", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	GeneratePuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	puzzle := models.Puzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &puzzle); err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.Seed != seed || puzzle.Difficulty != "hard" || puzzle.UserID != uid {
+		t.Errorf("Error: handler returned unexpected puzzle: %s", rr.Body.String())
+	}
+
+	for i := range expectedGivens {
+		for j := range expectedGivens[i] {
+			if puzzle.Givens[i][j] != expectedGivens[i][j] {
+				t.Fatalf("Error: handler returned unexpected givens: %v, expected: %v", puzzle.Givens, expectedGivens)
+			}
+		}
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGeneratePuzzleIfInvalidDifficulty(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+
+	// Initialize struct with modified interfaces
+	auth.TokenService = &tokenMock{}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	body := []byte(`{"name": "testpuzzle1", "difficulty": "impossible"}`)
+	req, err := http.NewRequest("POST", "/puzzles/generate", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	GeneratePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
@@ -11,7 +11,9 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/jinzhu/gorm"
+	"github.com/patrickmn/go-cache"
 	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
 	"github.com/quattad/sudokubuddy-backend/src/api/tests"
@@ -35,6 +37,24 @@ func TestGetPuzzleIfSuccessfulGet(t *testing.T) {
 			UserID:    uid,
 			CreatedAt: createdAtExpected,
 			UpdatedAt: updatedAtExpected,
+			Boards: []models.Board{
+				models.Board{
+					ID:       1,
+					BoardRow: 1,
+					BoardCol: 1,
+					Value:    5,
+					Given:    true,
+					PuzzleID: puzzleID,
+				},
+				models.Board{
+					ID:       2,
+					BoardRow: 1,
+					BoardCol: 2,
+					Value:    3,
+					Given:    false,
+					PuzzleID: puzzleID,
+				},
+			},
 		},
 	}
 
@@ -53,6 +73,16 @@ func TestGetPuzzleIfSuccessfulGet(t *testing.T) {
 		WithArgs(puzzleID, uid).
 		WillReturnRows(rows)
 
+	boardRows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "given", "puzzle_id"})
+
+	for _, board := range data[0].Boards {
+		boardRows.AddRow(board.ID, board.BoardRow, board.BoardCol, board.Value, board.Given, puzzleID)
+	}
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(boardRows)
+
 	// Initialize struct with modified interfaces
 	database.DBService = &dbMock{}
 	auth.TokenService = &tokenMock{}
@@ -194,3 +224,170 @@ func TestGetPuzzlesIfSuccessfulGet(t *testing.T) {
 		t.Errorf("Error: handler returned unexpected body: %v, expected: %v", string(actualDataBytes), string(expectedDataBytes))
 	}
 }
+
+func TestGetPuzzlesIfFilteredByDifficulty(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+
+	uid := uint32(101)
+	puzzleID := uint32(126)
+
+	createdAtExpected := time.Now()
+	updatedAtExpected := time.Now()
+
+	// Populate DB and define expected response
+	data := []models.Puzzle{
+		models.Puzzle{
+			ID:         puzzleID,
+			Name:       "testpuzzle1",
+			Difficulty: "hard",
+			Score:      75,
+			UserID:     uid,
+			CreatedAt:  createdAtExpected,
+			UpdatedAt:  updatedAtExpected,
+		},
+	}
+
+	expectedDataBytes, err := json.Marshal(data)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	expectedDataReader := bytes.NewBuffer(expectedDataBytes)
+
+	rows := s.Mock.NewRows([]string{"id", "name", "difficulty", "score", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, data[0].Name, data[0].Difficulty, data[0].Score, createdAtExpected, updatedAtExpected, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(uid, "hard").
+		WillReturnRows(rows)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles?difficulty=Hard", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzles(rr, req)
+
+	actualDataBytes := rr.Body.Bytes()
+	actualDataReader := bytes.NewBuffer(actualDataBytes)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	if !utils.JSONEqual(actualDataReader, expectedDataReader) {
+		t.Errorf("Error: handler returned unexpected body: %v, expected: %v", string(actualDataBytes), string(expectedDataBytes))
+	}
+}
+
+func TestGetPuzzlesIfInvalidDifficulty(t *testing.T) {
+	uid := uint32(101)
+	expectedStatusCode := http.StatusBadRequest
+
+	// Initialize struct with modified interfaces
+	auth.TokenService = &tokenMock{}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles?difficulty=impossible", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzles(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestGetPuzzlesIfCachedForOtherUser(t *testing.T) {
+	// Populate DB
+	s := tests.CreateSuite()
+
+	uidOne := uint32(100)
+	uidTwo := uint32(9495)
+
+	// The puzzles of uidOne are cached, but uidTwo has none
+	caching.Cache.Set(puzzleListsKey(uidOne), []byte(`[{"id": 125, "name": "testpuzzle1", "user_id": 100}]`), cache.DefaultExpiration)
+	defer invalidatePuzzleLists(uidOne)
+	defer invalidatePuzzleLists(uidTwo)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(uidTwo).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uidTwo, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzles(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	puzzles := []models.Puzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &puzzles); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(puzzles) != 0 {
+		t.Errorf("Error: handler returned the puzzles of another user: %s", rr.Body.String())
+	}
+
+	// ensure the puzzles of uidTwo were fetched
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,459 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/events"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== GETPUZZLEGRID() ========== //
+func TestGetPuzzleGridIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/grid", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzleGrid(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	response := puzzleGrid{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := strings.Replace(testGivens, "0", ".", -1); response.Grid != expected {
+		t.Errorf("Error: handler returned grid: %v, expected: %v", response.Grid, expected)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetPuzzleGridIfHexadoku(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	// Values above 9 are written as letters
+	givens := "123456789ABCDEFG" + strings.Repeat(".", 240)
+	expectPuzzleWithBoards(s, puzzleID, uid, givens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/grid", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzleGrid(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	response := puzzleGrid{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Grid != givens {
+		t.Errorf("Error: handler returned grid: %v, expected: %v", response.Grid, givens)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== UPDATEPUZZLEGRID() ========== //
+func TestUpdatePuzzleGridIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Only (1, 3) changes, so it is the only board updated within the transaction and appended to the move log
+	expectBoardUpdate(s, puzzleID, uid, 1, 3, 0, 4)
+
+	// SELECT * FROM `sessions` WHERE (puzzle_id=? AND completed_at IS NULL) ORDER BY id desc LIMIT 1
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	ch, unsubscribe := events.Events.Subscribe(puzzleTopic(puzzleID))
+	defer unsubscribe()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	body, err := json.Marshal(puzzleGrid{Grid: "534" + testGivens[3:]})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/puzzles/125/grid", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UpdatePuzzleGrid(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	if strings.TrimSpace(rr.Body.String()) != "1" {
+		t.Errorf("Error: handler returned rows: %v, expected: %v", rr.Body.String(), 1)
+	}
+
+	select {
+	case event := <-ch:
+		if change := event.Data.(puzzleChange); change.BoardRow != 1 || change.BoardCol != 3 || *change.Value != 4 {
+			t.Errorf("Error: handler published unexpected change: %+v", change)
+		}
+	default:
+		t.Errorf("Error: handler did not publish the changed board")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdatePuzzleGridIfSetup(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	// The puzzle has no givens yet, so the filled cells of the grid become its givens, which are not moves
+	expectPuzzleWithBoards(s, puzzleID, uid, strings.Repeat(".", 81))
+
+	s.Mock.ExpectBegin()
+
+	for i, ch := range testGivens {
+		if value := models.SymbolValue(ch); value != 0 {
+			s.Mock.ExpectExec("UPDATE").
+				WithArgs(true, sqlmock.AnyArg(), value, puzzleID, i/9+1, i%9+1).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+	}
+
+	s.Mock.ExpectCommit()
+
+	// The new givens are graded like those of a new puzzle
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(string(sudoku.Easy), sqlmock.AnyArg(), sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	rr := updatePuzzleGrid(t, s, uid, testGivens)
+
+	// Check status code
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdatePuzzleGridIfSetupHasMultipleSolutions(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	expectPuzzleWithBoards(s, puzzleID, uid, strings.Repeat(".", 81))
+
+	// A single given cannot make a puzzle
+	rr := updatePuzzleGrid(t, s, uid, "5"+strings.Repeat(".", 80))
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if !strings.Contains(rr.Body.String(), sudoku.ErrMultipleSolutions.Error()) {
+		t.Errorf("Error: handler returned unexpected body: %s", rr.Body.String())
+	}
+
+	// ensure no update was attempted
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// updatePuzzleGrid runs UpdatePuzzleGrid on puzzle 125 as uid with grid as the new grid string
+func updatePuzzleGrid(t *testing.T, s tests.Suite, uid uint32, grid string) *httptest.ResponseRecorder {
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	body, err := json.Marshal(puzzleGrid{Grid: grid})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest("PUT", "/puzzles/125/grid", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "125",
+	})
+
+	rr := httptest.NewRecorder()
+
+	UpdatePuzzleGrid(rr, req)
+
+	return rr
+}
+
+func TestUpdatePuzzleGridIfGivenChanged(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusForbidden
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// (1, 1) holds the given 5
+	body, err := json.Marshal(puzzleGrid{Grid: "4" + testGivens[1:]})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/puzzles/125/grid", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UpdatePuzzleGrid(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no update was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdatePuzzleGridIfInvalidLength(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	body, err := json.Marshal(puzzleGrid{Grid: testGivens[1:]})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/puzzles/125/grid", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "125",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UpdatePuzzleGrid(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestUpdatePuzzleGridIfSizeMismatch(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// A 4x4 grid for a 9x9 puzzle
+	body, err := json.Marshal(puzzleGrid{Grid: "1234" + "3412" + "2143" + "4321"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/puzzles/125/grid", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	UpdatePuzzleGrid(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no update was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
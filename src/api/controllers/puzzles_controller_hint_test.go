@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== GETPUZZLEHINT() ========== //
+func TestGetPuzzleHintIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// The hint is saved for the statistics of the user
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO `hints`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/hint", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzleHint(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	hint := sudoku.Hint{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &hint); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := hint.Placement; p == nil || int(testSolution[(p.Row-1)*9+p.Col-1]-'0') != p.Value {
+		t.Errorf("Error: handler returned unexpected hint: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetPuzzleHintIfNoLogicalStep(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	// An empty board can only be progressed by guessing
+	expectPuzzleWithBoards(s, puzzleID, uid, strings.Repeat(".", 81))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/hint", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzleHint(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestGetPuzzleHintIfDailyCopy(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusConflict
+
+	// The copy of a daily puzzle is ranked on the daily leaderboard
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "daily", "size", "box_rows", "box_cols", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", "2020-06-01", 9, 3, 3, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoards(s, puzzleID, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/hint", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetPuzzleHint(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no hint was saved
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
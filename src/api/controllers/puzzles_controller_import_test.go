@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// expectPuzzleInsert mocks the insert of a puzzle named name followed by its 81 boards
+// The name is either the name itself or an sqlmock.Argument matching it
+func expectPuzzleInsert(s tests.Suite, id int64, name interface{}, uid uint32) {
+	s.Mock.ExpectBegin()
+	expectPuzzleRowInsert(s, name, uid).
+		WillReturnResult(sqlmock.NewResult(id, 1))
+	s.Mock.ExpectCommit()
+
+	for i := 0; i < 81; i++ {
+		s.Mock.ExpectBegin()
+		s.Mock.ExpectExec("INSERT INTO").
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+		s.Mock.ExpectCommit()
+	}
+}
+
+// expectPuzzleRowInsert mocks the insert of the row of a puzzle named name, without its boards. Clones also set the
+// origin of the puzzle, which is not checked
+func expectPuzzleRowInsert(s tests.Suite, name interface{}, uid uint32) *sqlmock.ExpectedExec {
+	return s.Mock.ExpectExec("INSERT INTO").
+		WithArgs(name, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "", nil, sqlmock.AnyArg(), 9, 3, 3, "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), uid)
+}
+
+// expectPuzzleImport mocks the insert of a puzzle named name followed by its 81 boards within the transaction of an
+// import
+func expectPuzzleImport(s tests.Suite, id int64, name string, uid uint32) {
+	expectPuzzleRowInsert(s, name, uid).
+		WillReturnResult(sqlmock.NewResult(id, 1))
+
+	for i := 0; i < 81; i++ {
+		s.Mock.ExpectExec("INSERT INTO").
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+	}
+}
+
+// ========== IMPORTPUZZLES() ========== //
+func TestImportPuzzlesIfSuccessfulRawBody(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+
+	// The second puzzle has no name in the file and is named after the name query parameter
+	s.Mock.ExpectBegin()
+	expectPuzzleImport(s, 1, "classic", uid)
+	expectPuzzleImport(s, 2, "library 2", uid)
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	body := []byte(testGivens + " classic\n" + testGivens + "\n")
+	req, err := http.NewRequest("POST", "/puzzles/import?name=library", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "text/plain")
+
+	// Execute function to be tested
+	ImportPuzzles(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	puzzles := []models.Puzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &puzzles); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(puzzles) != 2 || puzzles[0].Difficulty == "" {
+		t.Errorf("Error: handler returned unexpected puzzles: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestImportPuzzlesIfSuccessfulMultipartSDK(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+
+	s.Mock.ExpectBegin()
+	expectPuzzleImport(s, 1, "classic", uid)
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	data, err := sudoku.Encode(givensFromString(testGivens), "classic", sudoku.FormatSDK)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build multipart form with a single .sdk file
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "classic.sdk")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part.Write([]byte(data))
+	writer.Close()
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/import", body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	// Execute function to be tested
+	ImportPuzzles(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestImportPuzzlesIfInvalidPuzzle(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Second puzzle has contradictory givens, so neither puzzle is saved
+	body := []byte(testGivens + "\n55" + testGivens[2:] + "\n")
+	req, err := http.NewRequest("POST", "/puzzles/import?format=txt", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ImportPuzzles(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no insert was attempted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestImportPuzzlesIfSaveFails(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+
+	// Names are shortened to fit once escaped, and the first puzzle is rolled back when the second cannot be saved
+	s.Mock.ExpectBegin()
+	expectPuzzleImport(s, 1, "A&amp;B&amp;C&amp; 1", uid)
+	expectPuzzleRowInsert(s, "A&amp;B&amp;C&amp; 2", uid).
+		WillReturnError(errors.New("Duplicate entry"))
+	s.Mock.ExpectRollback()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	body := []byte(testGivens + "\n" + testGivens + "\n")
+	req, err := http.NewRequest("POST", "/puzzles/import?format=txt&name="+url.QueryEscape("A&B&C&D&E"), bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ImportPuzzles(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+const (
+	testGivens   = "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	testSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+// expectPuzzleWithBoards mocks the queries for a puzzle owned by uid followed by its boards holding givens
+func expectPuzzleWithBoards(s tests.Suite, puzzleID uint32, uid uint32, givens string) {
+	size := int(math.Sqrt(float64(len(givens))))
+	boxRows, boxCols := models.BoxShape(size)
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "size", "box_rows", "box_cols", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", size, boxRows, boxCols, time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoards(s, puzzleID, givens)
+}
+
+// expectPuzzleBoards mocks the query for the boards of a puzzle holding givens, one board per character
+func expectPuzzleBoards(s tests.Suite, puzzleID uint32, givens string) {
+	expectPuzzleBoardsEntered(s, puzzleID, givens, strings.Repeat(".", len(givens)))
+}
+
+// expectPuzzleBoardsEntered mocks the query for the boards of a puzzle holding givens, and the values
+// that were entered by users in the empty cells. The length of givens sets the size of the grid
+func expectPuzzleBoardsEntered(s tests.Suite, puzzleID uint32, givens string, entered string) {
+	boardRows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "given", "puzzle_id"})
+	size := int(math.Sqrt(float64(len(givens))))
+
+	for i, ch := range givens {
+		value := models.SymbolValue(ch)
+		given := value != 0
+
+		if !given {
+			value = models.SymbolValue(rune(entered[i]))
+		}
+
+		boardRows.AddRow(i+1, i/size+1, i%size+1, value, given, puzzleID)
+	}
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(boardRows)
+}
+
+// expectOwnedPuzzle mocks the query for a classic puzzle owned by uid with variants, e.g. "x,anti_knight"
+func expectOwnedPuzzle(s tests.Suite, puzzleID uint32, uid uint32, variants string) {
+	rows := s.Mock.NewRows([]string{"id", "name", "size", "box_rows", "box_cols", "variants", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", 9, 3, 3, variants, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(rows)
+}
+
+// ========== SOLVEPUZZLE() ========== //
+func TestSolvePuzzleIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/solve", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	SolvePuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	solution := sudoku.Solution{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &solution); err != nil {
+		t.Fatal(err)
+	}
+
+	if !solution.Unique {
+		t.Errorf("Error: handler returned unique: false, expected true")
+	}
+
+	for i, ch := range testSolution {
+		if solution.Grid[i/9][i%9] != int(ch-'0') {
+			t.Fatalf("Error: handler returned unexpected grid: %v", solution.Grid)
+		}
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("unmet expectation error: %s", err)
+	}
+}
+
+func TestSolvePuzzleIfRaceCopy(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusConflict
+
+	_, cleanup := addRace(&racePlayer{UserID: uid, PuzzleID: puzzleID}, &racePlayer{UserID: 200, PuzzleID: 126})
+	defer cleanup()
+
+	expectPuzzleWithBoards(s, puzzleID, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/solve", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	SolvePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestSolvePuzzleIfContradictoryGivens(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	// Second cell duplicates the 5 in the first cell
+	expectPuzzleWithBoards(s, puzzleID, uid, "55"+testGivens[2:])
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/solve", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	SolvePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
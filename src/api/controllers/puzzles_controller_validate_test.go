@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+)
+
+// givensFromString builds a 9x9 grid of givens from an 81 character string of digits
+func givensFromString(s string) [][]int {
+	givens := make([][]int, 9)
+
+	for i := range givens {
+		givens[i] = make([]int, 9)
+	}
+
+	for i, ch := range s {
+		if ch >= '1' && ch <= '9' {
+			givens[i/9][i%9] = int(ch - '0')
+		}
+	}
+
+	return givens
+}
+
+// ========== VALIDATEPUZZLE() ========== //
+func TestValidatePuzzleIfSuccessful(t *testing.T) {
+	data := models.Puzzle{
+		Givens: givensFromString(testGivens),
+	}
+
+	body, err := json.Marshal(data)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/validate", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	ValidatePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+}
+
+func TestValidatePuzzleIfMultipleSolutions(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	data := models.Puzzle{
+		Givens: givensFromString("530070000"),
+	}
+
+	body, err := json.Marshal(data)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/validate", bytes.NewBuffer(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	ValidatePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestValidatePuzzleIfMissingGivens(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/validate", bytes.NewBufferString("{}"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	ValidatePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
@@ -0,0 +1,645 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/events"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/security"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// Types of the events published to the players of a race
+const (
+	raceSnapshot = "race"
+	raceJoin     = "join"
+	raceStart    = "start"
+	raceProgress = "progress"
+	raceWinner   = "winner"
+)
+
+var (
+	errInvalidRace       = errors.New("Race must have exactly one of properties 'difficulty' or 'puzzle_id'")
+	errRaceNotFound      = errors.New("Race not found")
+	errRaceStarted       = errors.New("Race has already started")
+	errRaceNotStarted    = errors.New("Race has not started yet")
+	errRaceFinished      = errors.New("Race is already finished")
+	errRaceNotCreator    = errors.New("Only the creator of the race can start it")
+	errRaceTooFewPlayers = errors.New("Race needs at least two players")
+	errRaceUnsolvable    = errors.New("Race puzzle must have exactly one solution")
+	errRaceAssist        = errors.New("Race puzzles cannot be solved or hinted")
+)
+
+// raceRequest is the body of a request to create a race from generated givens of a difficulty or from the givens
+// of a puzzle of the user
+type raceRequest struct {
+	Difficulty string `json:"difficulty"`
+	PuzzleID   uint32 `json:"puzzle_id"`
+}
+
+// Races are kept in memory for raceRetention once finished, so that their players can see the result, and for
+// raceExpiration at most if they are never finished
+const (
+	raceRetention  = 10 * time.Minute
+	raceExpiration = 24 * time.Hour
+)
+
+// raceNameBytes is the number of random bytes in the name of the copy of a player, which encode to 12 characters
+const raceNameBytes = 9
+
+// race is a competition between users solving copies of the same givens
+// Races are kept in memory, while the copies of the players are regular puzzles that outlive the race
+type race struct {
+	ID         uint32        `json:"id"`
+	CreatorID  uint32        `json:"creator_id"`
+	Givens     string        `json:"givens"`
+	Variants   string        `json:"variants,omitempty"`
+	Difficulty string        `json:"difficulty"`
+	StartedAt  *time.Time    `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at"`
+	WinnerID   uint32        `json:"winner_id,omitempty"`
+	Players    []*racePlayer `json:"players"`
+
+	createdAt time.Time
+	solution  sudoku.Grid
+	regions   string
+	parity    string
+	cages     []models.Cage
+	rules     sudoku.Rules
+}
+
+// racePlayer is the progress of a user in a race
+// Filled is the number of cells filled besides the givens and Mistakes counts values that differ from the solution
+type racePlayer struct {
+	UserID        uint32     `json:"user_id"`
+	PuzzleID      uint32     `json:"puzzle_id"`
+	Filled        int        `json:"filled"`
+	Mistakes      int        `json:"mistakes"`
+	SolvedAt      *time.Time `json:"solved_at"`
+	ElapsedMillis int64      `json:"elapsed_millis"`
+}
+
+// races holds the races by id, and by the puzzles of their players
+// mu also guards the fields of every race, so that the first correct completion is the only winner
+var races = struct {
+	mu       sync.Mutex
+	nextID   uint32
+	byID     map[uint32]*race
+	byPuzzle map[uint32]*race
+}{byID: map[uint32]*race{}, byPuzzle: map[uint32]*race{}}
+
+// CreateRace creates a race and joins the user to it
+func CreateRace(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Read from request body and unmarshal into raceRequest. If err, return status code 422.
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Generate givens of the difficulty, or collect the givens, variants and cages of the puzzle of uid, return
+		   status code 422 if err
+		5. Solve the givens under the rules of the variants, return status code 422 if they do not have exactly one
+		   solution
+		6. Save a copy of the givens for uid, return status code 422 if err
+		7. Return status 201 with the race
+	*/
+
+	request := raceRequest{}
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = json.Unmarshal(body, &request)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if (request.Difficulty == "") == (request.PuzzleID == 0) {
+		responses.ERROR(w, http.StatusUnprocessableEntity, errInvalidRace)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	var givens sudoku.Grid
+
+	// Copies of a puzzle of the user keep its variants and cages, while generated givens are classic
+	variant := models.Puzzle{}
+	var cages []models.Cage
+
+	if request.Difficulty != "" {
+		difficulty, err := sudoku.ParseDifficulty(request.Difficulty)
+
+		if err == nil {
+			givens, err = sudoku.Generate(difficulty, time.Now().UnixNano())
+		}
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+	} else {
+		puzzle, boards, err := findPuzzleBoards(db, request.PuzzleID, uid)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		cages, err = findPuzzleCages(db, puzzle)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		_, givens = coopGrids(puzzle, boards)
+		variant = puzzle
+	}
+
+	rules, err := sudoku.PuzzleRules(variant, cages)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	solution, err := rules.Solve(givens)
+
+	if err != nil || !solution.Unique {
+		responses.ERROR(w, http.StatusUnprocessableEntity, errRaceUnsolvable)
+		return
+	}
+
+	grade, err := rules.Rate(givens)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	now := time.Now()
+
+	races.mu.Lock()
+	evictRacesLocked(now)
+	races.nextID++
+	rc := &race{
+		ID:         races.nextID,
+		CreatorID:  uid,
+		Givens:     givens.String(),
+		Variants:   variant.Variants,
+		Difficulty: string(grade.Difficulty),
+		Players:    []*racePlayer{},
+		createdAt:  now,
+		solution:   solution.Grid,
+		regions:    variant.Regions,
+		parity:     variant.Parity,
+		cages:      cages,
+		rules:      rules,
+	}
+	races.byID[rc.ID] = rc
+	races.mu.Unlock()
+
+	_, err = joinRace(db, rc, uid)
+
+	if err != nil {
+		races.mu.Lock()
+		delete(races.byID, rc.ID)
+		races.mu.Unlock()
+
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/races/%d", r.Host, rc.ID))
+	responses.JSON(w, http.StatusCreated, snapshotRace(rc))
+}
+
+// GetRace fetches a race by id with the progress of its players
+func GetRace(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (raceID) from route variables, return status code 400 if err or if there is no such race
+		2. Return status 200 with the race
+	*/
+
+	rc, err := findRace(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, snapshotRace(rc))
+}
+
+// JoinRace joins the user to a race by id that has not started, giving them their own copy of the givens
+func JoinRace(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (raceID) from route variables, return status code 400 if err or if there is no such race
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Return status 200 with the copy of uid if they already joined
+		5. Return status code 409 if the race has started
+		6. Save a copy of the givens for uid, return status code 422 if err
+		7. Return status 201 with the copy
+	*/
+
+	rc, err := findRace(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	races.mu.Lock()
+	started := rc.StartedAt != nil
+	var joined *racePlayer
+
+	for _, p := range rc.Players {
+		if p.UserID == uid {
+			joined = p
+		}
+	}
+
+	races.mu.Unlock()
+
+	if joined != nil {
+		puzzle, err := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db).FindByID(joined.PuzzleID, uid)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+
+		responses.JSON(w, http.StatusOK, puzzle)
+		return
+	}
+
+	if started {
+		responses.ERROR(w, http.StatusConflict, errRaceStarted)
+		return
+	}
+
+	puzzle, err := joinRace(db, rc, uid)
+
+	if err == errRaceStarted {
+		responses.ERROR(w, http.StatusConflict, err)
+		return
+	}
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/puzzles/%d", r.Host, puzzle.ID))
+	responses.JSON(w, http.StatusCreated, puzzle)
+}
+
+// StartRace starts a race by id, after which its players can fill their boards and no one else can join
+func StartRace(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (raceID) from route variables, return status code 400 if err or if there is no such race
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Return status code 403 if uid did not create the race
+		4. Return status code 409 if the race has started, or 422 if it has less than two players
+		5. Publish the start of the race and return status 200 with the race
+	*/
+
+	rc, err := findRace(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	races.mu.Lock()
+
+	switch {
+	case rc.CreatorID != uid:
+		races.mu.Unlock()
+		responses.ERROR(w, http.StatusForbidden, errRaceNotCreator)
+		return
+	case rc.StartedAt != nil:
+		races.mu.Unlock()
+		responses.ERROR(w, http.StatusConflict, errRaceStarted)
+		return
+	case len(rc.Players) < 2:
+		races.mu.Unlock()
+		responses.ERROR(w, http.StatusUnprocessableEntity, errRaceTooFewPlayers)
+		return
+	}
+
+	now := time.Now()
+	rc.StartedAt = &now
+	snapshot := snapshotRaceLocked(rc)
+	events.Events.Publish(raceTopic(rc.ID), events.Event{Type: raceStart, Data: snapshot})
+	races.mu.Unlock()
+
+	responses.JSON(w, http.StatusOK, snapshot)
+}
+
+// GetRaceEvents streams the events of a race by id as Server-Sent Events, starting with the current race
+// Events are join, start and progress with the player, and winner with the race once a player solves their board
+func GetRaceEvents(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (raceID) from route variables, return status code 400 if err or if there is no such race
+		2. Subscribe to the events of the race
+		3. Stream the race and its events until the client disconnects, return status code 500 if the response cannot
+		   stream, and only log errors once the stream has started
+	*/
+
+	rc, err := findRace(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Subscribe and snapshot while holding the lock, so that no event falls between them
+	races.mu.Lock()
+	ch, unsubscribe := events.Events.Subscribe(raceTopic(rc.ID))
+	snapshot := events.Event{Type: raceSnapshot, Data: snapshotRaceLocked(rc)}
+	races.mu.Unlock()
+
+	defer unsubscribe()
+
+	err = events.Stream(w, r, ch, &snapshot)
+
+	// Once the stream has started, its status code is sent and the error can only be logged
+	switch {
+	case err == events.ErrNotFlushable:
+		responses.ERROR(w, http.StatusInternalServerError, err)
+	case err != nil:
+		log.Println(err)
+	}
+}
+
+// checkRaceMove returns an error if the puzzle belongs to a race that does not accept moves, because it has not
+// started or is already finished
+func checkRaceMove(puzzleID uint32) error {
+	races.mu.Lock()
+	defer races.mu.Unlock()
+
+	rc, ok := races.byPuzzle[puzzleID]
+
+	switch {
+	case !ok:
+		return nil
+	case rc.StartedAt == nil:
+		return errRaceNotStarted
+	case rc.FinishedAt != nil:
+		return errRaceFinished
+	}
+
+	return nil
+}
+
+// recordRaceMove updates the progress of the player whose puzzle holds grid after the boards of changed were set, if
+// the puzzle belongs to a race, and declares the player the winner if grid is the solution and no one else has finished
+func recordRaceMove(changed []models.Board, grid sudoku.Grid) {
+	if len(changed) == 0 {
+		return
+	}
+
+	races.mu.Lock()
+	defer races.mu.Unlock()
+
+	puzzleID := changed[0].PuzzleID
+	rc, ok := races.byPuzzle[puzzleID]
+
+	if !ok || rc.StartedAt == nil {
+		return
+	}
+
+	var player *racePlayer
+
+	for _, p := range rc.Players {
+		if p.PuzzleID == puzzleID {
+			player = p
+		}
+	}
+
+	givens, err := sudoku.Parse(rc.Givens)
+
+	if player == nil || err != nil {
+		return
+	}
+
+	player.Filled = grid.Filled() - givens.Filled()
+
+	for _, board := range changed {
+		if board.Value != 0 && board.Value != rc.solution[board.BoardRow-1][board.BoardCol-1] {
+			player.Mistakes++
+		}
+	}
+
+	now := time.Now()
+	player.ElapsedMillis = now.Sub(*rc.StartedAt).Nanoseconds() / int64(time.Millisecond)
+	topic := raceTopic(rc.ID)
+
+	won := rc.FinishedAt == nil && rc.rules.Solved(grid)
+
+	if won {
+		player.SolvedAt = &now
+		rc.FinishedAt = &now
+		rc.WinnerID = player.UserID
+	}
+
+	events.Events.Publish(topic, events.Event{Type: raceProgress, Data: *player})
+
+	if won {
+		events.Events.Publish(topic, events.Event{Type: raceWinner, Data: snapshotRaceLocked(rc)})
+	}
+}
+
+// joinRace saves a copy of the givens of rc for userID and adds the user to the players of rc
+func joinRace(db *gorm.DB, rc *race, userID uint32) (models.Puzzle, error) {
+	givens, err := sudoku.Parse(rc.Givens)
+
+	if err != nil {
+		return models.Puzzle{}, err
+	}
+
+	// Names are unique, and race ids start over when the server restarts, so each copy gets a random name
+	name, err := security.NewShareCode()
+
+	if err != nil {
+		return models.Puzzle{}, err
+	}
+
+	puzzle := models.Puzzle{
+		Name:       "Race " + name[:raceNameBytes*4/3],
+		Difficulty: rc.Difficulty,
+		UserID:     userID,
+		Givens:     givens,
+		Variants:   rc.Variants,
+		Regions:    rc.regions,
+		Parity:     rc.parity,
+	}
+
+	puzzle.PreparePuzzle()
+	err = puzzle.ValidatePuzzle("")
+
+	if err != nil {
+		return models.Puzzle{}, err
+	}
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzle, err = repo.Save(puzzle)
+
+	if err != nil {
+		return models.Puzzle{}, err
+	}
+
+	invalidatePuzzleLists(userID)
+
+	if len(rc.cages) > 0 {
+		_, err = crud.CagesCRUDService.NewCagesCRUD(db).SaveAll(puzzle.ID, rc.cages)
+
+		if err != nil {
+			deletePuzzleCopy(repo, puzzle)
+			return models.Puzzle{}, err
+		}
+	}
+
+	races.mu.Lock()
+
+	// The race may have started while the copy was saved, which then belongs to no race
+	if rc.StartedAt != nil {
+		races.mu.Unlock()
+		deletePuzzleCopy(repo, puzzle)
+		return models.Puzzle{}, errRaceStarted
+	}
+
+	player := &racePlayer{UserID: userID, PuzzleID: puzzle.ID}
+	rc.Players = append(rc.Players, player)
+	races.byPuzzle[puzzle.ID] = rc
+	events.Events.Publish(raceTopic(rc.ID), events.Event{Type: raceJoin, Data: *player})
+	races.mu.Unlock()
+
+	return puzzle, nil
+}
+
+// evictRacesLocked removes the races that finished more than raceRetention before now, or were created more than
+// raceExpiration before now, and ends their streams. The copies of their players become regular puzzles
+// Callers must hold races.mu
+func evictRacesLocked(now time.Time) {
+	for id, rc := range races.byID {
+		finished := rc.FinishedAt != nil && now.Sub(*rc.FinishedAt) > raceRetention
+
+		if !finished && now.Sub(rc.createdAt) <= raceExpiration {
+			continue
+		}
+
+		delete(races.byID, id)
+
+		for _, p := range rc.Players {
+			delete(races.byPuzzle, p.PuzzleID)
+		}
+
+		events.Events.Close(raceTopic(id))
+	}
+}
+
+// findRace returns the race with the id in the route variables
+func findRace(r *http.Request) (*race, error) {
+	routeVariables := mux.Vars(r)
+	raceID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	races.mu.Lock()
+	defer races.mu.Unlock()
+
+	rc, ok := races.byID[uint32(raceID)]
+
+	if !ok {
+		return nil, errRaceNotFound
+	}
+
+	return rc, nil
+}
+
+// snapshotRace returns a copy of rc that can be encoded while the race goes on
+func snapshotRace(rc *race) race {
+	races.mu.Lock()
+	defer races.mu.Unlock()
+
+	return snapshotRaceLocked(rc)
+}
+
+// snapshotRaceLocked is snapshotRace for callers that already hold races.mu
+func snapshotRaceLocked(rc *race) race {
+	snapshot := *rc
+	snapshot.Players = make([]*racePlayer, len(rc.Players))
+
+	for i, p := range rc.Players {
+		player := *p
+		snapshot.Players[i] = &player
+	}
+
+	return snapshot
+}
+
+// raceTopic is the topic of the events of a race
+func raceTopic(raceID uint32) string {
+	return "races/" + strconv.Itoa(int(raceID))
+}
@@ -0,0 +1,406 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/events"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// addRace adds a race between the players to the races in memory, and returns a function that removes it
+func addRace(players ...*racePlayer) (*race, func()) {
+	solution, _ := sudoku.Parse(testSolution)
+
+	races.mu.Lock()
+	defer races.mu.Unlock()
+
+	races.nextID++
+	rc := &race{ID: races.nextID, CreatorID: players[0].UserID, Givens: testGivens, Players: players, createdAt: time.Now(), solution: solution}
+	races.byID[rc.ID] = rc
+
+	for _, p := range players {
+		races.byPuzzle[p.PuzzleID] = rc
+	}
+
+	return rc, func() {
+		races.mu.Lock()
+		defer races.mu.Unlock()
+
+		delete(races.byID, rc.ID)
+
+		for _, p := range players {
+			delete(races.byPuzzle, p.PuzzleID)
+		}
+	}
+}
+
+// raceCopyName matches the random name of the copy of a player in a race
+type raceCopyName struct{}
+
+func (raceCopyName) Match(v driver.Value) bool {
+	name, ok := v.(string)
+	return ok && strings.HasPrefix(name, "Race ") && len(name) == len("Race ")+raceNameBytes*4/3
+}
+
+// ========== CREATERACE() ========== //
+func TestCreateRaceIfSuccessfulFromPuzzle(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+
+	races.mu.Lock()
+	raceID := races.nextID + 1
+	races.mu.Unlock()
+
+	expectPuzzleWithBoards(s, 125, uid, testGivens)
+	expectPuzzleInsert(s, 300, raceCopyName{}, uid)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("POST", "/races", bytes.NewBuffer([]byte(`{"puzzle_id": 125}`)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	CreateRace(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	response := race{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		races.mu.Lock()
+		delete(races.byID, response.ID)
+		delete(races.byPuzzle, 300)
+		races.mu.Unlock()
+	}()
+
+	if response.ID != raceID || response.CreatorID != uid || len(response.Players) != 1 || response.Players[0].PuzzleID != 300 {
+		t.Errorf("Error: handler returned unexpected race: %s", rr.Body.String())
+	}
+
+	// The copy of the creator cannot be filled before the race starts
+	if err = checkRaceMove(300); err != errRaceNotStarted {
+		t.Errorf("Error: checkRaceMove returned: %v, expected: %v", err, errRaceNotStarted)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestCreateRaceIfInvalidRequest(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+
+	req, err := http.NewRequest("POST", "/races", bytes.NewBuffer([]byte(`{"difficulty": "easy", "puzzle_id": 125}`)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	CreateRace(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+// ========== STARTRACE() ========== //
+func TestStartRaceIfTooFewPlayers(t *testing.T) {
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+
+	rc, remove := addRace(&racePlayer{UserID: uid, PuzzleID: 445})
+	defer remove()
+
+	// Initialize struct with modified interfaces
+	auth.TokenService = &tokenMock{}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("/races/%d/start", rc.ID), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprint(rc.ID)})
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartRace(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+func TestJoinRaceIfStartedDeletesCopy(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(101)
+
+	rc, remove := addRace(&racePlayer{UserID: 100, PuzzleID: 445})
+	defer remove()
+
+	// The race starts while the copy is saved, so the copy belongs to no race and is deleted again
+	now := time.Now()
+	rc.StartedAt = &now
+
+	expectPuzzleInsert(s, 300, raceCopyName{}, uid)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("DELETE").
+		WithArgs(300, uid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	_, err := joinRace(s.DB, rc, uid)
+
+	if err != errRaceStarted {
+		t.Errorf("Error: joinRace returned: %v, expected: %v", err, errRaceStarted)
+	}
+
+	if len(rc.Players) != 1 {
+		t.Errorf("Error: joinRace added a player to a started race")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestEvictRacesLocked(t *testing.T) {
+	now := time.Now()
+	finishedAt := now.Add(-raceRetention - time.Minute)
+	recentlyFinishedAt := now.Add(-time.Minute)
+
+	finished, removeFinished := addRace(&racePlayer{UserID: 100, PuzzleID: 445})
+	defer removeFinished()
+	finished.FinishedAt = &finishedAt
+
+	recent, removeRecent := addRace(&racePlayer{UserID: 100, PuzzleID: 446})
+	defer removeRecent()
+	recent.FinishedAt = &recentlyFinishedAt
+
+	stale, removeStale := addRace(&racePlayer{UserID: 100, PuzzleID: 447})
+	defer removeStale()
+	stale.createdAt = now.Add(-raceExpiration - time.Minute)
+
+	active, removeActive := addRace(&racePlayer{UserID: 100, PuzzleID: 448})
+	defer removeActive()
+
+	// Streams of evicted races end
+	ch, unsubscribe := events.Events.Subscribe(raceTopic(finished.ID))
+	defer unsubscribe()
+
+	races.mu.Lock()
+	evictRacesLocked(now)
+
+	for _, rc := range []*race{finished, stale} {
+		if _, ok := races.byID[rc.ID]; ok {
+			t.Errorf("Error: race %d was not evicted", rc.ID)
+		}
+	}
+
+	for _, rc := range []*race{recent, active} {
+		if _, ok := races.byID[rc.ID]; !ok {
+			t.Errorf("Error: race %d was evicted", rc.ID)
+		}
+	}
+
+	races.mu.Unlock()
+
+	// A puzzle of an evicted race is a regular puzzle again
+	if err := checkRaceMove(445); err != nil {
+		t.Errorf("Error: checkRaceMove returned: %v, expected nil", err)
+	}
+
+	if _, ok := <-ch; ok {
+		t.Errorf("Error: stream of evicted race was not closed")
+	}
+}
+
+// ========== GETRACEEVENTS() ========== //
+
+// brokenStream is a response that can stream but whose client is gone, so that every write fails
+type brokenStream struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (b *brokenStream) Write(data []byte) (int, error) {
+	b.writes++
+	return 0, errors.New("Broken pipe")
+}
+
+func TestGetRaceEventsIfStreamBreaks(t *testing.T) {
+	rc, remove := addRace(&racePlayer{UserID: 100, PuzzleID: 445})
+	defer remove()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/races/%d/events", rc.ID), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprint(rc.ID)})
+	rr := &brokenStream{ResponseRecorder: httptest.NewRecorder()}
+
+	// Execute function to be tested
+	GetRaceEvents(rr, req)
+
+	// The stream started with status code 200, so the failed snapshot is the only write
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	if rr.writes != 1 {
+		t.Errorf("Error: handler wrote %d times, expected 1", rr.writes)
+	}
+}
+func TestGetRaceEventsIfFirstCompletionWins(t *testing.T) {
+	s := tests.CreateSuite()
+	winner := uint32(100)
+	puzzleID := uint32(445)
+
+	now := time.Now()
+	rc, remove := addRace(&racePlayer{UserID: winner, PuzzleID: puzzleID}, &racePlayer{UserID: 200, PuzzleID: 446})
+	defer remove()
+
+	races.mu.Lock()
+	rc.StartedAt = &now
+	races.mu.Unlock()
+
+	// The winner fills (1, 3), the only empty cell of their copy, with its solution 4
+	expectOwnedPuzzle(s, puzzleID, winner, "")
+	expectPuzzleBoards(s, puzzleID, "530"+testSolution[3:])
+	expectBoardUpdate(s, puzzleID, winner, 1, 3, 0, 4)
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+			AddRow(7, now, 50, 0, puzzleID, winner))
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return winner, nil
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/races/{id}/events", GetRaceEvents)
+	router.HandleFunc("/boards", UpdateBoard)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/races/%d/events", server.URL, rc.ID))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+	br := bufio.NewReader(resp.Body)
+
+	snapshot := race{}
+
+	if eventType := readEvent(t, br, &snapshot); eventType != raceSnapshot || len(snapshot.Players) != 2 {
+		t.Fatalf("Error: received %s event: %+v, expected race with 2 players", eventType, snapshot)
+	}
+
+	req, err := http.NewRequest("PUT", server.URL+"/boards?puzzle_id=445&board_row=1&board_col=3", bytes.NewBuffer([]byte(`{"value": 4}`)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update.Body.Close()
+
+	if update.StatusCode != http.StatusOK {
+		t.Fatalf("Error: update returned status code: %v, expected: %v", update.StatusCode, http.StatusOK)
+	}
+
+	progress := racePlayer{}
+
+	if eventType := readEvent(t, br, &progress); eventType != raceProgress || progress.UserID != winner || progress.Filled != 51 || progress.SolvedAt == nil {
+		t.Fatalf("Error: received %s event: %+v, expected solved progress of the winner", eventType, progress)
+	}
+
+	finished := race{}
+
+	if eventType := readEvent(t, br, &finished); eventType != raceWinner || finished.WinnerID != winner || finished.FinishedAt == nil {
+		t.Fatalf("Error: received %s event: %+v, expected race won by %d", eventType, finished, winner)
+	}
+
+	// The other player can no longer fill their copy
+	if err = checkRaceMove(446); err != errRaceFinished {
+		t.Errorf("Error: checkRaceMove returned: %v, expected: %v", err, errRaceFinished)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
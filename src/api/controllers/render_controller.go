@@ -0,0 +1,452 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/patrickmn/go-cache"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/render"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// maxBookletPuzzles is the largest number of puzzles in a single booklet
+const maxBookletPuzzles = 60
+
+// errInvalidBookletIDs is returned when the ids of a booklet are missing or invalid
+var errInvalidBookletIDs = fmt.Errorf("Booklet ids must be a comma separated list of 1 to %d puzzle ids", maxBookletPuzzles)
+
+// RenderPuzzle draws a puzzle by id as a printable SVG image or PDF document
+// Givens are bold and the values entered by the player are lighter, and the notes query parameter adds pencil marks
+func RenderPuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Parse the format and notes query parameters, which default to 'svg' and false, return status code 400 if err
+		3. Get uid (userID) from request, if not authorized, return status code 401
+		4. Connect to the DB, return status code 500 if err
+		5. Fetch the puzzle owned by uid, its boards and the rules of its variants, return status code 400 if err
+		6. Return status 200 with the rendered puzzle
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	q := r.URL.Query()
+	format, err := parseRenderFormat(q.Get("format"))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts := render.Options{}
+
+	if q.Get("notes") != "" {
+		opts.Notes, err = strconv.ParseBool(q.Get("notes"))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, err := findRenderPuzzle(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeRendered(w, format, fmt.Sprintf("puzzle-%d", puzzleID), []render.Puzzle{puzzle}, opts)
+}
+
+// RenderBooklet draws the puzzles at the ids query parameter as a printable booklet, with per_page puzzles on each
+// page and the pages of solutions after the puzzles if solutions is set
+func RenderBooklet(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Parse the ids, format, per_page, notes and solutions query parameters, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Fetch every puzzle owned by uid, its boards and the rules of its variants, return status code 400 if err
+		5. If solutions is set, solve the givens of every puzzle, return status code 422 if a puzzle has no solution
+		6. Return status 200 with the rendered booklet
+	*/
+
+	q := r.URL.Query()
+	puzzleIDs := []uint32{}
+
+	for _, s := range strings.Split(q.Get("ids"), ",") {
+		puzzleID, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, errInvalidBookletIDs)
+			return
+		}
+
+		puzzleIDs = append(puzzleIDs, uint32(puzzleID))
+	}
+
+	if len(puzzleIDs) > maxBookletPuzzles {
+		responses.ERROR(w, http.StatusBadRequest, errInvalidBookletIDs)
+		return
+	}
+
+	format, err := parseRenderFormat(q.Get("format"))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts := render.Options{PerPage: 4}
+
+	if q.Get("per_page") != "" {
+		opts.PerPage, err = strconv.Atoi(q.Get("per_page"))
+
+		if err == nil {
+			err = opts.Check()
+		}
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, render.ErrInvalidPerPage)
+			return
+		}
+	}
+
+	if q.Get("notes") != "" {
+		opts.Notes, err = strconv.ParseBool(q.Get("notes"))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if q.Get("solutions") != "" {
+		opts.Solutions, err = strconv.ParseBool(q.Get("solutions"))
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzles := []render.Puzzle{}
+
+	for _, puzzleID := range puzzleIDs {
+		puzzle, err := findRenderPuzzle(db, puzzleID, uid)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusBadRequest, err)
+			return
+		}
+
+		puzzles = append(puzzles, puzzle)
+	}
+
+	if opts.Solutions {
+		for i, puzzle := range puzzles {
+			solution, err := puzzle.Rules.Solve(puzzle.Givens)
+
+			if err != nil {
+				responses.ERROR(w, http.StatusUnprocessableEntity, fmt.Errorf("%s: %s", puzzle.Title, err))
+				return
+			}
+
+			puzzles[i].Solution = solution.Grid
+		}
+	}
+
+	writeRendered(w, format, "booklet", puzzles, opts)
+}
+
+// GetPuzzleThumbnail draws the current values of a puzzle by id as a small PNG image for previews
+// The ETag of the image changes whenever a board of the puzzle changes, so that clients only download it again then
+func GetPuzzleThumbnail(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Parse the size query parameter, which defaults to 180 pixels, return status code 400 if err
+		3. Get uid (userID) from request, if not authorized, return status code 401
+		4. Connect to the DB, return status code 500 if err
+		5. Execute FindByID to check that the puzzle belongs to uid, and fetch the cages of a killer puzzle, return status
+		   code 400 if err
+		6. Execute FindLatestMoveIDByPuzzleID and FindLatestUpdateByPuzzleID for the ETag, return status code 400 if err
+		7. If the ETag matches If-None-Match, return status code 304
+		8. Read the image from the cache, or fetch the boards and draw them with the rules of the variants, return status
+		   code 400 if err
+		9. Return status 200 with the image
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	side := render.DefaultThumbnailSize
+
+	if s := r.URL.Query().Get("size"); s != "" {
+		side, err = strconv.Atoi(s)
+
+		if err != nil || side < render.MinThumbnailSize || side > render.MaxThumbnailSize {
+			responses.ERROR(w, http.StatusBadRequest, render.ErrInvalidThumbnailSize)
+			return
+		}
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, err := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db).FindByID(uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cages, err := findPuzzleCages(db, puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repoBoards := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	moveID, err := repoBoards.FindLatestMoveIDByPuzzleID(uint32(puzzleID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	latest, err := repoBoards.FindLatestUpdateByPuzzleID(uint32(puzzleID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	etag := thumbnailETag(puzzle, cages, moveID, latest, side)
+
+	// The ETag is private to the owner of the puzzle, and must be checked again before every use
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	cacheString := "thumbnails/" + etag
+
+	if it, found := caching.Cache.Get(cacheString); found {
+		writeThumbnail(w, it.([]byte))
+		return
+	}
+
+	boards, err := repoBoards.FindAllByPuzzleID(uint32(puzzleID))
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rules, err := sudoku.PuzzleRules(puzzle, cages)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	thumbnail, err := buildRenderPuzzle(puzzle, boards, rules)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	err = render.WriteThumbnail(buf, thumbnail, side)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	caching.Cache.Set(cacheString, buf.Bytes(), cache.DefaultExpiration)
+	writeThumbnail(w, buf.Bytes())
+}
+
+// thumbnailETag identifies the thumbnail of puzzle with sides of side pixels
+// Every value entered, undone or redone adds a move, so the latest move tells entries apart even within one second.
+// Givens are not moves and are told apart by the latest update of the boards, and the variants and cages by their
+// hash, since cages are edited in place
+func thumbnailETag(puzzle models.Puzzle, cages []models.Cage, moveID uint32, latest time.Time, side int) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%s", puzzle.Variants, puzzle.Regions, puzzle.Parity)
+
+	for _, cage := range cages {
+		fmt.Fprintf(h, "|%d:%s:%d", cage.ID, cage.Cells, cage.Sum)
+	}
+
+	return fmt.Sprintf("\"%d-%d-%d-%x-%d\"", puzzle.ID, moveID, latest.UnixNano(), h.Sum64(), side)
+}
+
+// writeThumbnail writes a PNG thumbnail with status code 200
+func writeThumbnail(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// parseRenderFormat parses the format query parameter of a render, which defaults to svg
+func parseRenderFormat(s string) (render.Format, error) {
+	if s == "" {
+		return render.FormatSVG, nil
+	}
+
+	return render.ParseFormat(s)
+}
+
+// findRenderPuzzle fetches a puzzle owned by userID with its boards and variant rules, and prepares it for drawing
+func findRenderPuzzle(db *gorm.DB, puzzleID uint32, userID uint32) (render.Puzzle, error) {
+	puzzle, boards, err := findPuzzleBoards(db, puzzleID, userID)
+
+	if err != nil {
+		return render.Puzzle{}, err
+	}
+
+	return newRenderPuzzle(db, puzzle, boards)
+}
+
+// newRenderPuzzle prepares puzzle and its boards for drawing, with the rules of its variants
+// Puzzles without marked givens, such as those imported with their values only, draw every value as a given
+func newRenderPuzzle(db *gorm.DB, puzzle models.Puzzle, boards []models.Board) (render.Puzzle, error) {
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		return render.Puzzle{}, err
+	}
+
+	return buildRenderPuzzle(puzzle, boards, rules)
+}
+
+// buildRenderPuzzle prepares puzzle and its boards for drawing with rules
+func buildRenderPuzzle(puzzle models.Puzzle, boards []models.Board, rules sudoku.Rules) (render.Puzzle, error) {
+	givens, err := sudoku.GivensFromBoards(puzzle, boards)
+
+	if err != nil {
+		return render.Puzzle{}, err
+	}
+
+	entries, err := sudoku.FromBoards(puzzle, boards)
+
+	if err != nil {
+		return render.Puzzle{}, err
+	}
+
+	if givens.Filled() == 0 {
+		givens = entries
+	}
+
+	notes := make([][][]int, givens.Size())
+
+	for r := range notes {
+		notes[r] = make([][]int, givens.Size())
+	}
+
+	for _, board := range boards {
+		notes[board.BoardRow-1][board.BoardCol-1] = board.NoteValues()
+	}
+
+	return render.Puzzle{
+		Title:   html.UnescapeString(puzzle.Name),
+		Givens:  givens,
+		Entries: entries,
+		Notes:   notes,
+		Rules:   rules,
+	}, nil
+}
+
+// writeRendered draws puzzles in format and writes them inline as filename, with the extension of format
+// Nothing is written until the drawing succeeds, so that an error still gets a json response with status code 422
+func writeRendered(w http.ResponseWriter, format render.Format, filename string, puzzles []render.Puzzle, opts render.Options) {
+	buf := &bytes.Buffer{}
+	err := render.Write(buf, format, puzzles, opts)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s.%s\"", filename, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
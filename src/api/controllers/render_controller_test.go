@@ -0,0 +1,393 @@
+package controllers
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/render"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== RENDERPUZZLE() ========== //
+func TestRenderPuzzleIfSuccessfulSVG(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoardsEntered(s, puzzleID, testGivens, "..4"+strings.Repeat(".", len(testGivens)-3))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/render?format=svg", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	RenderPuzzle(rr, req)
+
+	// Check status code, headers and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "image/svg+xml" {
+		t.Errorf("Error: handler returned Content-Type: %v", contentType)
+	}
+
+	if disposition := rr.Header().Get("Content-Disposition"); disposition != `inline; filename="puzzle-125.svg"` {
+		t.Errorf("Error: handler returned Content-Disposition: %v", disposition)
+	}
+
+	if !strings.Contains(rr.Body.String(), `font-weight="normal" fill="#595959" text-anchor="middle">4</text>`) {
+		t.Errorf("Error: handler did not draw the entered value")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestRenderPuzzleIfInvalidFormat(t *testing.T) {
+	expectedStatusCode := http.StatusBadRequest
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/125/render?format=png", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "125",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	RenderPuzzle(rr, req)
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	if !strings.Contains(rr.Body.String(), render.ErrInvalidFormat.Error()) {
+		t.Errorf("Error: handler returned unexpected body: %s", rr.Body.String())
+	}
+}
+
+// ========== RENDERBOOKLET() ========== //
+func TestRenderBookletIfSuccessfulPDF(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+
+	expectPuzzleWithBoards(s, 125, uid, testGivens)
+	expectPuzzleWithBoards(s, 126, uid, testGivens)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/puzzles/booklet?ids=125,126&format=pdf&per_page=2&solutions=true", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	RenderBooklet(rr, req)
+
+	// Check status code, headers and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/pdf" {
+		t.Errorf("Error: handler returned Content-Type: %v", contentType)
+	}
+
+	// Both puzzles fit on one page, followed by one page of solutions
+	if body := rr.Body.String(); !strings.HasPrefix(body, "%PDF-") || !strings.Contains(body, "/Count 2") {
+		t.Errorf("Error: handler returned unexpected document")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestRenderBookletIfInvalidQuery(t *testing.T) {
+	expectedStatusCode := http.StatusBadRequest
+
+	for _, query := range []string{"", "ids=", "ids=125,abc", "ids=125&per_page=3"} {
+		// Build request and response objects
+		req, err := http.NewRequest("GET", "/puzzles/booklet?"+query, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+
+		// Execute function to be tested
+		RenderBooklet(rr, req)
+
+		if status := rr.Code; status != expectedStatusCode {
+			t.Errorf("Error: handler returned status code for '%s': %v, expected: %v", query, status, expectedStatusCode)
+		}
+	}
+}
+
+// ========== GETPUZZLETHUMBNAIL() ========== //
+
+// getPuzzleThumbnail runs GetPuzzleThumbnail as uid with the If-None-Match header set to etag if it is not empty
+func getPuzzleThumbnail(t *testing.T, s tests.Suite, uid uint32, etag string) *httptest.ResponseRecorder {
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	req, err := http.NewRequest("GET", "/puzzles/125/thumbnail.png?size=90", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "125",
+	})
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	rr := httptest.NewRecorder()
+
+	GetPuzzleThumbnail(rr, req)
+
+	return rr
+}
+
+// expectThumbnailVersion mocks the queries for the latest move on a puzzle and the latest change to its boards
+func expectThumbnailVersion(s tests.Suite, puzzleID uint32, moveID uint32, latest time.Time) {
+	s.Mock.ExpectQuery("SELECT MAX\\(id\\)").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}).AddRow(moveID))
+
+	s.Mock.ExpectQuery("SELECT MAX\\(updated_at\\)").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"updated_at"}).AddRow(latest))
+}
+
+func TestGetPuzzleThumbnailIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	latest := time.Date(2020, 5, 17, 9, 30, 0, 0, time.UTC)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	expectThumbnailVersion(s, puzzleID, 7, latest)
+	expectPuzzleBoards(s, puzzleID, testGivens)
+
+	// Draw the image again rather than reading it from a previous run
+	caching.DeletePrefix("thumbnails/")
+
+	rr := getPuzzleThumbnail(t, s, uid, "")
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "image/png" {
+		t.Errorf("Error: handler returned Content-Type: %v", contentType)
+	}
+
+	if etag := rr.Header().Get("ETag"); etag != thumbnailETag(models.Puzzle{ID: puzzleID}, nil, 7, latest, 90) {
+		t.Errorf("Error: handler returned ETag: %v", etag)
+	}
+
+	img, err := png.Decode(rr.Body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bounds := img.Bounds(); bounds.Dx() != 90 || bounds.Dy() != 90 {
+		t.Errorf("Error: handler returned image of bounds: %v", bounds)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetPuzzleThumbnailIfNotModified(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	latest := time.Date(2020, 5, 18, 9, 30, 0, 0, time.UTC)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// The boards are not fetched when the client already has the image
+	expectThumbnailVersion(s, puzzleID, 7, latest)
+
+	rr := getPuzzleThumbnail(t, s, uid, thumbnailETag(models.Puzzle{ID: puzzleID}, nil, 7, latest, 90))
+
+	if status := rr.Code; status != http.StatusNotModified {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusNotModified)
+	}
+
+	if rr.Body.Len() != 0 {
+		t.Errorf("Error: handler returned a body: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetPuzzleThumbnailIfMovedWithinSameSecond(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	latest := time.Date(2020, 5, 19, 9, 30, 0, 0, time.UTC)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// A second move in the same second leaves the latest update of the boards as it was
+	expectThumbnailVersion(s, puzzleID, 8, latest)
+	expectPuzzleBoards(s, puzzleID, testGivens)
+
+	rr := getPuzzleThumbnail(t, s, uid, thumbnailETag(models.Puzzle{ID: puzzleID}, nil, 7, latest, 90))
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if etag := rr.Header().Get("ETag"); etag != thumbnailETag(models.Puzzle{ID: puzzleID}, nil, 8, latest, 90) {
+		t.Errorf("Error: handler returned ETag: %v", etag)
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestThumbnailETagIfCagesOrVariantsChange(t *testing.T) {
+	latest := time.Date(2020, 5, 19, 9, 30, 0, 0, time.UTC)
+	puzzle := models.Puzzle{ID: 125, Variants: models.VariantKiller}
+	cages := []models.Cage{{ID: 1, Cells: "1112", Sum: 3}}
+	etag := thumbnailETag(puzzle, cages, 7, latest, 90)
+
+	// Cages are edited in place and keep their ids
+	edited := []models.Cage{{ID: 1, Cells: "1112", Sum: 4}}
+
+	if thumbnailETag(puzzle, edited, 7, latest, 90) == etag {
+		t.Errorf("Error: ETag did not change with the sum of a cage")
+	}
+
+	jigsaw := models.Puzzle{ID: 125, Variants: models.VariantJigsaw}
+
+	if thumbnailETag(jigsaw, nil, 7, latest, 90) == thumbnailETag(puzzle, nil, 7, latest, 90) {
+		t.Errorf("Error: ETag did not change with the variants")
+	}
+}
+
+func TestGetPuzzleThumbnailIfInvalidSize(t *testing.T) {
+	expectedStatusCode := http.StatusBadRequest
+
+	req, err := http.NewRequest("GET", "/puzzles/125/thumbnail.png?size=4000", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "125",
+	})
+
+	rr := httptest.NewRecorder()
+
+	GetPuzzleThumbnail(rr, req)
+
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
@@ -0,0 +1,376 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// errSessionEntries is returned when a session is started on a puzzle whose boards already hold entries, which
+// would be counted in the time of the session without being played in it
+var errSessionEntries = errors.New("Session can only be started on a puzzle whose boards hold only its givens")
+
+// StartSession starts a game session for a puzzle by id, or returns the session already in progress
+func StartSession(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Fetch the puzzle owned by uid and its boards, return status code 404 if uid owns no such puzzle or 400 if err
+		5. Return status 200 with the session of the puzzle that is not completed, if any
+		6. Check that the boards hold only the givens of the puzzle, return status code 409 if not
+		7. Otherwise save a new session, return status code 422 if err
+		8. Solve the givens of the puzzle once for the moves of the session
+		9. Return status 201 with the created session
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	puzzle, boards, err := findPuzzleBoards(db, uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, puzzleErrorStatus(err), err)
+		return
+	}
+
+	repo := crud.SessionsCRUDService.NewSessionsCRUD(db)
+	session, err := repo.FindActiveByPuzzleID(uint32(puzzleID))
+
+	if err == nil {
+		responses.JSON(w, http.StatusOK, session.Refresh(time.Now()))
+		return
+	}
+
+	if !gorm.IsRecordNotFoundError(err) {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	for _, board := range boards {
+		if !board.Given && board.Value != 0 {
+			responses.ERROR(w, http.StatusConflict, errSessionEntries)
+			return
+		}
+	}
+
+	session = models.Session{
+		PuzzleID: uint32(puzzleID),
+		UserID:   uid,
+	}
+
+	session.PrepareSession()
+	err = session.ValidateSession("")
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	session, err = repo.Save(session)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Moves are checked against the solution without solving the puzzle again, or counted without mistakes if the
+	// rules cannot be read
+	rules, err := puzzleRules(db, puzzle)
+
+	if err != nil {
+		log.Println(err)
+	} else {
+		solvePuzzle(puzzle, boards, rules)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/sessions/%d", r.Host, session.ID))
+	responses.JSON(w, http.StatusCreated, session)
+}
+
+// GetSessions fetches all game sessions of the user for a puzzle by id, starting from the latest
+func GetSessions(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindAllByPuzzleID, return status code 400 if err
+		5. Return status 200 with the sessions and their elapsed time
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.SessionsCRUDService.NewSessionsCRUD(db)
+	sessions, err := repo.FindAllByPuzzleID(uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+
+	for i := range sessions {
+		sessions[i].Refresh(now)
+	}
+
+	responses.JSON(w, http.StatusOK, sessions)
+}
+
+// GetSession fetches a game session by id
+func GetSession(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (sessionID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID, return status code 400 if err
+		5. Return status 200 with the session and its elapsed time
+	*/
+
+	updateSession(w, r, "")
+}
+
+// PauseSession stops the timer of a game session by id
+func PauseSession(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (sessionID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID, return status code 400 if err
+		5. Check that the session is running, return status code 422 if it is paused or completed
+		6. Execute Update, return status code 400 if err
+		7. Return status 200 with the paused session
+	*/
+
+	updateSession(w, r, "pause")
+}
+
+// ResumeSession restarts the timer of a paused game session by id
+func ResumeSession(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (sessionID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID, return status code 400 if err
+		5. Check that the session is paused, return status code 422 if it is running or completed
+		6. Execute Update, return status code 400 if err
+		7. Return status 200 with the resumed session
+	*/
+
+	updateSession(w, r, "resume")
+}
+
+// updateSession fetches the session in the route variables and applies action to it
+// An empty action only fetches the session
+func updateSession(w http.ResponseWriter, r *http.Request, action string) {
+	routeVariables := mux.Vars(r)
+	sessionID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.SessionsCRUDService.NewSessionsCRUD(db)
+	session, err := repo.FindByID(uint32(sessionID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+
+	if action == "" {
+		responses.JSON(w, http.StatusOK, session.Refresh(now))
+		return
+	}
+
+	err = session.ValidateSession(action)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	switch action {
+	case "pause":
+		session.Pause(now)
+	case "resume":
+		session.Resume(now)
+	}
+
+	_, err = repo.Update(session)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, session)
+}
+
+// solutionExpiration is how long the solution of a puzzle is cached for the moves of its game sessions
+const solutionExpiration = 24 * time.Hour
+
+// puzzleSolution is the cached solution of the givens of a puzzle under its rules
+// Grid is nil if the givens cannot be read or do not have a unique solution
+type puzzleSolution struct {
+	Grid sudoku.Grid
+}
+
+// solvePuzzle solves the givens of the boards of puzzle under rules and caches the solution for the puzzle
+func solvePuzzle(puzzle models.Puzzle, boards []models.Board, rules sudoku.Rules) puzzleSolution {
+	solved := puzzleSolution{}
+	givens, err := sudoku.GivensFromBoards(puzzle, boards)
+
+	if err == nil {
+		var solution sudoku.Solution
+		solution, err = rules.Solve(givens)
+
+		if err == nil && solution.Unique {
+			solved.Grid = solution.Grid
+		}
+	}
+
+	caching.Cache.Set("solutions/"+strconv.Itoa(int(puzzle.ID)), solved, solutionExpiration)
+
+	return solved
+}
+
+// findPuzzleSolution returns the cached solution of a puzzle, solving the givens of boards under rules if it has
+// expired or was never cached, such as after a restart
+func findPuzzleSolution(puzzle models.Puzzle, boards []models.Board, rules sudoku.Rules) puzzleSolution {
+	if it, found := caching.Cache.Get("solutions/" + strconv.Itoa(int(puzzle.ID))); found {
+		return it.(puzzleSolution)
+	}
+
+	return solvePuzzle(puzzle, boards, rules)
+}
+
+// invalidatePuzzleSolution removes the cached solution of a puzzle whose givens or rules have changed
+func invalidatePuzzleSolution(puzzleID uint32) {
+	caching.Cache.Delete("solutions/" + strconv.Itoa(int(puzzleID)))
+}
+
+// recordMove counts the board updates of changed as moves of the session of puzzle that is in progress, if any
+// A value that differs from the unique solution of the givens of boards under rules is counted as a mistake, and the
+// session is completed once grid is solved. A paused session is resumed by the moves
+func recordMove(db *gorm.DB, puzzle models.Puzzle, boards []models.Board, changed []models.Board, grid sudoku.Grid, rules sudoku.Rules) (*models.Session, error) {
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	repo := crud.SessionsCRUDService.NewSessionsCRUD(db)
+	session, err := repo.FindActiveByPuzzleID(puzzle.ID)
+
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session.Resume(now)
+	session.Moves += len(changed)
+
+	// Moves on puzzles whose givens cannot be read or solved are not counted as mistakes
+	solution := findPuzzleSolution(puzzle, boards, rules)
+
+	for _, board := range changed {
+		if board.Value != 0 && solution.Grid.Size() == grid.Size() && solution.Grid[board.BoardRow-1][board.BoardCol-1] != board.Value {
+			session.Mistakes++
+		}
+	}
+
+	if rules.Solved(grid) {
+		session.Complete(now)
+	}
+
+	_, err = repo.Update(session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if session.CompletedAt != nil {
+		invalidateLeaderboards()
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== STARTSESSION() ========== //
+func TestStartSessionIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoards(s, puzzleID, testGivens)
+
+	// No session is in progress
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/sessions", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartSession(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	session := models.Session{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	if session.ID != 7 || session.Status != models.SessionActive || session.UserID != uid {
+		t.Errorf("Error: handler returned unexpected session: %s", rr.Body.String())
+	}
+
+	// The givens are solved once for the moves of the session
+	if it, found := caching.Cache.Get("solutions/125"); !found || it.(puzzleSolution).Grid.String() != testSolution {
+		t.Errorf("Error: handler did not cache the solution of the puzzle")
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestStartSessionIfBoardsHoldEntries(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusConflict
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// The first empty cell was already filled before the session
+	entered := "..4" + testSolution[3:]
+	expectPuzzleBoardsEntered(s, puzzleID, testGivens, entered)
+
+	// No session is in progress
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/sessions", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartSession(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no session was saved
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestStartSessionIfAlreadyActive(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusOK
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	sessionID := uint32(7)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// The session in progress holds an entry, which does not stop it from being returned
+	expectPuzzleBoardsEntered(s, puzzleID, testGivens, "..4"+testSolution[3:])
+
+	sessionRows := s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(sessionID, time.Now().Add(-time.Minute), 1, 0, puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(sessionRows)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/sessions", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartSession(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	session := models.Session{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	if session.ID != sessionID || session.Status != models.SessionActive || session.ElapsedMillis < int64(time.Minute/time.Millisecond) {
+		t.Errorf("Error: handler returned unexpected session: %s, expected session %d in progress", rr.Body.String(), sessionID)
+	}
+
+	// ensure no session was saved
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestStartSessionIfNotOwner(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusNotFound
+	uid := uint32(200)
+	puzzleID := uint32(125)
+
+	// The puzzle belongs to another user, so it is not found for uid
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/sessions", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	StartSession(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no session was looked up or saved
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
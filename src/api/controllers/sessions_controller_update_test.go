@@ -0,0 +1,350 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// expectSession mocks the query for session 7 of uid, started a minute ago and paused since pausedAt if it is not nil
+func expectSession(s tests.Suite, uid uint32, pausedAt *time.Time) {
+	sessionRows := s.Mock.NewRows([]string{"id", "started_at", "paused_at", "paused_millis", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(7, time.Now().Add(-time.Minute), pausedAt, 0, 3, 1, 125, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(7, uid).
+		WillReturnRows(sessionRows)
+}
+
+// serveSession calls handler for session 7 as uid and returns the recorded response
+func serveSession(t *testing.T, s tests.Suite, uid uint32, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("PUT", "/sessions/7", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "7",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	handler(rr, req)
+
+	return rr
+}
+
+// ========== GETSESSION() ========== //
+func TestGetSessionIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+
+	expectSession(s, uid, nil)
+
+	rr := serveSession(t, s, uid, GetSession)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	session := models.Session{}
+
+	if err := json.Unmarshal(rr.Body.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	if session.ID != 7 || session.Status != models.SessionActive || session.ElapsedMillis < int64(time.Minute/time.Millisecond) {
+		t.Errorf("Error: handler returned unexpected session: %s", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetSessionIfNotOwner(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusBadRequest
+	uid := uint32(200)
+
+	// Session 7 belongs to another user, so it is not found for uid
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(7, uid).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	rr := serveSession(t, s, uid, GetSession)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+// ========== PAUSESESSION() ========== //
+func TestPauseSessionIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+
+	expectSession(s, uid, nil)
+
+	// UPDATE `sessions` SET `completed_at` = ?, `elapsed_millis` = ?, `mistakes` = ?, `moves` = ?, `paused_at` = ?,
+	// `paused_millis` = ?, `updated_at` = ? WHERE (id=?)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(nil, sqlmock.AnyArg(), 1, 3, sqlmock.AnyArg(), 0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	rr := serveSession(t, s, uid, PauseSession)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	session := models.Session{}
+
+	if err := json.Unmarshal(rr.Body.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	if session.Status != models.SessionPaused || session.PausedAt == nil {
+		t.Errorf("Error: handler returned unexpected session: %s, expected paused session", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestPauseSessionIfPaused(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+
+	pausedAt := time.Now().Add(-30 * time.Second)
+	expectSession(s, uid, &pausedAt)
+
+	rr := serveSession(t, s, uid, PauseSession)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure the session was not updated
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== RESUMESESSION() ========== //
+func TestResumeSessionIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+
+	// The session was paused for the last 30 seconds of its minute
+	pausedAt := time.Now().Add(-30 * time.Second)
+	expectSession(s, uid, &pausedAt)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(nil, sqlmock.AnyArg(), 1, 3, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	rr := serveSession(t, s, uid, ResumeSession)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	session := models.Session{}
+
+	if err := json.Unmarshal(rr.Body.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	if session.Status != models.SessionActive || session.PausedAt != nil || session.PausedMillis < 30000 {
+		t.Errorf("Error: handler returned unexpected session: %s, expected running session paused for 30s", rr.Body.String())
+	}
+
+	// The pause is not counted in the elapsed time
+	if session.ElapsedMillis >= int64(time.Minute/time.Millisecond) {
+		t.Errorf("Error: handler returned elapsed millis: %d, expected less than a minute", session.ElapsedMillis)
+	}
+
+	// ensure all expectations have been met
+	if err := s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestResumeSessionIfRunning(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+
+	expectSession(s, uid, nil)
+
+	rr := serveSession(t, s, uid, ResumeSession)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
+
+// ========== RECORDMOVE() ========== //
+func TestRecordMoveIfFinalBoardCompletesSession(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(125)
+	uid := uint32(100)
+	puzzle := models.Puzzle{ID: puzzleID, Size: 9, BoxRows: 3, BoxCols: 3, UserID: uid}
+
+	// The 4 written in (1, 3) fills the last empty cell with its solution
+	grid, err := sudoku.Parse(testSolution)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := sudoku.PuzzleRules(puzzle, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidatePuzzleSolution(puzzleID)
+	defer invalidatePuzzleSolution(puzzleID)
+
+	boards := []models.Board{}
+
+	for i, ch := range testGivens {
+		value := int(ch - '0')
+		boards = append(boards, models.Board{BoardRow: i/9 + 1, BoardCol: i%9 + 1, Value: value, Given: value != 0, PuzzleID: puzzleID})
+	}
+
+	changed := []models.Board{{BoardRow: 1, BoardCol: 3, Value: 4, PuzzleID: puzzleID}}
+
+	sessionRows := s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(7, time.Now().Add(-time.Minute), 50, 0, puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(sessionRows)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 51, nil, 0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	session, err := recordMove(s.DB, puzzle, boards, changed, grid, rules)
+
+	if err != nil {
+		t.Fatalf("Error: recordMove returned: %v, expected nil", err)
+	}
+
+	if session == nil || session.Status != models.SessionSolved || session.CompletedAt == nil || session.Mistakes != 0 {
+		t.Errorf("Error: recordMove returned session: %+v, expected solved session without mistakes", session)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestRecordMoveIfFinalBoardIsMistake(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(126)
+	uid := uint32(100)
+	puzzle := models.Puzzle{ID: puzzleID, Size: 9, BoxRows: 3, BoxCols: 3, UserID: uid}
+
+	// The 9 written in (1, 3) fills the last empty cell, but is not its solution
+	grid, err := sudoku.Parse("539" + testSolution[3:])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := sudoku.PuzzleRules(puzzle, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidatePuzzleSolution(puzzleID)
+	defer invalidatePuzzleSolution(puzzleID)
+
+	boards := []models.Board{}
+
+	for i, ch := range testGivens {
+		value := int(ch - '0')
+		boards = append(boards, models.Board{BoardRow: i/9 + 1, BoardCol: i%9 + 1, Value: value, Given: value != 0, PuzzleID: puzzleID})
+	}
+
+	changed := []models.Board{{BoardRow: 1, BoardCol: 3, Value: 9, PuzzleID: puzzleID}}
+
+	sessionRows := s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(8, time.Now().Add(-time.Minute), 50, 0, puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(sessionRows)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(nil, sqlmock.AnyArg(), 1, 51, nil, 0, sqlmock.AnyArg(), 8).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	session, err := recordMove(s.DB, puzzle, boards, changed, grid, rules)
+
+	if err != nil {
+		t.Fatalf("Error: recordMove returned: %v, expected nil", err)
+	}
+
+	if session == nil || session.Status != models.SessionActive || session.Mistakes != 1 {
+		t.Errorf("Error: recordMove returned session: %+v, expected session in progress with a mistake", session)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/caching"
+	"github.com/quattad/sudokubuddy-backend/src/api/config"
+	"github.com/quattad/sudokubuddy-backend/src/api/crud"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/security"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+var errNoGivensToClone = errors.New("Puzzle has no givens to clone")
+
+// puzzleShare is the share code of a puzzle and the path that other users clone the puzzle from
+type puzzleShare struct {
+	ShareCode string `json:"share_code"`
+	CloneURI  string `json:"clone_uri"`
+}
+
+// cloneRequest is the body of a request to clone a puzzle, which may rename the copy
+type cloneRequest struct {
+	Name string `json:"name"`
+}
+
+// SharePuzzle makes a puzzle by id shareable and returns its share code, keeping the code if it is already shared
+func SharePuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID to check that the puzzle belongs to uid, return status code 400 if err
+		5. Return status 200 with the share code if the puzzle is already shared
+		6. Otherwise generate a share code, return status code 500 if err
+		7. Execute UpdateShareCode, return status code 400 if err
+		8. Return status 201 with the share code
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	puzzle, err := repo.FindByID(uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if puzzle.ShareCode != nil {
+		responses.JSON(w, http.StatusOK, newPuzzleShare(*puzzle.ShareCode))
+		return
+	}
+
+	code, err := security.NewShareCode()
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, err = repo.UpdateShareCode(uint32(puzzleID), uid, &code)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	caching.Cache.Delete("puzzles/" + strconv.Itoa(int(puzzleID)))
+
+	responses.JSON(w, http.StatusCreated, newPuzzleShare(code))
+}
+
+// RevokePuzzleShare removes the share code of a puzzle by id, so that it can no longer be cloned
+// Copies that were already cloned are not affected
+func RevokePuzzleShare(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract id (puzzleID) from route variables and convert to uint32, return status code 400 if err
+		2. Get uid (userID) from request, if not authorized, return status code 401
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByID to check that the puzzle belongs to uid, return status code 400 if err
+		5. Execute UpdateShareCode with no code, return status code 400 if err
+		6. Return status 200 with number of rows updated
+	*/
+
+	routeVariables := mux.Vars(r)
+	puzzleID, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	_, err = repo.FindByID(uint32(puzzleID), uid)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := repo.UpdateShareCode(uint32(puzzleID), uid, nil)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	caching.Cache.Delete("puzzles/" + strconv.Itoa(int(puzzleID)))
+
+	responses.JSON(w, http.StatusOK, rows)
+}
+
+// ClonePuzzle copies the givens of the puzzle shared with the code in the route variables into a new puzzle of the user
+// The copy is named after the shared puzzle and the user, unless the request body sets a name
+func ClonePuzzle(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Get uid (userID) from request, if not authorized, return status code 401
+		2. Read the optional name from request body, return status code 422 if err
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByShareCode, return status code 400 if err
+		5. Execute FindAllByPuzzleID and collect the givens and the cages of a killer puzzle, return status code 400 if err
+		6. Return status code 422 if the shared puzzle has no givens and no cages
+		7. Save a puzzle of uid with the givens, variants and cages, return status code 422 if err
+		8. Return status 201 with the copy
+	*/
+
+	code := mux.Vars(r)["code"]
+	uid, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	request := cloneRequest{}
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &request)
+
+		if err != nil {
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.PuzzlesCRUDService.NewPuzzlesCRUD(db)
+	shared, err := repo.FindByShareCode(code)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	repoBoards := crud.BoardsCRUDService.NewBoardsCRUD(db)
+	boards, err := repoBoards.FindAllByPuzzleID(shared.ID)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The clone takes the size of the shared puzzle
+	givens, err := sudoku.GivensFromBoards(shared, boards)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Killer puzzles can be solved from their cages alone
+	cages, err := findPuzzleCages(db, shared)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if givens.Filled() == 0 && len(cages) == 0 {
+		responses.ERROR(w, http.StatusUnprocessableEntity, errNoGivensToClone)
+		return
+	}
+
+	// Clones of clones rank with the puzzle that was first shared
+	origin := shared.OriginID
+
+	if origin == 0 {
+		origin = shared.ID
+	}
+
+	puzzle := models.Puzzle{
+		Name:       request.Name,
+		Difficulty: shared.Difficulty,
+		Score:      shared.Score,
+		Seed:       shared.Seed,
+		OriginID:   origin,
+		UserID:     uid,
+		Givens:     givens,
+		Variants:   shared.Variants,
+		Regions:    shared.Regions,
+		Parity:     shared.Parity,
+	}
+
+	// Names are unique, so the name of the shared puzzle is shortened to fit the user. The stored name is already
+	// escaped, and PreparePuzzle escapes it again
+	if puzzle.Name == "" {
+		puzzle.Name = fitPuzzleName(html.UnescapeString(shared.Name), fmt.Sprintf(" #%d", uid))
+	}
+
+	puzzle.PreparePuzzle()
+	err = puzzle.ValidatePuzzle("")
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	puzzle, err = repo.Save(puzzle)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	invalidatePuzzleLists(uid)
+
+	// A killer clone without its cages cannot be solved, so it is deleted along with its boards
+	if len(cages) > 0 {
+		_, err = crud.CagesCRUDService.NewCagesCRUD(db).SaveAll(puzzle.ID, cages)
+
+		if err != nil {
+			deletePuzzleCopy(repo, puzzle)
+			responses.ERROR(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/puzzles/%d", r.Host, puzzle.ID))
+	responses.JSON(w, http.StatusCreated, puzzle)
+}
+
+// maxPuzzleNameLength is the size of the name column of the puzzles table
+const maxPuzzleNameLength = 20
+
+// fitPuzzleName shortens name so that name followed by suffix still fits the name column once PreparePuzzle has
+// escaped it, and returns both. Characters are only cut from name, and never from within an escaped character
+func fitPuzzleName(name string, suffix string) string {
+	runes := []rune(name)
+
+	for len(runes) > 0 && len([]rune(html.EscapeString(string(runes)+suffix))) > maxPuzzleNameLength {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + suffix
+}
+
+// newPuzzleShare returns the share of a puzzle shared with code
+func newPuzzleShare(code string) puzzleShare {
+	return puzzleShare{
+		ShareCode: code,
+		CloneURI:  "/puzzles/clone/" + code,
+	}
+}
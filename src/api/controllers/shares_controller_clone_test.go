@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// expectSharedPuzzle mocks the query for a puzzle of ownerID shared with code, whose boards hold givens and a value
+// of 4 entered by the owner at (1, 3)
+func expectSharedPuzzle(s tests.Suite, puzzleID uint32, ownerID uint32, code string, givens string) {
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "difficulty", "share_code", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", "easy", code, time.Now(), time.Now(), ownerID)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(code).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoardsEntered(s, puzzleID, givens, "..4"+strings.Repeat(".", 78))
+}
+
+// ========== CLONEPUZZLE() ========== //
+func TestClonePuzzleIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	expectSharedPuzzle(s, 125, 200, code, testGivens)
+	expectPuzzleInsert(s, 126, "testpuzzle1 #100", uid)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/clone/"+code, bytes.NewBuffer(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"code": code,
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ClonePuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	puzzle := models.Puzzle{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &puzzle); err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.ID != 126 || puzzle.UserID != uid || puzzle.ShareCode != nil {
+		t.Errorf("Error: handler returned puzzle: %s, expected unshared puzzle 126 of user %d", rr.Body.String(), uid)
+	}
+
+	// The clone ranks with the shared puzzle
+	if puzzle.OriginID != 125 {
+		t.Errorf("Error: handler returned origin: %d, expected 125", puzzle.OriginID)
+	}
+
+	// The value entered by the owner is not a given and is not copied
+	if puzzle.Givens[0][0] != 5 || puzzle.Givens[0][2] != 0 {
+		t.Errorf("Error: handler returned givens: %v, expected only the givens of the shared puzzle", puzzle.Givens)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestClonePuzzleIfNamed(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	expectSharedPuzzle(s, 125, 200, code, testGivens)
+	expectPuzzleInsert(s, 126, "my copy", uid)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/clone/"+code, bytes.NewBuffer([]byte(`{"name": "my copy"}`)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"code": code,
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute function to be tested
+	ClonePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestClonePuzzleIfEscapedName(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	// The stored name is escaped once, and is shortened so that it still fits once escaped again
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "difficulty", "share_code", "created_at", "updated_at", "user_id"}).
+		AddRow(125, "Tom &amp; Jerry&#39;s", "easy", code, time.Now(), time.Now(), 200)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(code).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoards(s, 125, testGivens)
+	expectPuzzleInsert(s, 126, "Tom &amp; Jerry #100", uid)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/clone/"+code, bytes.NewBuffer(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"code": code,
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ClonePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestClonePuzzleIfRevoked(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusBadRequest
+	uid := uint32(100)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	// No puzzle is shared with a revoked code
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(code).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/clone/"+code, bytes.NewBuffer(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"code": code,
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ClonePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+
+	// ensure no puzzle was saved
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestClonePuzzleIfCagesNotSavedDeletesCopy(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusUnprocessableEntity
+	uid := uint32(100)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	// The shared puzzle is a killer puzzle with a single cage
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "difficulty", "variants", "share_code", "created_at", "updated_at", "user_id"}).
+		AddRow(125, "testpuzzle1", "easy", "killer", code, time.Now(), time.Now(), 200)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(code).
+		WillReturnRows(puzzleRows)
+
+	expectPuzzleBoards(s, 125, testGivens)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(125).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "cells", "sum", "puzzle_id"}).AddRow(1, "1112", 3, 125))
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WillReturnResult(sqlmock.NewResult(126, 1))
+	s.Mock.ExpectCommit()
+
+	for i := 0; i < 81; i++ {
+		s.Mock.ExpectBegin()
+		s.Mock.ExpectExec("INSERT INTO").
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+		s.Mock.ExpectCommit()
+	}
+
+	// The cages cannot be saved, so the clone is deleted along with its boards
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WillReturnError(errors.New("cages table is unavailable"))
+	s.Mock.ExpectRollback()
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("DELETE").
+		WithArgs(126, uid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/clone/"+code, bytes.NewBuffer(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"code": code,
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	ClonePuzzle(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	// ensure the clone was deleted
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== SHAREPUZZLE() ========== //
+func TestSharePuzzleIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusCreated
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// UPDATE `puzzles` SET `share_code` = ?, `updated_at` = ? WHERE (id=? AND user_id=?)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/share", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	SharePuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	share := puzzleShare{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &share); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(share.ShareCode) != 24 || share.CloneURI != "/puzzles/clone/"+share.ShareCode {
+		t.Errorf("Error: handler returned share: %s, expected a 24 character code", rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestSharePuzzleIfShared(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusOK
+	uid := uint32(100)
+	puzzleID := uint32(125)
+	code := "Rk9vYmFyQmF6UXV4UXV1eA"
+
+	// The existing code is returned without updating the puzzle
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "share_code", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", code, time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("POST", "/puzzles/125/share", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	SharePuzzle(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	share := puzzleShare{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &share); err != nil {
+		t.Fatal(err)
+	}
+
+	if share.ShareCode != code {
+		t.Errorf("Error: handler returned share code: %s, expected: %s", share.ShareCode, code)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== REVOKEPUZZLESHARE() ========== //
+func TestRevokePuzzleShareIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	expectedStatusCode := http.StatusOK
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	puzzleRows := s.Mock.NewRows([]string{"id", "name", "share_code", "created_at", "updated_at", "user_id"}).
+		AddRow(puzzleID, "testpuzzle1", "Rk9vYmFyQmF6UXV4UXV1eA", time.Now(), time.Now(), uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID, uid).
+		WillReturnRows(puzzleRows)
+
+	// The share code is set to NULL
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(nil, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.Mock.ExpectCommit()
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("DELETE", "/puzzles/125/share", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(puzzleID)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	RevokePuzzleShare(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v, body: %s", status, expectedStatusCode, rr.Body.String())
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
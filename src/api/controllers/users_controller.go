@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/patrickmn/go-cache"
@@ -16,8 +18,11 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/database"
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
 	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
 )
 
+var errOtherUserStats = errors.New("Statistics can only be fetched by their own user")
+
 // GetUser fetches a user by id
 func GetUser(w http.ResponseWriter, r *http.Request) {
 	/*
@@ -281,3 +286,87 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 	responses.JSON(w, http.StatusOK, rows)
 
 }
+
+// GetUserStats fetches the statistics of a user by id, which only the user can see
+func GetUserStats(w http.ResponseWriter, r *http.Request) {
+	/*
+		1. Extract userID from route variables using mux.Vars() and convert to uint32, return status code 400 if err
+		2. Extract the tokenID and check if it matches the userID. If it does not match, return status code 401 unauthorized.
+		3. Connect to the DB, return status code 500 if err
+		4. Execute FindByUserID, return status code 400 if err
+		5. Return status 200 with the statistics, with every difficulty from easiest to hardest
+	*/
+
+	routeVariables := mux.Vars(r)
+	uid, err := strconv.ParseUint(routeVariables["id"], 10, 32)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tokenUID, err := auth.TokenService.ExtractTokenID(r)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if tokenUID != uint32(uid) {
+		responses.ERROR(w, http.StatusUnauthorized, errOtherUserStats)
+		return
+	}
+
+	db, err := database.DBService.Connect(config.DBDRIVER, config.DBURL)
+
+	if err != nil {
+		responses.ERROR(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	defer db.Close()
+
+	repo := crud.StatsCRUDService.NewStatsCRUD(db)
+
+	stats, err := repo.FindByUserID(uint32(uid), time.Now())
+
+	if err != nil {
+		responses.ERROR(w, http.StatusBadRequest, err)
+		return
+	}
+
+	stats.Difficulties = orderDifficultyStats(stats.Difficulties)
+
+	responses.JSON(w, http.StatusOK, stats)
+}
+
+// orderDifficultyStats orders stats from the easiest to the hardest difficulty, adding empty statistics for the
+// difficulties without solved puzzles. Puzzles without a known difficulty come last
+func orderDifficultyStats(stats []models.DifficultyStats) []models.DifficultyStats {
+	byDifficulty := map[string]models.DifficultyStats{}
+
+	for _, s := range stats {
+		byDifficulty[s.Difficulty] = s
+	}
+
+	ordered := []models.DifficultyStats{}
+
+	for _, d := range sudoku.Difficulties {
+		s, ok := byDifficulty[string(d)]
+
+		if !ok {
+			s = models.DifficultyStats{Difficulty: string(d)}
+		}
+
+		ordered = append(ordered, s)
+		delete(byDifficulty, string(d))
+	}
+
+	for _, s := range stats {
+		if _, ok := byDifficulty[s.Difficulty]; ok {
+			ordered = append(ordered, s)
+		}
+	}
+
+	return ordered
+}
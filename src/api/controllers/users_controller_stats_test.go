@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/auth"
+	"github.com/quattad/sudokubuddy-backend/src/api/database"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== GETUSERSTATS() ========== //
+func TestGetUserStatsIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(100)
+
+	s.Mock.ExpectQuery("SELECT count").
+		WithArgs(uid).
+		WillReturnRows(s.Mock.NewRows([]string{"count(*)"}).AddRow(4))
+	s.Mock.ExpectQuery("AS abandoned").
+		WillReturnRows(s.Mock.NewRows([]string{"solved", "abandoned"}).AddRow(3, 1))
+	s.Mock.ExpectQuery("GROUP BY puzzles.difficulty").
+		WithArgs(uid).
+		WillReturnRows(s.Mock.NewRows([]string{"difficulty", "solved", "best_millis", "average_millis"}).
+			AddRow("", 1, 500000, 500000).
+			AddRow("hard", 2, 300000, 350000))
+	s.Mock.ExpectQuery("SELECT completed_at FROM").
+		WithArgs(uid).
+		WillReturnRows(s.Mock.NewRows([]string{"completed_at"}))
+	s.Mock.ExpectQuery("FROM `hints`").
+		WithArgs(uid).
+		WillReturnRows(s.Mock.NewRows([]string{"technique", "count"}).AddRow("hidden_single", 2))
+
+	// Initialize struct with modified interfaces
+	database.DBService = &dbMock{}
+	auth.TokenService = &tokenMock{}
+
+	// Define custom functions
+	mockConnect = func(DBDRIVER, DBURL string) (*gorm.DB, error) {
+		return s.DB, nil
+	}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return uid, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/users/100/stats", bytes.NewBuffer(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": strconv.Itoa(int(uid)),
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetUserStats(rr, req)
+
+	// Check status code and body
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Error: handler returned status code: %v, expected: %v", status, http.StatusOK)
+	}
+
+	stats := models.UserStats{}
+
+	if err = json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Created != 4 || stats.Solved != 3 || stats.Abandoned != 1 || len(stats.Hints) != 1 {
+		t.Errorf("Error: handler returned unexpected stats: %s", rr.Body.String())
+	}
+
+	// Every difficulty is listed from easiest to hardest, followed by puzzles without a difficulty
+	expected := []string{"easy", "medium", "hard", "expert", ""}
+
+	if len(stats.Difficulties) != len(expected) {
+		t.Fatalf("Error: handler returned difficulties: %+v, expected: %v", stats.Difficulties, expected)
+	}
+
+	for i, d := range stats.Difficulties {
+		if d.Difficulty != expected[i] {
+			t.Errorf("Error: handler returned difficulties: %+v, expected: %v", stats.Difficulties, expected)
+			break
+		}
+	}
+
+	if hard := stats.Difficulties[2]; hard.Solved != 2 || hard.BestMillis != 300000 {
+		t.Errorf("Error: handler returned hard stats: %+v, expected 2 solved with best 300000", hard)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestGetUserStatsIfOtherUser(t *testing.T) {
+	expectedStatusCode := http.StatusUnauthorized
+
+	// Initialize struct with modified interfaces
+	auth.TokenService = &tokenMock{}
+
+	mockExtractTokenID = func(r *http.Request) (uint32, error) {
+		return 200, nil
+	}
+
+	// Build request and response objects
+	req, err := http.NewRequest("GET", "/users/100/stats", bytes.NewBuffer(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = mux.SetURLVars(req, map[string]string{
+		"id": "100",
+	})
+
+	rr := httptest.NewRecorder()
+
+	// Execute function to be tested
+	GetUserStats(rr, req)
+
+	// Check status code
+	if status := rr.Code; status != expectedStatusCode {
+		t.Errorf("Error: handler returned status code: %v, expected: %v", status, expectedStatusCode)
+	}
+}
@@ -9,6 +9,18 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
 )
 
+var (
+	// ErrNothingToUndo is returned by Undo when no move of the puzzle is in effect
+	ErrNothingToUndo = errors.New("No move to undo")
+
+	// ErrNothingToRedo is returned by Redo when no move of the puzzle has been undone
+	ErrNothingToRedo = errors.New("No move to redo")
+
+	// ErrBoardGiven is returned when a move is applied to a board that is a given, e.g. when an undo or redo reaches
+	// a move made before the board became a given
+	ErrBoardGiven = errors.New("Board is a given and cannot be modified")
+)
+
 // BoardsCRUDService is a global variable that exposes the methods of the module
 var BoardsCRUDService BoardsCRUDInterface
 
@@ -35,9 +47,18 @@ type BoardsCRUDInterface interface {
 	FindByID(uint32) (models.Board, error)
 	FindByPuzzleIDRowCol(uint32, int, int) (models.Board, error)
 	FindAll(uint32) ([]models.Board, error)
+	FindAllByPuzzleID(uint32) ([]models.Board, error)
+	FindLatestUpdateByPuzzleID(uint32) (time.Time, error)
+	FindLatestMoveIDByPuzzleID(uint32) (uint32, error)
 
 	// Update
-	Update(uint32, models.Board) (int64, error)
+	Update(uint32, uint32, models.Board) (int64, error)
+	Undo(uint32, uint32) (models.Move, error)
+	Redo(uint32, uint32) (models.Move, error)
+	UpdateAll(uint32, uint32, []models.Board) (int64, error)
+	UpdateNotes(uint32, models.Board) (int64, error)
+	UpdateAllNotes(uint32, []models.Board) (int64, error)
+	ClearNotes(uint32) (int64, error)
 
 	// Delete
 	Delete(uint32) (int64, error)
@@ -184,11 +205,353 @@ func (boardsCRUD *BoardsCRUD) FindAll(userID uint32) ([]models.Board, error) {
 	return nil, err
 }
 
+// FindAllByPuzzleID fetches all the cells of a puzzle from the Board table, ordered by row and column
+// Returns an array of models and error if successful, returns empty array and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) FindAllByPuzzleID(puzzleID uint32) ([]models.Board, error) {
+	var err error
+	boards := []models.Board{}
+	done := make(chan bool)
+
+	// If not found or any kind of error, will return false
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = boardsCRUD.db.Debug().Model(&models.Board{}).Where("puzzle_id=?", puzzleID).Order("board_row, board_col").Find(&boards).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	// Boards found
+	if channels.OK(done) {
+		return boards, nil
+	}
+
+	// Other errors
+	return nil, err
+}
+
+// FindLatestUpdateByPuzzleID fetches the time at which a cell of a puzzle last changed, without fetching the cells
+// Returns the time and error if successful, returns the zero time and error if unsuccessful or if the puzzle has no cells
+func (boardsCRUD *BoardsCRUD) FindLatestUpdateByPuzzleID(puzzleID uint32) (time.Time, error) {
+	var err error
+	latest := struct{ UpdatedAt *time.Time }{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = boardsCRUD.db.Debug().Model(&models.Board{}).Select("MAX(updated_at) AS updated_at").Where("puzzle_id=?", puzzleID).Scan(&latest).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) && latest.UpdatedAt != nil {
+		return *latest.UpdatedAt, nil
+	}
+
+	// MAX is null when the puzzle has no cells
+	if err == nil {
+		err = errors.New("Board not found")
+	}
+
+	return time.Time{}, err
+}
+
+// FindLatestMoveIDByPuzzleID fetches the id of the latest move on a puzzle, which grows with every value entered,
+// undone or redone, without fetching the moves
+// Returns the id and error if successful, returns 0 and nil if the puzzle has no moves, and 0 and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) FindLatestMoveIDByPuzzleID(puzzleID uint32) (uint32, error) {
+	var err error
+	latest := struct{ ID *uint32 }{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = boardsCRUD.db.Debug().Model(&models.Move{}).Select("MAX(id) AS id").Where("puzzle_id=?", puzzleID).Scan(&latest).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) {
+		return 0, err
+	}
+
+	// MAX is null when the puzzle has no moves
+	if latest.ID == nil {
+		return 0, nil
+	}
+
+	return *latest.ID, nil
+}
+
 // ========== UPDATE ========== //
 
-// Update takes in a model instance with updated fields and ID, and updates the existing entry in the db that matches ID
-// Returns updated model and error if successful, returns empty array and error if unsuccessful
-func (boardsCRUD *BoardsCRUD) Update(puzzleID uint32, board models.Board) (int64, error) {
+// Update takes in a model instance with updated fields, updates the existing entry in the db that matches the
+// puzzleID, board row and board col, and appends the change to the move log of the puzzle as made by userID
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) Update(puzzleID uint32, userID uint32, board models.Board) (int64, error) {
+	var err error
+	var rows int64
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		move := models.Move{
+			Kind:     models.MoveSet,
+			BoardRow: board.BoardRow,
+			BoardCol: board.BoardCol,
+			NewValue: board.Value,
+			PuzzleID: puzzleID,
+			UserID:   userID,
+		}
+
+		tx := boardsCRUD.db.Debug().Begin()
+
+		if err = tx.Error; err != nil {
+			ch <- false
+			return
+		}
+
+		rows, err = applyMove(tx, &move)
+
+		if err != nil {
+			tx.Rollback()
+			ch <- false
+			return
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) {
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+// Undo reverts the latest move of the puzzle that is in effect and appends an undo move to the move log
+// Returns the appended move and error if successful, returns ErrNothingToUndo if no move is in effect and
+// ErrBoardGiven if the board of the move is now a given
+func (boardsCRUD *BoardsCRUD) Undo(puzzleID uint32, userID uint32) (models.Move, error) {
+	return boardsCRUD.revert(puzzleID, userID, models.MoveUndo)
+}
+
+// Redo repeats the latest undone move of the puzzle and appends a redo move to the move log
+// Returns the appended move and error if successful, returns ErrNothingToRedo if no move was undone and
+// ErrBoardGiven if the board of the move is now a given
+func (boardsCRUD *BoardsCRUD) Redo(puzzleID uint32, userID uint32) (models.Move, error) {
+	return boardsCRUD.revert(puzzleID, userID, models.MoveRedo)
+}
+
+// revert replays the move log of the puzzle to find the move to undo or redo, depending on kind, and applies it
+// within a single transaction
+func (boardsCRUD *BoardsCRUD) revert(puzzleID uint32, userID uint32, kind string) (models.Move, error) {
+	var err error
+	move := models.Move{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		tx := boardsCRUD.db.Debug().Begin()
+
+		if err = tx.Error; err != nil {
+			ch <- false
+			return
+		}
+
+		moves := []models.Move{}
+		err = tx.Model(&models.Move{}).Where("puzzle_id=?", puzzleID).Order("id").Find(&moves).Error
+
+		if err != nil {
+			tx.Rollback()
+			ch <- false
+			return
+		}
+
+		applied, undone := models.MoveStacks(moves)
+
+		switch {
+		case kind == models.MoveUndo && len(applied) > 0:
+			last := applied[len(applied)-1]
+			move = models.Move{BoardRow: last.BoardRow, BoardCol: last.BoardCol, NewValue: last.OldValue, Ref: last.ID}
+		case kind == models.MoveRedo && len(undone) > 0:
+			last := undone[len(undone)-1]
+			move = models.Move{BoardRow: last.BoardRow, BoardCol: last.BoardCol, NewValue: last.NewValue, Ref: last.ID}
+		case kind == models.MoveUndo:
+			err = ErrNothingToUndo
+		default:
+			err = ErrNothingToRedo
+		}
+
+		if err != nil {
+			tx.Rollback()
+			ch <- false
+			return
+		}
+
+		move.Kind = kind
+		move.PuzzleID = puzzleID
+		move.UserID = userID
+
+		if _, err = applyMove(tx, &move); err != nil {
+			tx.Rollback()
+			ch <- false
+			return
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) {
+		return models.Move{}, err
+	}
+
+	return move, nil
+}
+
+// applyMove sets the value of the board of move to move.NewValue within tx and appends move to the move log
+// move.OldValue is read from the board, which stays locked until tx ends. Givens are not moves, so ErrBoardGiven is
+// returned if the board is a given
+func applyMove(tx *gorm.DB, move *models.Move) (int64, error) {
+	current := models.Board{}
+	err := tx.Set("gorm:query_option", "FOR UPDATE").Model(&models.Board{}).Where("puzzle_id=? AND board_row=? AND board_col=?", move.PuzzleID, move.BoardRow, move.BoardCol).Take(&current).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	if current.Given {
+		return 0, ErrBoardGiven
+	}
+
+	rs := tx.Model(&models.Board{}).Where("puzzle_id=? AND board_row=? AND board_col=?", move.PuzzleID, move.BoardRow, move.BoardCol).UpdateColumns(
+		map[string]interface{}{
+			"value":      move.NewValue,
+			"updated_at": time.Now(),
+		},
+	)
+
+	if rs.Error != nil {
+		return 0, rs.Error
+	}
+
+	move.OldValue = current.Value
+	move.CreatedAt = time.Now()
+
+	err = tx.Model(&models.Move{}).Create(move).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rs.RowsAffected, nil
+}
+
+// UpdateAll takes in model instances with updated values, and updates the value and given flag of the matching
+// entries of the puzzle in a single transaction
+// Values entered by userID are appended to the move log like those of Update, while givens are part of the puzzle
+// and are not moves
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) UpdateAll(puzzleID uint32, userID uint32, boards []models.Board) (int64, error) {
+	var err error
+	var rows int64
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		tx := boardsCRUD.db.Debug().Begin()
+
+		if err = tx.Error; err != nil {
+			ch <- false
+			return
+		}
+
+		for _, board := range boards {
+			if !board.Given {
+				move := models.Move{
+					Kind:     models.MoveSet,
+					BoardRow: board.BoardRow,
+					BoardCol: board.BoardCol,
+					NewValue: board.Value,
+					PuzzleID: puzzleID,
+					UserID:   userID,
+				}
+
+				var affected int64
+
+				if affected, err = applyMove(tx, &move); err != nil {
+					tx.Rollback()
+					ch <- false
+					return
+				}
+
+				rows += affected
+				continue
+			}
+
+			rs := tx.Model(&models.Board{}).Where("puzzle_id=? AND board_row=? AND board_col=?", puzzleID, board.BoardRow, board.BoardCol).UpdateColumns(
+				map[string]interface{}{
+					"value":      board.Value,
+					"given":      board.Given,
+					"updated_at": time.Now(),
+				},
+			)
+
+			if err = rs.Error; err != nil {
+				tx.Rollback()
+				ch <- false
+				return
+			}
+
+			rows += rs.RowsAffected
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) {
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+// UpdateNotes takes in a model instance with updated notes, and updates the notes of the existing entry in the db
+// that matches the puzzleID, board row and board col
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) UpdateNotes(puzzleID uint32, board models.Board) (int64, error) {
 	var rs *gorm.DB
 	done := make(chan bool)
 
@@ -197,7 +560,7 @@ func (boardsCRUD *BoardsCRUD) Update(puzzleID uint32, board models.Board) (int64
 
 		rs = boardsCRUD.db.Debug().Model(&models.Board{}).Where("puzzle_id=? AND board_row=? AND board_col=?", puzzleID, board.BoardRow, board.BoardCol).UpdateColumns(
 			map[string]interface{}{
-				"value":      board.Value,
+				"notes":      board.Notes,
 				"updated_at": time.Now(),
 			},
 		)
@@ -210,7 +573,82 @@ func (boardsCRUD *BoardsCRUD) Update(puzzleID uint32, board models.Board) (int64
 	}
 
 	return rs.RowsAffected, nil
+}
+
+// UpdateAllNotes takes in model instances with updated notes, and updates the notes of the matching entries of the
+// puzzle in a single transaction
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) UpdateAllNotes(puzzleID uint32, boards []models.Board) (int64, error) {
+	var err error
+	var rows int64
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		tx := boardsCRUD.db.Debug().Begin()
+
+		if err = tx.Error; err != nil {
+			ch <- false
+			return
+		}
+
+		for _, board := range boards {
+			rs := tx.Model(&models.Board{}).Where("puzzle_id=? AND board_row=? AND board_col=?", puzzleID, board.BoardRow, board.BoardCol).UpdateColumns(
+				map[string]interface{}{
+					"notes":      board.Notes,
+					"updated_at": time.Now(),
+				},
+			)
+
+			if err = rs.Error; err != nil {
+				tx.Rollback()
+				ch <- false
+				return
+			}
+
+			rows += rs.RowsAffected
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			ch <- false
+			return
+		}
 
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) {
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+// ClearNotes takes in a puzzleID and clears the notes of every board of the puzzle
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (boardsCRUD *BoardsCRUD) ClearNotes(puzzleID uint32) (int64, error) {
+	var rs *gorm.DB
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		rs = boardsCRUD.db.Debug().Model(&models.Board{}).Where("puzzle_id=?", puzzleID).UpdateColumns(
+			map[string]interface{}{
+				"notes":      "",
+				"updated_at": time.Now(),
+			},
+		)
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) || rs.Error != nil {
+		return 0, rs.Error
+	}
+
+	return rs.RowsAffected, nil
 }
 
 // ========== DELETE ========== //
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/quattad/sudokubuddy-backend/src/api/models"
 	"github.com/quattad/sudokubuddy-backend/src/api/tests"
@@ -230,3 +231,160 @@ func TestBoardsFindAllIfSuccessful(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+// ========== FINDALLBYPUZZLEID() ========== //
+func TestBoardsFindAllByPuzzleIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(445)
+
+	expectedData := []models.Board{
+		models.Board{
+			ID:       1,
+			BoardRow: 1,
+			BoardCol: 1,
+			Value:    2,
+			PuzzleID: puzzleID,
+		},
+		models.Board{
+			ID:       2,
+			BoardRow: 1,
+			BoardCol: 2,
+			Value:    5,
+			PuzzleID: puzzleID,
+		},
+	}
+
+	rows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "puzzle_id"}).
+		AddRow(expectedData[0].ID, expectedData[0].BoardRow, expectedData[0].BoardCol, expectedData[0].Value, puzzleID).
+		AddRow(expectedData[1].ID, expectedData[1].BoardRow, expectedData[1].BoardCol, expectedData[1].Value, puzzleID)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	boards, err := repo.FindAllByPuzzleID(puzzleID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := json.Marshal(expectedData)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	actual, err := json.Marshal(boards)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	if !utils.JSONEqual(bytes.NewBuffer(expected), bytes.NewBuffer(actual)) {
+		t.Errorf("Actual: %s, expected: %s", actual, expected)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== FINDLATESTUPDATEBYPUZZLEID() ========== //
+func TestBoardsFindLatestUpdateByPuzzleIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(445)
+	expected := time.Date(2020, 5, 17, 9, 30, 0, 0, time.UTC)
+
+	rows := s.Mock.NewRows([]string{"updated_at"}).AddRow(expected)
+
+	s.Mock.ExpectQuery("SELECT MAX\\(updated_at\\)").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	latest, err := repo.FindLatestUpdateByPuzzleID(puzzleID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !latest.Equal(expected) {
+		t.Errorf("Actual: %s, expected: %s", latest, expected)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestBoardsFindLatestUpdateByPuzzleIDIfNoBoards(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(445)
+
+	rows := s.Mock.NewRows([]string{"updated_at"}).AddRow(nil)
+
+	s.Mock.ExpectQuery("SELECT MAX\\(updated_at\\)").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	_, err := repo.FindLatestUpdateByPuzzleID(puzzleID)
+
+	if err == nil || err.Error() != "Board not found" {
+		t.Errorf("Actual error: %v, expected: Board not found", err)
+	}
+}
+
+// ========== FINDLATESTMOVEIDBYPUZZLEID() ========== //
+func TestBoardsFindLatestMoveIDByPuzzleIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(445)
+
+	rows := s.Mock.NewRows([]string{"id"}).AddRow(42)
+
+	s.Mock.ExpectQuery("SELECT MAX\\(id\\)").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	latest, err := repo.FindLatestMoveIDByPuzzleID(puzzleID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest != 42 {
+		t.Errorf("Actual: %d, expected: 42", latest)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestBoardsFindLatestMoveIDByPuzzleIDIfNoMoves(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(445)
+
+	rows := s.Mock.NewRows([]string{"id"}).AddRow(nil)
+
+	s.Mock.ExpectQuery("SELECT MAX\\(id\\)").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	latest, err := repo.FindLatestMoveIDByPuzzleID(puzzleID)
+
+	if err != nil || latest != 0 {
+		t.Errorf("Actual: %d and error: %v, expected: 0 and nil", latest, err)
+	}
+}
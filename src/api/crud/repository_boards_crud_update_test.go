@@ -26,16 +26,31 @@ func TestUpdateIfSuccessful(t *testing.T) {
 		},
 	}
 
-	// 'UPDATE `boards` SET `updated_at` = ?, `value` = ?  WHERE (puzzle_id=? AND board_row=? AND board_col=?)
+	uid := uint32(100)
+
+	// The current board is locked and read for the old value of the move
+	rows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "puzzle_id"}).
+		AddRow(1, boardRow, boardCol, 2, puzzleID)
+
 	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT (.+) FOR UPDATE").
+		WithArgs(puzzleID, boardRow, boardCol).
+		WillReturnRows(rows)
+
+	// 'UPDATE `boards` SET `updated_at` = ?, `value` = ?  WHERE (puzzle_id=? AND board_row=? AND board_col=?)
 	s.Mock.ExpectExec("UPDATE").
 		WithArgs(sqlmock.AnyArg(), expectedValue, puzzleID, boardRow, boardCol).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// INSERT INTO `moves` (`kind`,`board_row`,`board_col`,`old_value`,`new_value`,`ref`,`created_at`,`puzzle_id`,`user_id`)
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs(models.MoveSet, boardRow, boardCol, 2, expectedValue, 0, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	s.Mock.ExpectCommit()
 
 	// Execute function to be tested
 	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
-	rowsUpdated, err := repo.Update(puzzleID, data[0])
+	rowsUpdated, err := repo.Update(puzzleID, uid, data[0])
 
 	if err != nil {
 		t.Errorf("Error: %s, expected nil", err)
@@ -51,3 +66,252 @@ func TestUpdateIfSuccessful(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+func TestUpdateNotesIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	expectedNotes := "139"
+	boardRow := 5
+	boardCol := 4
+
+	data := []models.Board{
+		models.Board{
+			Notes:    expectedNotes,
+			BoardRow: boardRow,
+			BoardCol: boardCol,
+		},
+	}
+
+	// 'UPDATE `boards` SET `notes` = ?, `updated_at` = ?  WHERE (puzzle_id=? AND board_row=? AND board_col=?)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(expectedNotes, sqlmock.AnyArg(), puzzleID, boardRow, boardCol).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	rowsUpdated, err := repo.UpdateNotes(puzzleID, data[0])
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	if rowsUpdated != 1 {
+		t.Errorf("Actual rowsUpdated: %v, expected 1", rowsUpdated)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateAllNotesIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+
+	data := []models.Board{
+		models.Board{
+			Notes:    "12",
+			BoardRow: 1,
+			BoardCol: 1,
+		},
+		models.Board{
+			Notes:    "89",
+			BoardRow: 1,
+			BoardCol: 2,
+		},
+	}
+
+	// Both updates are made within a single transaction
+	s.Mock.ExpectBegin()
+
+	for _, board := range data {
+		s.Mock.ExpectExec("UPDATE").
+			WithArgs(board.Notes, sqlmock.AnyArg(), puzzleID, board.BoardRow, board.BoardCol).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	rowsUpdated, err := repo.UpdateAllNotes(puzzleID, data)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	if rowsUpdated != 2 {
+		t.Errorf("Actual rowsUpdated: %v, expected 2", rowsUpdated)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestClearNotesIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+
+	// 'UPDATE `boards` SET `notes` = ?, `updated_at` = ?  WHERE (puzzle_id=?)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs("", sqlmock.AnyArg(), puzzleID).
+		WillReturnResult(sqlmock.NewResult(0, 81))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	rowsUpdated, err := repo.ClearNotes(puzzleID)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	if rowsUpdated != 81 {
+		t.Errorf("Actual rowsUpdated: %v, expected 81", rowsUpdated)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUpdateAllIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	data := []models.Board{
+		models.Board{
+			Value:    5,
+			Given:    true,
+			BoardRow: 1,
+			BoardCol: 1,
+		},
+		models.Board{
+			Value:    0,
+			BoardRow: 1,
+			BoardCol: 2,
+		},
+	}
+
+	// Both updates are made within a single transaction, and only the cleared entry is a move
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(true, sqlmock.AnyArg(), 5, puzzleID, 1, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectQuery("SELECT (.+) FOR UPDATE").
+		WithArgs(puzzleID, 1, 2).
+		WillReturnRows(s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "puzzle_id"}).AddRow(2, 1, 2, 3, puzzleID))
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), 0, puzzleID, 1, 2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs(models.MoveSet, 1, 2, 3, 0, 0, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	rowsUpdated, err := repo.UpdateAll(puzzleID, uid, data)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	if rowsUpdated != 2 {
+		t.Errorf("Actual rowsUpdated: %v, expected 2", rowsUpdated)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestUndoIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	// Move 2 was undone by move 3, so move 1 is the latest move in effect
+	moveRows := s.Mock.NewRows([]string{"id", "kind", "board_row", "board_col", "old_value", "new_value", "ref", "puzzle_id", "user_id"}).
+		AddRow(1, models.MoveSet, 1, 3, 0, 4, 0, puzzleID, uid).
+		AddRow(2, models.MoveSet, 1, 4, 0, 6, 0, puzzleID, uid).
+		AddRow(3, models.MoveUndo, 1, 4, 6, 0, 2, puzzleID, uid)
+
+	boardRows := s.Mock.NewRows([]string{"id", "board_row", "board_col", "value", "puzzle_id"}).
+		AddRow(3, 1, 3, 4, puzzleID)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(moveRows)
+	s.Mock.ExpectQuery("SELECT (.+) FOR UPDATE").
+		WithArgs(puzzleID, 1, 3).
+		WillReturnRows(boardRows)
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(sqlmock.AnyArg(), 0, puzzleID, 1, 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec("INSERT INTO").
+		WithArgs(models.MoveUndo, 1, 3, 4, 0, 1, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	move, err := repo.Undo(puzzleID, uid)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if move.ID != 4 || move.Ref != 1 || move.OldValue != 4 || move.NewValue != 0 {
+		t.Errorf("Actual move: %+v, expected move 4 reverting move 1", move)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestRedoIfNothingToRedo(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(445)
+	uid := uint32(100)
+
+	moveRows := s.Mock.NewRows([]string{"id", "kind", "board_row", "board_col", "old_value", "new_value", "ref", "puzzle_id", "user_id"}).
+		AddRow(1, models.MoveSet, 1, 3, 0, 4, 0, puzzleID, uid)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(moveRows)
+	s.Mock.ExpectRollback()
+
+	// Execute function to be tested
+	repo := BoardsCRUDService.NewBoardsCRUD(s.DB)
+	_, err := repo.Redo(puzzleID, uid)
+
+	if err != ErrNothingToRedo {
+		t.Errorf("Error: %v, expected: %v", err, ErrNothingToRedo)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,235 @@
+package crud
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
+)
+
+// CagesCRUDService is a global variable that exposes the methods of the module
+var CagesCRUDService CagesCRUDInterface
+
+func init() {
+	CagesCRUDService = &CagesCRUD{}
+}
+
+// CagesCRUD is a struct that makes it easy to access the db for that particular repository
+// by calling r.db
+type CagesCRUD struct {
+	db *gorm.DB
+}
+
+// CagesCRUDInterface is an interface for CagesCRUD struct to allow for mocking of functions
+// during testing
+type CagesCRUDInterface interface {
+	// InitDB
+	NewCagesCRUD(*gorm.DB) *CagesCRUD
+
+	// Create
+	Save(models.Cage) (models.Cage, error)
+	SaveAll(uint32, []models.Cage) ([]models.Cage, error)
+
+	// Read
+	FindByID(uint32) (models.Cage, error)
+	FindAllByPuzzleID(uint32) ([]models.Cage, error)
+
+	// Update
+	Update(models.Cage) (int64, error)
+
+	// Delete
+	Delete(uint32) (int64, error)
+}
+
+// NewCagesCRUD takes in db as an argument and returns a CagesCRUD struct that
+// has r.db as a property; making it easy to access the db
+func (cagesCRUD *CagesCRUD) NewCagesCRUD(db *gorm.DB) *CagesCRUD {
+	cagesCRUD.db = db
+	return cagesCRUD
+}
+
+// ========== CREATE ========== //
+
+// Save takes a Cage model and saves it to the db
+// Returns the saved model and error if successful, returns empty Cage instance and error if unsuccessful
+func (cagesCRUD *CagesCRUD) Save(cage models.Cage) (models.Cage, error) {
+	var err error
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = cagesCRUD.db.Debug().Model(&models.Cage{}).Create(&cage).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return cage, nil
+	}
+
+	return models.Cage{}, err
+}
+
+// SaveAll takes Cage models and saves them to the db as cages of puzzleID in a single transaction, so that a copy of
+// a killer puzzle gets all of its cages or none
+// Returns the saved models and error if successful, returns nil and error if unsuccessful
+func (cagesCRUD *CagesCRUD) SaveAll(puzzleID uint32, cages []models.Cage) ([]models.Cage, error) {
+	var err error
+	saved := []models.Cage{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		tx := cagesCRUD.db.Debug().Begin()
+
+		if err = tx.Error; err != nil {
+			ch <- false
+			return
+		}
+
+		for _, cage := range cages {
+			cage.ID = 0
+			cage.PuzzleID = puzzleID
+			cage.PrepareCage()
+
+			if err = tx.Model(&models.Cage{}).Create(&cage).Error; err != nil {
+				tx.Rollback()
+				ch <- false
+				return
+			}
+
+			saved = append(saved, cage)
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return saved, nil
+	}
+
+	return nil, err
+}
+
+// ========== READ ========== //
+
+// FindByID takes a cageID and fetches the model instance from the db
+// Returns the model and error if successful, returns empty Cage instance and error if unsuccessful
+func (cagesCRUD *CagesCRUD) FindByID(cageID uint32) (models.Cage, error) {
+	var err error
+	cage := models.Cage{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = cagesCRUD.db.Debug().Model(&models.Cage{}).Where("id=?", cageID).Take(&cage).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return cage, nil
+	}
+
+	if gorm.IsRecordNotFoundError(err) {
+		return cage, errors.New("Cage not found")
+	}
+
+	return cage, err
+}
+
+// FindAllByPuzzleID fetches all the cages of a puzzle, in the order they were created
+// Returns an array of models and error if successful, returns empty array and error if unsuccessful
+func (cagesCRUD *CagesCRUD) FindAllByPuzzleID(puzzleID uint32) ([]models.Cage, error) {
+	var err error
+	cages := []models.Cage{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = cagesCRUD.db.Debug().Model(&models.Cage{}).Where("puzzle_id=?", puzzleID).Order("id").Find(&cages).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return cages, nil
+	}
+
+	return nil, err
+}
+
+// ========== UPDATE ========== //
+
+// Update takes in a model instance with updated cells and sum, and updates the existing entry in the db that
+// matches its ID
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (cagesCRUD *CagesCRUD) Update(cage models.Cage) (int64, error) {
+	var rs *gorm.DB
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		rs = cagesCRUD.db.Debug().Model(&models.Cage{}).Where("id=?", cage.ID).UpdateColumns(
+			map[string]interface{}{
+				"cells":      cage.Cells,
+				"sum":        cage.Sum,
+				"updated_at": time.Now(),
+			},
+		)
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) || rs.Error != nil {
+		return 0, rs.Error
+	}
+
+	return rs.RowsAffected, nil
+}
+
+// ========== DELETE ========== //
+
+// Delete takes in an ID and deletes the existing entry in the db that matches ID
+// Returns number of rows deleted and error if successful, returns 0 and error if unsuccessful
+func (cagesCRUD *CagesCRUD) Delete(cageID uint32) (int64, error) {
+	var rs *gorm.DB
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		rs = cagesCRUD.db.Debug().Model(&models.Cage{}).Where("id=?", cageID).Delete(&models.Cage{})
+		ch <- true
+	}(done)
+
+	if channels.OK(done) && rs.Error == nil {
+		return rs.RowsAffected, nil
+	}
+
+	return 0, rs.Error
+}
@@ -0,0 +1,52 @@
+package crud
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== SAVEALL() ========== //
+func TestCagesSaveAllIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(126)
+	cages := []models.Cage{
+		{ID: 1, Cells: "1112", Sum: 3, PuzzleID: 125},
+		{ID: 2, Cells: "2131", Sum: 9, PuzzleID: 125},
+	}
+
+	// Copies are inserted as new cages of puzzleID
+	s.Mock.ExpectBegin()
+
+	for i, cage := range cages {
+		s.Mock.ExpectExec("INSERT INTO").
+			WithArgs(cage.Cells, cage.Sum, sqlmock.AnyArg(), sqlmock.AnyArg(), puzzleID).
+			WillReturnResult(sqlmock.NewResult(int64(i+10), 1))
+	}
+
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := CagesCRUDService.NewCagesCRUD(s.DB)
+	saved, err := repo.SaveAll(puzzleID, cages)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if len(saved) != 2 || saved[0].ID != 10 || saved[1].PuzzleID != puzzleID {
+		t.Errorf("Actual cages: %+v, expected 2 new cages of puzzle %d", saved, puzzleID)
+	}
+
+	if cages[0].ID != 1 {
+		t.Errorf("Actual id of the original cage: %d, expected 1", cages[0].ID)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,57 @@
+package crud
+
+import (
+	"testing"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== FINDALLBYPUZZLEID() ========== //
+func TestCagesFindAllByPuzzleIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(125)
+
+	// SELECT * FROM `cages` WHERE (puzzle_id=?) ORDER BY id
+	rows := s.Mock.NewRows([]string{"id", "cells", "sum", "puzzle_id"}).
+		AddRow(1, "1112", 3, puzzleID).
+		AddRow(2, "2131", 9, puzzleID)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := CagesCRUDService.NewCagesCRUD(s.DB)
+	cages, err := repo.FindAllByPuzzleID(puzzleID)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if len(cages) != 2 || cages[1].Cells != "2131" || cages[1].Sum != 9 {
+		t.Errorf("Actual cages: %+v, expected 2 cages with the second holding 2131 and adding up to 9", cages)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== FINDBYID() ========== //
+func TestCagesFindByIDIfCageDoesNotExist(t *testing.T) {
+	s := tests.CreateSuite()
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(uint32(3)).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Execute function to be tested
+	repo := CagesCRUDService.NewCagesCRUD(s.DB)
+	_, err := repo.FindByID(3)
+
+	if err == nil || err.Error() != "Cage not found" {
+		t.Errorf("Error: %v, expected Cage not found", err)
+	}
+}
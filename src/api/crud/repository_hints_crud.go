@@ -0,0 +1,64 @@
+package crud
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
+)
+
+// HintsCRUDService is a global variable that exposes the methods of the module
+var HintsCRUDService HintsCRUDInterface
+
+func init() {
+	HintsCRUDService = &HintsCRUD{}
+}
+
+// HintsCRUD is a struct that makes it easy to access the db for that particular repository
+// by calling r.db
+type HintsCRUD struct {
+	db *gorm.DB
+}
+
+// HintsCRUDInterface is an interface for HintsCRUD struct to allow for mocking of functions
+// during testing
+type HintsCRUDInterface interface {
+	// InitDB
+	NewHintsCRUD(*gorm.DB) *HintsCRUD
+
+	// Create
+	Save(models.Hint) (models.Hint, error)
+}
+
+// NewHintsCRUD takes in db as an argument and returns a HintsCRUD struct that
+// has r.db as a property; making it easy to access the db
+func (hintsCRUD *HintsCRUD) NewHintsCRUD(db *gorm.DB) *HintsCRUD {
+	hintsCRUD.db = db
+	return hintsCRUD
+}
+
+// ========== CREATE ========== //
+
+// Save takes a Hint model and saves it to the db
+// Returns the saved model and error if successful, returns empty Hint instance and error if unsuccessful
+func (hintsCRUD *HintsCRUD) Save(hint models.Hint) (models.Hint, error) {
+	var err error
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = hintsCRUD.db.Debug().Model(&models.Hint{}).Create(&hint).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return hint, nil
+	}
+
+	return models.Hint{}, err
+}
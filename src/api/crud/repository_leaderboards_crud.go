@@ -0,0 +1,163 @@
+package crud
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
+)
+
+// LeaderboardsCRUDService is a global variable that exposes the methods of the module
+var LeaderboardsCRUDService LeaderboardsCRUDInterface
+
+func init() {
+	LeaderboardsCRUDService = &LeaderboardsCRUD{}
+}
+
+// LeaderboardsCRUD is a struct that makes it easy to access the db for that particular repository
+// by calling r.db
+// Leaderboards are aggregated from the completed sessions of each user, so the repository is read only
+type LeaderboardsCRUD struct {
+	db *gorm.DB
+}
+
+// LeaderboardsCRUDInterface is an interface for LeaderboardsCRUD struct to allow for mocking of functions
+// during testing
+type LeaderboardsCRUDInterface interface {
+	// InitDB
+	NewLeaderboardsCRUD(*gorm.DB) *LeaderboardsCRUD
+
+	// Read
+	FindByPuzzleID(uint32, time.Time, int) ([]models.LeaderboardEntry, error)
+	FindByDaily(string, int) ([]models.LeaderboardEntry, error)
+	FindAll(time.Time, string, int) ([]models.LeaderboardEntry, error)
+}
+
+// NewLeaderboardsCRUD takes in db as an argument and returns a LeaderboardsCRUD struct that
+// has r.db as a property; making it easy to access the db
+func (leaderboardsCRUD *LeaderboardsCRUD) NewLeaderboardsCRUD(db *gorm.DB) *LeaderboardsCRUD {
+	leaderboardsCRUD.db = db
+	return leaderboardsCRUD
+}
+
+// completedSessions selects the sessions completed since since, aggregated by user and joined with their usernames
+// A zero since selects all completed sessions
+func (leaderboardsCRUD *LeaderboardsCRUD) completedSessions(since time.Time) *gorm.DB {
+	query := leaderboardsCRUD.db.Debug().Table("sessions").
+		Select("sessions.user_id, users.username, COUNT(*) AS solved, MIN(sessions.elapsed_millis) AS best_millis, AVG(sessions.elapsed_millis) AS average_millis").
+		Joins("JOIN users ON users.id = sessions.user_id").
+		Where("sessions.completed_at IS NOT NULL")
+
+	if !since.IsZero() {
+		query = query.Where("sessions.completed_at >= ?", since)
+	}
+
+	return query
+}
+
+// ========== READ ========== //
+
+// FindByPuzzleID takes a puzzleID and fetches the users who solved the puzzle since since, fastest first
+// Each user solves their own copy, so the puzzle is ranked with the puzzles that share its origin, being the puzzle
+// that was first shared or the puzzle itself, and with the copies of the same daily puzzle
+// Returns at most limit entries, without ranks
+func (leaderboardsCRUD *LeaderboardsCRUD) FindByPuzzleID(puzzleID uint32, since time.Time, limit int) ([]models.LeaderboardEntry, error) {
+	var err error
+	entries := []models.LeaderboardEntry{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = leaderboardsCRUD.completedSessions(since).
+			Joins("JOIN puzzles ON puzzles.id = sessions.puzzle_id").
+			Joins("JOIN puzzles AS ranked ON ranked.id = ?", puzzleID).
+			Where("COALESCE(NULLIF(puzzles.origin_id, 0), puzzles.id) = COALESCE(NULLIF(ranked.origin_id, 0), ranked.id) OR (ranked.daily <> '' AND puzzles.daily = ranked.daily)").
+			Group("sessions.user_id, users.username").
+			Order("best_millis, sessions.user_id").
+			Limit(limit).
+			Scan(&entries).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return entries, nil
+	}
+
+	return nil, err
+}
+
+// FindByDaily takes the date of a daily puzzle and fetches the users who solved their copy of it, fastest first
+// Returns at most limit entries, without ranks
+func (leaderboardsCRUD *LeaderboardsCRUD) FindByDaily(date string, limit int) ([]models.LeaderboardEntry, error) {
+	var err error
+	entries := []models.LeaderboardEntry{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = leaderboardsCRUD.completedSessions(time.Time{}).
+			Joins("JOIN puzzles ON puzzles.id = sessions.puzzle_id").
+			Where("puzzles.daily = ?", date).
+			Group("sessions.user_id, users.username").
+			Order("best_millis, sessions.user_id").
+			Limit(limit).
+			Scan(&entries).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return entries, nil
+	}
+
+	return nil, err
+}
+
+// FindAll takes a difficulty and fetches the users who solved the most puzzles of the difficulty since since,
+// with ties broken by the fastest average time
+// An empty difficulty includes puzzles of all difficulties. Returns at most limit entries, without ranks
+func (leaderboardsCRUD *LeaderboardsCRUD) FindAll(since time.Time, difficulty string, limit int) ([]models.LeaderboardEntry, error) {
+	var err error
+	entries := []models.LeaderboardEntry{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		query := leaderboardsCRUD.completedSessions(since)
+
+		if difficulty != "" {
+			query = query.Joins("JOIN puzzles ON puzzles.id = sessions.puzzle_id").Where("puzzles.difficulty = ?", difficulty)
+		}
+
+		err = query.
+			Group("sessions.user_id, users.username").
+			Order("solved desc, average_millis, sessions.user_id").
+			Limit(limit).
+			Scan(&entries).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return entries, nil
+	}
+
+	return nil, err
+}
@@ -0,0 +1,78 @@
+package crud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== FINDBYPUZZLEID() ========== //
+func TestLeaderboardsFindByPuzzleIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(125)
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// SELECT sessions.user_id, users.username, COUNT(*) AS solved, ... FROM `sessions` JOIN users ON users.id = sessions.user_id
+	// JOIN puzzles ON puzzles.id = sessions.puzzle_id JOIN puzzles AS ranked ON ranked.id = ?
+	// WHERE (sessions.completed_at IS NOT NULL) AND (sessions.completed_at >= ?) AND (COALESCE(NULLIF(puzzles.origin_id, 0), ...))
+	// GROUP BY sessions.user_id, users.username ORDER BY best_millis, sessions.user_id LIMIT 10
+	rows := s.Mock.NewRows([]string{"user_id", "username", "solved", "best_millis", "average_millis"}).
+		AddRow(100, "johndoe", 2, 61000, 75500.5).
+		AddRow(101, "janedoe", 1, 90000, 90000)
+
+	s.Mock.ExpectQuery("SELECT sessions.user_id, users.username, COUNT\\(\\*\\) AS solved").
+		WithArgs(puzzleID, since).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := LeaderboardsCRUDService.NewLeaderboardsCRUD(s.DB)
+	entries, err := repo.FindByPuzzleID(puzzleID, since, 10)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Actual number of entries: %d, expected 2", len(entries))
+	}
+
+	if entries[0].Username != "johndoe" || entries[0].BestMillis != 61000 || entries[0].AverageMillis != 75500.5 {
+		t.Errorf("Actual entry: %+v, expected johndoe with best 61000 and average 75500.5", entries[0])
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== FINDALL() ========== //
+func TestLeaderboardsFindAllIfDifficulty(t *testing.T) {
+	s := tests.CreateSuite()
+
+	rows := s.Mock.NewRows([]string{"user_id", "username", "solved", "best_millis", "average_millis"}).
+		AddRow(101, "janedoe", 5, 40000, 52000)
+
+	s.Mock.ExpectQuery("JOIN puzzles ON puzzles.id = sessions.puzzle_id").
+		WithArgs("hard").
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := LeaderboardsCRUDService.NewLeaderboardsCRUD(s.DB)
+	entries, err := repo.FindAll(time.Time{}, "hard", 10)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if len(entries) != 1 || entries[0].Solved != 5 {
+		t.Errorf("Actual entries: %+v, expected janedoe with 5 solved", entries)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,65 @@
+package crud
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
+)
+
+// MovesCRUDService is a global variable that exposes the methods of the module
+var MovesCRUDService MovesCRUDInterface
+
+func init() {
+	MovesCRUDService = &MovesCRUD{}
+}
+
+// MovesCRUD is a struct that makes it easy to access the db for that particular repository
+// by calling r.db
+// Moves are appended by BoardsCRUD as boards are updated, so the repository is read only
+type MovesCRUD struct {
+	db *gorm.DB
+}
+
+// MovesCRUDInterface is an interface for MovesCRUD struct to allow for mocking of functions
+// during testing
+type MovesCRUDInterface interface {
+	// InitDB
+	NewMovesCRUD(*gorm.DB) *MovesCRUD
+
+	// Read
+	FindAllByPuzzleID(uint32) ([]models.Move, error)
+}
+
+// NewMovesCRUD takes in db as an argument and returns a MovesCRUD struct that
+// has r.db as a property; making it easy to access the db
+func (movesCRUD *MovesCRUD) NewMovesCRUD(db *gorm.DB) *MovesCRUD {
+	movesCRUD.db = db
+	return movesCRUD
+}
+
+// ========== READ ========== //
+
+// FindAllByPuzzleID takes a puzzleID and fetches the move log of the puzzle in the order the moves were made
+func (movesCRUD *MovesCRUD) FindAllByPuzzleID(puzzleID uint32) ([]models.Move, error) {
+	var err error
+	moves := []models.Move{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = movesCRUD.db.Debug().Model(&models.Move{}).Where("puzzle_id=?", puzzleID).Order("id").Find(&moves).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return moves, nil
+	}
+
+	return nil, err
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
 )
 
+// ErrPuzzleNotFound is returned when no puzzle matches the id, or the id and owner, that were looked up
+var ErrPuzzleNotFound = errors.New("Puzzle not found")
+
 // PuzzlesCRUDService is a global variable that exposes the methods of the module
 var PuzzlesCRUDService PuzzlesCRUDInterface
 
@@ -30,13 +33,20 @@ type PuzzlesCRUDInterface interface {
 
 	// Create
 	Save(models.Puzzle) (models.Puzzle, error)
+	SaveAll([]models.Puzzle) ([]models.Puzzle, error)
 
 	// Read
 	FindByID(uint32, uint32) (models.Puzzle, error)
 	FindAll(uint32) ([]models.Puzzle, error)
+	FindAllByDifficulty(uint32, string) ([]models.Puzzle, error)
+	FindByDaily(uint32, string) (models.Puzzle, error)
+	FindByShareCode(string) (models.Puzzle, error)
+	FindVariantsByID(uint32) (models.Puzzle, error)
 
 	// Update
 	Update(uint32, models.Puzzle) (int64, error)
+	UpdateShareCode(uint32, uint32, *string) (int64, error)
+	UpdateGrade(uint32, uint32, string, int) (int64, error)
 
 	// Delete
 	Delete(uint32, uint32) (int64, error)
@@ -53,7 +63,7 @@ func (puzzlesCRUD *PuzzlesCRUD) NewPuzzlesCRUD(db *gorm.DB) *PuzzlesCRUD {
 
 // Save takes a Puzzle model and saves it to the db
 // Returns the saved model and error if successful, returns empty Puzzle instance and error if unsuccessful
-// Also creates Board
+// Also creates a Board for every cell of the grid, filled with puzzle.Givens if present
 func (puzzlesCRUD *PuzzlesCRUD) Save(puzzle models.Puzzle) (models.Puzzle, error) {
 
 	var err error
@@ -66,14 +76,26 @@ func (puzzlesCRUD *PuzzlesCRUD) Save(puzzle models.Puzzle) (models.Puzzle, error
 			ch <- false
 		}
 
-		// Create board for every new puzzle
-		for i := 1; i <= 9; i++ {
-			for j := 1; j <= 9; j++ {
+		// Create board for every new puzzle, puzzles saved without a size are classic puzzles
+		size := puzzle.Size
+
+		if size == 0 {
+			size = models.DefaultGridSize
+		}
+
+		for i := 1; i <= size; i++ {
+			for j := 1; j <= size; j++ {
 
 				board := models.Board{}
 				board.BoardRow = i
 				board.BoardCol = j
 				board.PuzzleID = puzzle.ID
+
+				if puzzle.Givens != nil {
+					board.Value = puzzle.Givens[i-1][j-1]
+					board.Given = board.Value != 0
+				}
+
 				err = puzzlesCRUD.db.Debug().Model(&models.Board{}).Create(&board).Error
 
 				if err != nil {
@@ -95,6 +117,80 @@ func (puzzlesCRUD *PuzzlesCRUD) Save(puzzle models.Puzzle) (models.Puzzle, error
 
 }
 
+// SaveAll takes Puzzle models and saves them to the db with their boards in a single transaction, so that an import
+// saves all of its puzzles or none
+// Returns the saved models and error if successful, returns nil and error if unsuccessful
+func (puzzlesCRUD *PuzzlesCRUD) SaveAll(puzzles []models.Puzzle) ([]models.Puzzle, error) {
+	var err error
+	saved := []models.Puzzle{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		tx := puzzlesCRUD.db.Debug().Begin()
+
+		if err = tx.Error; err != nil {
+			ch <- false
+			return
+		}
+
+		for _, puzzle := range puzzles {
+			if err = createPuzzle(tx, &puzzle); err != nil {
+				tx.Rollback()
+				ch <- false
+				return
+			}
+
+			saved = append(saved, puzzle)
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return saved, nil
+	}
+
+	return nil, err
+}
+
+// createPuzzle inserts puzzle and a Board for every cell of its grid, filled with puzzle.Givens if present
+func createPuzzle(db *gorm.DB, puzzle *models.Puzzle) error {
+	if err := db.Model(&models.Puzzle{}).Create(puzzle).Error; err != nil {
+		return err
+	}
+
+	// Puzzles saved without a size are classic puzzles
+	size := puzzle.Size
+
+	if size == 0 {
+		size = models.DefaultGridSize
+	}
+
+	for i := 1; i <= size; i++ {
+		for j := 1; j <= size; j++ {
+			board := models.Board{BoardRow: i, BoardCol: j, PuzzleID: puzzle.ID}
+
+			if puzzle.Givens != nil {
+				board.Value = puzzle.Givens[i-1][j-1]
+				board.Given = board.Value != 0
+			}
+
+			if err := db.Model(&models.Board{}).Create(&board).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // ========== READ ========== //
 
 // FindByID takes a userID and fetches the model instance from the db
@@ -123,7 +219,7 @@ func (puzzlesCRUD *PuzzlesCRUD) FindByID(puzzleID uint32, userID uint32) (models
 
 	// Puzzle not found
 	if gorm.IsRecordNotFoundError(err) {
-		return puzzle, errors.New("Puzzle not found")
+		return puzzle, ErrPuzzleNotFound
 	}
 
 	// Other errors
@@ -159,6 +255,121 @@ func (puzzlesCRUD *PuzzlesCRUD) FindAll(userID uint32) ([]models.Puzzle, error)
 	return nil, err
 }
 
+// FindAllByDifficulty fetches all the entries from the Puzzle model in the db with the given difficulty
+// Returns an array of models and error if successful, returns empty array and error if unsuccessful
+func (puzzlesCRUD *PuzzlesCRUD) FindAllByDifficulty(userID uint32, difficulty string) ([]models.Puzzle, error) {
+	var err error
+	puzzles := []models.Puzzle{}
+	done := make(chan bool)
+
+	// If not found or any kind of error, will return false
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = puzzlesCRUD.db.Debug().Model(&models.Puzzle{}).Limit(100).Where("user_id=? AND difficulty=?", userID, difficulty).Find(&puzzles).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	// Puzzle found
+	if channels.OK(done) {
+		return puzzles, nil
+	}
+
+	// Other errors
+	return nil, err
+}
+
+// FindByDaily takes a userID and the date of a daily puzzle, and fetches the copy of the daily puzzle of the user
+// Returns gorm.ErrRecordNotFound if the user has not started the daily puzzle
+func (puzzlesCRUD *PuzzlesCRUD) FindByDaily(userID uint32, date string) (models.Puzzle, error) {
+	var err error
+	puzzle := models.Puzzle{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = puzzlesCRUD.db.Debug().Model(&models.Puzzle{}).Where("user_id=? AND daily=?", userID, date).Take(&puzzle).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return puzzle, nil
+	}
+
+	return puzzle, err
+}
+
+// FindByShareCode takes a share code and fetches the puzzle shared with it, regardless of its owner
+// Returns the model and error if successful, returns empty Puzzle instance and error if unsuccessful
+func (puzzlesCRUD *PuzzlesCRUD) FindByShareCode(code string) (models.Puzzle, error) {
+	var err error
+	puzzle := models.Puzzle{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = puzzlesCRUD.db.Debug().Model(&models.Puzzle{}).Where("share_code=?", code).Take(&puzzle).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return puzzle, nil
+	}
+
+	if gorm.IsRecordNotFoundError(err) {
+		return puzzle, ErrPuzzleNotFound
+	}
+
+	return puzzle, err
+}
+
+// FindVariantsByID takes a puzzleID and fetches the size and variant rules of the puzzle, regardless of its owner
+// Returns the model and error if successful, returns empty Puzzle instance and error if unsuccessful
+func (puzzlesCRUD *PuzzlesCRUD) FindVariantsByID(puzzleID uint32) (models.Puzzle, error) {
+	var err error
+	puzzle := models.Puzzle{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = puzzlesCRUD.db.Debug().Model(&models.Puzzle{}).Select("id, size, box_rows, box_cols, variants, regions, parity").Where("id=?", puzzleID).Take(&puzzle).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return puzzle, nil
+	}
+
+	if gorm.IsRecordNotFoundError(err) {
+		return puzzle, ErrPuzzleNotFound
+	}
+
+	return puzzle, err
+}
+
 // ========== UPDATE ========== //
 
 // Update takes in a model instance with updated fields and ID, and updates the existing entry in the db that matches ID
@@ -188,6 +399,61 @@ func (puzzlesCRUD *PuzzlesCRUD) Update(userID uint32, puzzle models.Puzzle) (int
 
 }
 
+// UpdateShareCode takes a puzzleID, userID and share code, and sets the share code of the puzzle of the user
+// A nil code revokes the share code, so that the puzzle can no longer be cloned
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (puzzlesCRUD *PuzzlesCRUD) UpdateShareCode(puzzleID uint32, userID uint32, code *string) (int64, error) {
+	var rs *gorm.DB
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		rs = puzzlesCRUD.db.Debug().Model(&models.Puzzle{}).Where("id=? AND user_id=?", puzzleID, userID).UpdateColumns(
+			map[string]interface{}{
+				"share_code": code,
+				"updated_at": time.Now(),
+			},
+		)
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) || rs.Error != nil {
+		return 0, rs.Error
+	}
+
+	return rs.RowsAffected, nil
+}
+
+// UpdateGrade takes a puzzleID, userID, difficulty and score, and sets the grade of the puzzle of the user, such as once
+// the givens of a puzzle have been set up
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (puzzlesCRUD *PuzzlesCRUD) UpdateGrade(puzzleID uint32, userID uint32, difficulty string, score int) (int64, error) {
+	var rs *gorm.DB
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		rs = puzzlesCRUD.db.Debug().Model(&models.Puzzle{}).Where("id=? AND user_id=?", puzzleID, userID).UpdateColumns(
+			map[string]interface{}{
+				"difficulty": difficulty,
+				"score":      score,
+				"updated_at": time.Now(),
+			},
+		)
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) || rs.Error != nil {
+		return 0, rs.Error
+	}
+
+	return rs.RowsAffected, nil
+}
+
 // ========== DELETE ========== //
 
 // Delete takes in an ID and deletes the existing entry in the db that matches ID
@@ -85,3 +85,72 @@ func TestSavePuzzleIfSuccessfullySave(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+func TestSaveAllPuzzlesIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	uid := uint32(100)
+	givens := [][]int{{1, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 2}}
+	puzzles := []models.Puzzle{
+		{Name: "first", Size: 4, BoxRows: 2, BoxCols: 2, Givens: givens, UserID: uid},
+		{Name: "second", Size: 4, BoxRows: 2, BoxCols: 2, Givens: givens, UserID: uid},
+	}
+
+	// Both puzzles and their 16 boards are inserted in a single transaction
+	s.Mock.ExpectBegin()
+
+	for i := range puzzles {
+		s.Mock.ExpectExec("INSERT INTO").
+			WithArgs(puzzles[i].Name, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 4, 2, 2, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), uid).
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+
+		for j := 0; j < 16; j++ {
+			s.Mock.ExpectExec("INSERT INTO").
+				WillReturnResult(sqlmock.NewResult(int64(16*i+j+1), 1))
+		}
+	}
+
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := PuzzlesCRUDService.NewPuzzlesCRUD(s.DB)
+	saved, err := repo.SaveAll(puzzles)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if len(saved) != 2 || saved[0].ID != 1 || saved[1].ID != 2 {
+		t.Errorf("Actual puzzles: %+v, expected puzzles 1 and 2", saved)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestSaveAllPuzzlesIfInsertFails(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzles := []models.Puzzle{{Name: "first", Size: 4, BoxRows: 2, BoxCols: 2, UserID: 100}}
+
+	// Nothing is saved when a puzzle cannot be inserted
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("INSERT INTO").
+		WillReturnError(fmt.Errorf("Duplicate entry 'first'"))
+	s.Mock.ExpectRollback()
+
+	// Execute function to be tested
+	repo := PuzzlesCRUDService.NewPuzzlesCRUD(s.DB)
+	saved, err := repo.SaveAll(puzzles)
+
+	if err == nil || saved != nil {
+		t.Errorf("Actual puzzles: %+v and error: %v, expected nil and an error", saved, err)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -163,3 +163,79 @@ func TestFindAllIfSuccessful(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+// ========== FindAllByDifficulty() ========== //
+func TestFindAllByDifficultyIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	uid := uint32(1001)
+	difficulty := "hard"
+
+	// Populate DB and define expected response
+	data := []models.Puzzle{
+		models.Puzzle{
+			Name:       "testPuzzleOne",
+			Difficulty: difficulty,
+			Score:      80,
+		},
+	}
+
+	expected, err := json.Marshal(data)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	rows := s.Mock.NewRows([]string{"name", "difficulty", "score"}).
+		AddRow(data[0].Name, data[0].Difficulty, data[0].Score)
+
+	s.Mock.ExpectQuery("SELECT *").WithArgs(uid, difficulty).WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := PuzzlesCRUDService.NewPuzzlesCRUD(s.DB)
+	puzzles, err := repo.FindAllByDifficulty(uid, difficulty)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := json.Marshal(puzzles)
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshaling expected json data", err)
+	}
+
+	if !utils.JSONEqual(bytes.NewBuffer(expected), bytes.NewBuffer(actual)) {
+		t.Errorf("Actual: %s, expected: %s", actual, expected)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+// ========== FindVariantsByID() ========== //
+func TestFindVariantsByIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+	puzzleID := uint32(10)
+
+	rows := s.Mock.NewRows([]string{"id", "size", "variants"}).
+		AddRow(puzzleID, 9, "x,anti_knight")
+
+	s.Mock.ExpectQuery("SELECT id, size, box_rows, box_cols, variants, regions, parity").WithArgs(puzzleID).WillReturnRows(rows)
+
+	repo := PuzzlesCRUDService.NewPuzzlesCRUD(s.DB)
+	puzzle, err := repo.FindVariantsByID(puzzleID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.ID != puzzleID || puzzle.Variants != "x,anti_knight" {
+		t.Errorf("Actual puzzle: %+v, expected id %d with variants x,anti_knight", puzzle, puzzleID)
+	}
+
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -62,3 +62,33 @@ func TestUpdateIfSuccessfullyUpdatedName(t *testing.T) {
 		fmt.Printf("unmet expectation error: %s", err)
 	}
 }
+
+func TestUpdateGradeIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	uid := uint32(100)
+	puzzleID := uint32(125)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs("hard", 42, sqlmock.AnyArg(), puzzleID, uid).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := PuzzlesCRUDService.NewPuzzlesCRUD(s.DB)
+	rowsUpdated, err := repo.UpdateGrade(puzzleID, uid, "hard", 42)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	if rowsUpdated != 1 {
+		t.Errorf("Actual rowsUpdated: %v, expected 1", rowsUpdated)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
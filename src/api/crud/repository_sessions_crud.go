@@ -0,0 +1,193 @@
+package crud
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
+)
+
+// SessionsCRUDService is a global variable that exposes the methods of the module
+var SessionsCRUDService SessionsCRUDInterface
+
+func init() {
+	SessionsCRUDService = &SessionsCRUD{}
+}
+
+// SessionsCRUD is a struct that makes it easy to access the db for that particular repository
+// by calling r.db
+type SessionsCRUD struct {
+	db *gorm.DB
+}
+
+// SessionsCRUDInterface is an interface for SessionsCRUD struct to allow for mocking of functions
+// during testing
+type SessionsCRUDInterface interface {
+	// InitDB
+	NewSessionsCRUD(*gorm.DB) *SessionsCRUD
+
+	// Create
+	Save(models.Session) (models.Session, error)
+
+	// Read
+	FindByID(uint32, uint32) (models.Session, error)
+	FindActiveByPuzzleID(uint32) (models.Session, error)
+	FindAllByPuzzleID(uint32, uint32) ([]models.Session, error)
+
+	// Update
+	Update(models.Session) (int64, error)
+}
+
+// NewSessionsCRUD takes in db as an argument and returns a SessionsCRUD struct that
+// has r.db as a property; making it easy to access the db
+func (sessionsCRUD *SessionsCRUD) NewSessionsCRUD(db *gorm.DB) *SessionsCRUD {
+	sessionsCRUD.db = db
+	return sessionsCRUD
+}
+
+// ========== CREATE ========== //
+
+// Save takes a Session model and saves it to the db
+// Returns the saved model and error if successful, returns empty Session instance and error if unsuccessful
+func (sessionsCRUD *SessionsCRUD) Save(session models.Session) (models.Session, error) {
+	var err error
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = sessionsCRUD.db.Debug().Model(&models.Session{}).Create(&session).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return session, nil
+	}
+
+	return models.Session{}, err
+}
+
+// ========== READ ========== //
+
+// FindByID takes a sessionID and userID and fetches the session of the user from the db
+// Returns the model and error if successful, returns empty Session instance and error if unsuccessful
+func (sessionsCRUD *SessionsCRUD) FindByID(sessionID uint32, userID uint32) (models.Session, error) {
+	var err error
+	session := models.Session{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = sessionsCRUD.db.Debug().Model(&models.Session{}).Where("id=? AND user_id=?", sessionID, userID).Take(&session).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return session, nil
+	}
+
+	if gorm.IsRecordNotFoundError(err) {
+		return session, errors.New("Session not found")
+	}
+
+	return session, err
+}
+
+// FindActiveByPuzzleID takes a puzzleID and fetches the latest session of the puzzle that is not completed
+// Returns gorm.ErrRecordNotFound if the puzzle has no such session
+func (sessionsCRUD *SessionsCRUD) FindActiveByPuzzleID(puzzleID uint32) (models.Session, error) {
+	var err error
+	session := models.Session{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = sessionsCRUD.db.Debug().Model(&models.Session{}).Where("puzzle_id=? AND completed_at IS NULL", puzzleID).Order("id desc").Take(&session).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return session, nil
+	}
+
+	return session, err
+}
+
+// FindAllByPuzzleID takes a puzzleID and userID and fetches all sessions of the user for the puzzle,
+// starting from the latest
+func (sessionsCRUD *SessionsCRUD) FindAllByPuzzleID(puzzleID uint32, userID uint32) ([]models.Session, error) {
+	var err error
+	sessions := []models.Session{}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+		err = sessionsCRUD.db.Debug().Model(&models.Session{}).Where("puzzle_id=? AND user_id=?", puzzleID, userID).Order("id desc").Find(&sessions).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return sessions, nil
+	}
+
+	return nil, err
+}
+
+// ========== UPDATE ========== //
+
+// Update takes in a session with updated timer, move and completion fields, and updates the existing entry in the db
+// that matches session.ID
+// Returns number of rows updated and error if successful, returns 0 and error if unsuccessful
+func (sessionsCRUD *SessionsCRUD) Update(session models.Session) (int64, error) {
+	var rs *gorm.DB
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		rs = sessionsCRUD.db.Debug().Model(&models.Session{}).Where("id=?", session.ID).UpdateColumns(
+			map[string]interface{}{
+				"paused_at":      session.PausedAt,
+				"paused_millis":  session.PausedMillis,
+				"elapsed_millis": session.ElapsedMillis,
+				"moves":          session.Moves,
+				"mistakes":       session.Mistakes,
+				"completed_at":   session.CompletedAt,
+				"updated_at":     time.Now(),
+			},
+		)
+
+		ch <- true
+	}(done)
+
+	if !channels.OK(done) || rs.Error != nil {
+		return 0, rs.Error
+	}
+
+	return rs.RowsAffected, nil
+}
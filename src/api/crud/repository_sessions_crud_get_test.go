@@ -0,0 +1,60 @@
+package crud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== FINDACTIVEBYPUZZLEID() ========== //
+func TestSessionsFindActiveByPuzzleIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(125)
+	uid := uint32(100)
+
+	// SELECT * FROM `sessions` WHERE (puzzle_id=? AND completed_at IS NULL) ORDER BY id desc LIMIT 1
+	rows := s.Mock.NewRows([]string{"id", "started_at", "moves", "mistakes", "puzzle_id", "user_id"}).
+		AddRow(7, time.Now(), 12, 1, puzzleID, uid)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(rows)
+
+	// Execute function to be tested
+	repo := SessionsCRUDService.NewSessionsCRUD(s.DB)
+	session, err := repo.FindActiveByPuzzleID(puzzleID)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if session.ID != 7 || session.Moves != 12 || session.Mistakes != 1 {
+		t.Errorf("Actual session: %+v, expected id 7 with 12 moves and 1 mistake", session)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestSessionsFindActiveByPuzzleIDIfNone(t *testing.T) {
+	s := tests.CreateSuite()
+
+	puzzleID := uint32(125)
+
+	s.Mock.ExpectQuery("SELECT *").
+		WithArgs(puzzleID).
+		WillReturnRows(s.Mock.NewRows([]string{"id"}))
+
+	// Execute function to be tested
+	repo := SessionsCRUDService.NewSessionsCRUD(s.DB)
+	_, err := repo.FindActiveByPuzzleID(puzzleID)
+
+	if !gorm.IsRecordNotFoundError(err) {
+		t.Errorf("Error: %v, expected record not found", err)
+	}
+}
@@ -0,0 +1,48 @@
+package crud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+func TestSessionsUpdateIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	completedAt := time.Now()
+	session := models.Session{
+		ID:            7,
+		ElapsedMillis: 40000,
+		Moves:         52,
+		Mistakes:      2,
+		CompletedAt:   &completedAt,
+	}
+
+	// UPDATE `sessions` SET `completed_at` = ?, `elapsed_millis` = ?, `mistakes` = ?, `moves` = ?, `paused_at` = ?,
+	// `paused_millis` = ?, `updated_at` = ?  WHERE (id=?)
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectExec("UPDATE").
+		WithArgs(&completedAt, session.ElapsedMillis, session.Mistakes, session.Moves, sqlmock.AnyArg(), session.PausedMillis, sqlmock.AnyArg(), session.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectCommit()
+
+	// Execute function to be tested
+	repo := SessionsCRUDService.NewSessionsCRUD(s.DB)
+	rowsUpdated, err := repo.Update(session)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	if rowsUpdated != 1 {
+		t.Errorf("Actual rowsUpdated: %v, expected 1", rowsUpdated)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
@@ -0,0 +1,139 @@
+package crud
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/utils/channels"
+)
+
+// StatsCRUDService is a global variable that exposes the methods of the module
+var StatsCRUDService StatsCRUDInterface
+
+func init() {
+	StatsCRUDService = &StatsCRUD{}
+}
+
+// StatsCRUD is a struct that makes it easy to access the db for that particular repository
+// by calling r.db
+// Statistics are aggregated from the puzzles, sessions and hints of each user, so the repository is read only
+type StatsCRUD struct {
+	db *gorm.DB
+}
+
+// StatsCRUDInterface is an interface for StatsCRUD struct to allow for mocking of functions
+// during testing
+type StatsCRUDInterface interface {
+	// InitDB
+	NewStatsCRUD(*gorm.DB) *StatsCRUD
+
+	// Read
+	FindByUserID(uint32, time.Time) (models.UserStats, error)
+}
+
+// NewStatsCRUD takes in db as an argument and returns a StatsCRUD struct that
+// has r.db as a property; making it easy to access the db
+func (statsCRUD *StatsCRUD) NewStatsCRUD(db *gorm.DB) *StatsCRUD {
+	statsCRUD.db = db
+	return statsCRUD
+}
+
+// maxHintUsages is the number of most used techniques returned with the statistics of a user
+const maxHintUsages = 3
+
+// ========== READ ========== //
+
+// FindByUserID takes a userID and aggregates the statistics of the user as of now, without loading any boards
+// Difficulties are ordered by name and hints by the most used technique first
+// Returns the model and error if successful, returns empty UserStats instance and error if unsuccessful
+func (statsCRUD *StatsCRUD) FindByUserID(userID uint32, now time.Time) (models.UserStats, error) {
+	var err error
+	stats := models.UserStats{UserID: userID, Difficulties: []models.DifficultyStats{}, Hints: []models.HintUsage{}}
+	done := make(chan bool)
+
+	go func(ch chan<- bool) {
+		defer close(ch)
+
+		err = statsCRUD.db.Debug().Model(&models.Puzzle{}).Where("user_id = ?", userID).Count(&stats.Created).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		sessions := struct {
+			Solved    int
+			Abandoned int
+		}{}
+
+		err = statsCRUD.db.Debug().Table("sessions").
+			Select("COUNT(completed_at) AS solved, COUNT(CASE WHEN completed_at IS NULL AND updated_at < ? THEN 1 END) AS abandoned", now.Add(-models.AbandonAfter)).
+			Where("user_id = ?", userID).
+			Scan(&sessions).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		stats.Solved = sessions.Solved
+		stats.Abandoned = sessions.Abandoned
+
+		err = statsCRUD.db.Debug().Table("sessions").
+			Select("puzzles.difficulty, COUNT(*) AS solved, MIN(sessions.elapsed_millis) AS best_millis, AVG(sessions.elapsed_millis) AS average_millis").
+			Joins("JOIN puzzles ON puzzles.id = sessions.puzzle_id").
+			Where("sessions.user_id = ? AND sessions.completed_at IS NOT NULL", userID).
+			Group("puzzles.difficulty").
+			Order("puzzles.difficulty").
+			Scan(&stats.Difficulties).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		// The streaks count days in UTC, so the days of the solves are taken from their times rather than from DATE(),
+		// which would use the time zone of the DB
+		completed := []struct{ CompletedAt time.Time }{}
+
+		err = statsCRUD.db.Debug().Table("sessions").
+			Select("completed_at").
+			Where("user_id = ? AND completed_at IS NOT NULL", userID).
+			Scan(&completed).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		dates := make([]time.Time, len(completed))
+
+		for i, c := range completed {
+			dates[i] = c.CompletedAt
+		}
+
+		stats.CurrentStreak, stats.LongestStreak = models.Streaks(dates, now)
+
+		err = statsCRUD.db.Debug().Model(&models.Hint{}).
+			Select("technique, COUNT(*) AS count").
+			Where("user_id = ?", userID).
+			Group("technique").
+			Order("count desc, technique").
+			Limit(maxHintUsages).
+			Scan(&stats.Hints).Error
+
+		if err != nil {
+			ch <- false
+			return
+		}
+
+		ch <- true
+	}(done)
+
+	if channels.OK(done) {
+		return stats, nil
+	}
+
+	return models.UserStats{}, err
+}
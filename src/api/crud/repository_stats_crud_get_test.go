@@ -0,0 +1,88 @@
+package crud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quattad/sudokubuddy-backend/src/api/tests"
+)
+
+// ========== FINDBYUSERID() ========== //
+func TestStatsFindByUserIDIfSuccessful(t *testing.T) {
+	s := tests.CreateSuite()
+
+	userID := uint32(100)
+	now := time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectQuery("SELECT count\\(\\*\\) FROM `puzzles`").
+		WithArgs(userID).
+		WillReturnRows(s.Mock.NewRows([]string{"count(*)"}).AddRow(5))
+
+	s.Mock.ExpectQuery("SELECT COUNT\\(completed_at\\) AS solved").
+		WithArgs(now.Add(-7*24*time.Hour), userID).
+		WillReturnRows(s.Mock.NewRows([]string{"solved", "abandoned"}).AddRow(3, 1))
+
+	s.Mock.ExpectQuery("SELECT puzzles.difficulty, COUNT\\(\\*\\) AS solved").
+		WithArgs(userID).
+		WillReturnRows(s.Mock.NewRows([]string{"difficulty", "solved", "best_millis", "average_millis"}).
+			AddRow("easy", 2, 61000, 75500.5).
+			AddRow("hard", 1, 300000, 300000))
+
+	// The solve at 01:00 on the 10th in UTC+8 is on the 9th in UTC, like the solve before it
+	s.Mock.ExpectQuery("SELECT completed_at FROM `sessions`").
+		WithArgs(userID).
+		WillReturnRows(s.Mock.NewRows([]string{"completed_at"}).
+			AddRow(time.Date(2020, 1, 8, 12, 0, 0, 0, time.UTC)).
+			AddRow(time.Date(2020, 1, 9, 8, 0, 0, 0, time.UTC)).
+			AddRow(time.Date(2020, 1, 10, 1, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60))))
+
+	s.Mock.ExpectQuery("SELECT technique, COUNT\\(\\*\\) AS count").
+		WithArgs(userID).
+		WillReturnRows(s.Mock.NewRows([]string{"technique", "count"}).
+			AddRow("naked single", 4).
+			AddRow("x-wing", 1))
+
+	// Execute function to be tested
+	repo := StatsCRUDService.NewStatsCRUD(s.DB)
+	stats, err := repo.FindByUserID(userID, now)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if stats.Created != 5 || stats.Solved != 3 || stats.Abandoned != 1 {
+		t.Errorf("Actual counts: %+v, expected 5 created, 3 solved and 1 abandoned", stats)
+	}
+
+	if len(stats.Difficulties) != 2 || stats.Difficulties[0].BestMillis != 61000 || stats.Difficulties[0].AverageMillis != 75500.5 {
+		t.Errorf("Actual difficulties: %+v, expected easy with best 61000 and average 75500.5, and hard", stats.Difficulties)
+	}
+
+	if stats.CurrentStreak != 2 || stats.LongestStreak != 2 {
+		t.Errorf("Actual streaks: %d current, %d longest, expected: 2 current, 2 longest", stats.CurrentStreak, stats.LongestStreak)
+	}
+
+	if len(stats.Hints) != 2 || stats.Hints[0].Technique != "naked single" || stats.Hints[0].Count != 4 {
+		t.Errorf("Actual hints: %+v, expected naked single used 4 times first", stats.Hints)
+	}
+
+	// ensure all expectations have been met
+	if err = s.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectation error: %s", err)
+	}
+}
+
+func TestStatsFindByUserIDIfError(t *testing.T) {
+	s := tests.CreateSuite()
+
+	s.Mock.ExpectQuery("SELECT count\\(\\*\\) FROM `puzzles`").
+		WillReturnError(sqlmock.ErrCancelled)
+
+	// Execute function to be tested
+	repo := StatsCRUDService.NewStatsCRUD(s.DB)
+
+	if _, err := repo.FindByUserID(100, time.Now()); err == nil {
+		t.Errorf("Error: nil, expected error")
+	}
+}
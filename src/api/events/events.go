@@ -0,0 +1,159 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is the number of events queued for a subscriber before it is dropped for falling behind
+const subscriberBuffer = 32
+
+// heartbeat is how often Stream writes a comment to keep idle connections open through proxies
+var heartbeat = 15 * time.Second
+
+// ErrNotFlushable is returned by Stream before anything is written when the response cannot stream events. Any other
+// error of Stream happens once the stream has started with status code 200
+var ErrNotFlushable = errors.New("Response does not support streaming")
+
+// Event is a message published to the subscribers of a topic
+// Type is sent as the name of a Server-Sent Event and Data is sent as its JSON encoded data
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Broker delivers the events published to a topic to every subscriber of the topic
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan Event]bool
+}
+
+// Events is the broker shared by the whole API
+var Events = NewBroker()
+
+// NewBroker returns a Broker without subscribers
+func NewBroker() *Broker {
+	return &Broker{subscribers: map[string]map[chan Event]bool{}}
+}
+
+// Subscribe returns a channel that receives the events published to topic, and a function that unsubscribes it
+// The channel is closed once unsubscribed, or if the subscriber falls too far behind
+func (b *Broker) Subscribe(topic string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = map[chan Event]bool{}
+	}
+	b.subscribers[topic][ch] = true
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.remove(topic, ch)
+	}
+}
+
+// Publish sends event to the current subscribers of topic without blocking
+func (b *Broker) Publish(topic string, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[topic] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(topic, ch)
+		}
+	}
+}
+
+// Close unsubscribes every subscriber of topic, once the topic has nothing more to publish
+// Events already published are still received before the channels report being closed
+func (b *Broker) Close(topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[topic] {
+		b.remove(topic, ch)
+	}
+}
+
+// remove unsubscribes ch from topic and closes it, if it is still subscribed
+func (b *Broker) remove(topic string, ch chan Event) {
+	if !b.subscribers[topic][ch] {
+		return
+	}
+
+	delete(b.subscribers[topic], ch)
+	close(ch)
+
+	if len(b.subscribers[topic]) == 0 {
+		delete(b.subscribers, topic)
+	}
+}
+
+// Stream writes the events of ch to w as Server-Sent Events, starting with initial if it is not nil
+// It returns once the client disconnects or ch is closed, after which the client is expected to reconnect
+func Stream(w http.ResponseWriter, r *http.Request, ch <-chan Event, initial *Event) error {
+	flusher, ok := w.(http.Flusher)
+
+	if !ok {
+		return ErrNotFlushable
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if initial != nil {
+		if err := writeEvent(w, *initial); err != nil {
+			return err
+		}
+	}
+
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return err
+			}
+		case event, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := writeEvent(w, event); err != nil {
+				return err
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes event in the Server-Sent Events format
+func writeEvent(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(event.Data)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ========== PUBLISH() ========== //
+func TestPublishIfSubscribed(t *testing.T) {
+	b := NewBroker()
+	ch, unsubscribe := b.Subscribe("puzzles/1")
+
+	b.Publish("puzzles/1", Event{Type: "update", Data: 1})
+	b.Publish("puzzles/2", Event{Type: "update", Data: 2})
+
+	if event := <-ch; event.Data != 1 {
+		t.Errorf("Actual event: %+v, expected data 1", event)
+	}
+
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Errorf("Channel is open, expected closed after unsubscribing")
+	}
+
+	// Unsubscribing twice is harmless
+	unsubscribe()
+}
+
+func TestPublishIfSubscriberFallsBehind(t *testing.T) {
+	b := NewBroker()
+	ch, _ := b.Subscribe("puzzles/1")
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish("puzzles/1", Event{Type: "update", Data: i})
+	}
+
+	count := 0
+
+	for range ch {
+		count++
+	}
+
+	if count != subscriberBuffer {
+		t.Errorf("Actual events received: %d, expected: %d before being dropped", count, subscriberBuffer)
+	}
+}
+
+// ========== CLOSE() ========== //
+func TestCloseIfEventsPending(t *testing.T) {
+	b := NewBroker()
+	ch, unsubscribe := b.Subscribe("puzzles/1")
+
+	b.Publish("puzzles/1", Event{Type: "delete", Data: 1})
+	b.Close("puzzles/1")
+
+	if event, ok := <-ch; !ok || event.Type != "delete" {
+		t.Errorf("Actual event: %+v, expected pending delete event", event)
+	}
+
+	if _, ok := <-ch; ok {
+		t.Errorf("Channel is open, expected closed after closing the topic")
+	}
+
+	// Unsubscribing after the topic is closed is harmless
+	unsubscribe()
+}
+
+// ========== STREAM() ========== //
+func TestStreamIfSuccessful(t *testing.T) {
+	b := NewBroker()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ch, unsubscribe := b.Subscribe("races/1")
+		defer unsubscribe()
+
+		Stream(w, r, ch, &Event{Type: "race", Data: map[string]int{"id": 1}})
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Actual content type: %s, expected: text/event-stream", contentType)
+	}
+
+	br := bufio.NewReader(resp.Body)
+	expected := []string{"event: race\n", "data: {\"id\":1}\n", "\n", "event: winner\n", "data: 100\n", "\n"}
+
+	for i, line := range expected {
+		// The handler subscribes before writing the initial event, so the second event is not missed
+		if i == 3 {
+			b.Publish("races/1", Event{Type: "winner", Data: 100})
+		}
+
+		done := make(chan string, 1)
+
+		go func() {
+			l, _ := br.ReadString('\n')
+			done <- l
+		}()
+
+		select {
+		case actual := <-done:
+			if actual != line {
+				t.Fatalf("Actual line: %q, expected: %q", actual, line)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("No line received, expected: %q", line)
+		}
+	}
+}
@@ -2,16 +2,21 @@ package models
 
 import (
 	"errors"
+	"sort"
 	"strings"
 	"time"
 )
 
 // Board is a struct that defines fields in the db
+// Given is true for the original clues of a puzzle, which players cannot modify
+// Notes holds the candidate notes of the player as ascending value symbols, e.g. "139" or "39AG"
 type Board struct {
 	ID        uint32    `gorm:"primary_key;auto_increment;unique" json:"id"`
 	BoardRow  int       `gorm:"type:tinyint(1) unsigned; not null" json:"board_row"`
 	BoardCol  int       `gorm:"type:tinyint(1) unsigned; not null" json:"board_col"`
 	Value     int       `gorm:"type:tinyint(1) unsigned; default:0; not null" json:"value"`
+	Given     bool      `gorm:"not null" json:"given"`
+	Notes     string    `gorm:"size:16;not null" json:"notes"`
 	CreatedAt time.Time `gorm:"default:current_timestamp()" json:"created_at"`
 	UpdatedAt time.Time `gorm:"default:current_timestamp()" json:"updated_at"`
 	PuzzleID  uint32    `gorm:"not null" json:"puzzle_id"`
@@ -25,31 +30,57 @@ func (board *Board) PrepareBoard() *Board {
 	return board
 }
 
-// ValidateBoard checks if any of the above fields are empty
+// ValidateBoard checks if any of the above fields are empty or out of range for a puzzle of DefaultGridSize
+// Row and column must be from 1 to DefaultGridSize and value from 0 (empty) to DefaultGridSize
 func (board *Board) ValidateBoard(action string) error {
+	return board.ValidateBoardSize(action, DefaultGridSize)
+}
+
+// ValidateBoardSize checks if any of the above fields are empty or out of range for a puzzle of size
+// Row and column must be from 1 to size and value from 0 (empty) to size
+func (board *Board) ValidateBoardSize(action string, size int) error {
 	var err error
 
 	switch strings.ToLower(action) {
 	case "update":
-		if board.BoardRow < 1 {
+		if board.BoardRow < 1 || board.BoardRow > size {
 			return errors.New("Board has invalid value for property 'board_row'")
 		}
-		if board.BoardCol < 1 {
+		if board.BoardCol < 1 || board.BoardCol > size {
 			return errors.New("Board has invalid value for property 'board_col'")
 		}
-		if board.Value < 1 {
+		if board.Value < 0 || board.Value > size {
 			return errors.New("Board has invalid value for property 'value'")
 		}
 		if board.PuzzleID < 1 {
 			return errors.New("Board has invalid value for property 'puzzle_id'")
 		}
+	case "notes":
+		if board.BoardRow < 1 || board.BoardRow > size {
+			return errors.New("Board has invalid value for property 'board_row'")
+		}
+		if board.BoardCol < 1 || board.BoardCol > size {
+			return errors.New("Board has invalid value for property 'board_col'")
+		}
+		if !validNotes(board.Notes, size) {
+			return errors.New("Board has invalid value for property 'notes'")
+		}
+		if board.PuzzleID < 1 {
+			return errors.New("Board has invalid value for property 'puzzle_id'")
+		}
 	default:
-		if board.BoardRow < 1 {
+		if board.BoardRow < 1 || board.BoardRow > size {
 			return errors.New("Board has invalid value for property 'board_row'")
 		}
-		if board.BoardCol < 1 {
+		if board.BoardCol < 1 || board.BoardCol > size {
 			return errors.New("Board has invalid value for property 'board_col'")
 		}
+		if board.Value < 0 || board.Value > size {
+			return errors.New("Board has invalid value for property 'value'")
+		}
+		if !validNotes(board.Notes, size) {
+			return errors.New("Board has invalid value for property 'notes'")
+		}
 		if board.PuzzleID < 1 {
 			return errors.New("Board has invalid value for property 'puzzle_id'")
 		}
@@ -58,3 +89,75 @@ func (board *Board) ValidateBoard(action string) error {
 	err = nil
 	return err
 }
+
+// NoteValues returns the candidate values held in the notes of the board
+func (board *Board) NoteValues() []int {
+	values := []int{}
+
+	for _, ch := range board.Notes {
+		values = append(values, SymbolValue(ch))
+	}
+
+	return values
+}
+
+// ToggleNote adds value to the notes of the board if it is absent and removes it if it is present
+func (board *Board) ToggleNote(value int) {
+	values := []int{}
+	found := false
+
+	for _, v := range board.NoteValues() {
+		if v == value {
+			found = true
+			continue
+		}
+
+		values = append(values, v)
+	}
+
+	if !found {
+		values = append(values, value)
+	}
+
+	board.Notes = FormatNotes(values)
+}
+
+// FormatNotes returns the notes string for a set of candidate values, ignoring duplicates
+// Values outside of 1 to MaxGridSize produce notes that ValidateBoard rejects
+func FormatNotes(values []int) string {
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+
+	var notes strings.Builder
+
+	for i, v := range sorted {
+		if i > 0 && v == sorted[i-1] {
+			continue
+		}
+
+		notes.WriteByte(ValueSymbol(v))
+	}
+
+	return notes.String()
+}
+
+// validNotes checks that notes only holds the symbols of ascending values from 1 to size
+func validNotes(notes string, size int) bool {
+	if len(notes) > size {
+		return false
+	}
+
+	prev := 0
+
+	for _, ch := range notes {
+		value := SymbolValue(ch)
+
+		if value <= prev || value > size {
+			return false
+		}
+
+		prev = value
+	}
+
+	return true
+}
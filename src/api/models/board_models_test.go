@@ -1 +1,158 @@
 package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========= ValidateBoard() ========= //
+func TestIfValidateBoardSuccessfulForUpdateEmptyValue(t *testing.T) {
+	testBoard := Board{
+		BoardRow: 9,
+		BoardCol: 1,
+		Value:    0,
+		PuzzleID: 1,
+	}
+
+	// Execute test function
+	err := testBoard.ValidateBoard("update")
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+}
+
+func TestIfValidateBoardUnsuccessfulForUpdateValueAboveNine(t *testing.T) {
+	testBoard := Board{
+		BoardRow: 1,
+		BoardCol: 1,
+		Value:    10,
+		PuzzleID: 1,
+	}
+
+	expectedErr := errors.New("Board has invalid value for property 'value'")
+
+	// Execute test function
+	err := testBoard.ValidateBoard("update")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidateBoardUnsuccessfulForDefaultRowAboveNine(t *testing.T) {
+	testBoard := Board{
+		BoardRow: 10,
+		BoardCol: 1,
+		PuzzleID: 1,
+	}
+
+	expectedErr := errors.New("Board has invalid value for property 'board_row'")
+
+	// Execute test function
+	err := testBoard.ValidateBoard("")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidateBoardUnsuccessfulForNotesOutOfOrder(t *testing.T) {
+	testBoard := Board{
+		BoardRow: 1,
+		BoardCol: 1,
+		Notes:    "931",
+		PuzzleID: 1,
+	}
+
+	expectedErr := errors.New("Board has invalid value for property 'notes'")
+
+	// Execute test function
+	err := testBoard.ValidateBoard("notes")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidateBoardUnsuccessfulForNotesWithZero(t *testing.T) {
+	testBoard := Board{
+		BoardRow: 1,
+		BoardCol: 1,
+		Notes:    FormatNotes([]int{0, 4}),
+		PuzzleID: 1,
+	}
+
+	// Execute test function
+	err := testBoard.ValidateBoard("notes")
+
+	if err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
+
+func TestIfValidateBoardSizeSuccessfulForHexadoku(t *testing.T) {
+	testBoard := Board{
+		BoardRow: 16,
+		BoardCol: 12,
+		Value:    16,
+		Notes:    FormatNotes([]int{16, 3, 10}),
+		PuzzleID: 1,
+	}
+
+	if testBoard.Notes != "3AG" {
+		t.Fatalf("Actual notes: %s, expected notes: %s", testBoard.Notes, "3AG")
+	}
+
+	// Execute test function
+	err := testBoard.ValidateBoardSize("", 16)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+
+	// The same board lies outside a 12x12 puzzle
+	if err = testBoard.ValidateBoardSize("", 12); err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
+
+// ========= ToggleNote() ========= //
+func TestToggleNote(t *testing.T) {
+	testBoard := Board{
+		Notes: "139",
+	}
+
+	testBoard.ToggleNote(5)
+
+	if testBoard.Notes != "1359" {
+		t.Fatalf("Actual notes: %s, expected notes: %s", testBoard.Notes, "1359")
+	}
+
+	testBoard.ToggleNote(1)
+
+	if testBoard.Notes != "359" {
+		t.Errorf("Actual notes: %s, expected notes: %s", testBoard.Notes, "359")
+	}
+}
+
+// ========= FormatNotes() ========= //
+func TestFormatNotes(t *testing.T) {
+	notes := FormatNotes([]int{9, 2, 2, 7})
+
+	if notes != "279" {
+		t.Errorf("Actual notes: %s, expected notes: %s", notes, "279")
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Cage is a struct that defines fields in the db for a cage of a killer puzzle
+// Cells holds the cells of the cage as pairs of row and column symbols, e.g. "111221" for (1, 1), (1, 2) and (2, 1),
+// and the values of the cells must be distinct and add up to Sum
+type Cage struct {
+	ID        uint32    `gorm:"primary_key;auto_increment;unique" json:"id"`
+	Cells     string    `gorm:"size:512;not null" json:"cells"`
+	Sum       int       `gorm:"type:smallint unsigned; not null" json:"sum"`
+	CreatedAt time.Time `gorm:"default:current_timestamp()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp()" json:"updated_at"`
+	PuzzleID  uint32    `gorm:"not null" json:"puzzle_id"`
+}
+
+// PrepareCage removes whitespaces from the cells of the cage and populates CreatedAt, UpdatedAt columns
+func (cage *Cage) PrepareCage() *Cage {
+	cage.Cells = strings.ToUpper(strings.Join(strings.Fields(cage.Cells), ""))
+	cage.CreatedAt = time.Now()
+	cage.UpdatedAt = time.Now()
+	return cage
+}
+
+// ValidateCage checks if any of the above fields are empty or out of range for a puzzle of size
+// Cells must hold distinct cells of the grid, at most one per value, and Sum must be positive
+// Whether the cells are contiguous, overlap other cages or can reach Sum is checked by the killer rules
+func (cage *Cage) ValidateCage(size int) error {
+	positions := cage.Positions()
+
+	if len(cage.Cells) == 0 || len(cage.Cells)%2 != 0 || len(positions) > size {
+		return errors.New("Cage has invalid value for property 'cells'")
+	}
+
+	seen := map[[2]int]bool{}
+
+	for _, p := range positions {
+		if p[0] < 1 || p[0] > size || p[1] < 1 || p[1] > size || seen[p] {
+			return errors.New("Cage has invalid value for property 'cells'")
+		}
+
+		seen[p] = true
+	}
+
+	if cage.Sum < 1 {
+		return errors.New("Cage has invalid value for property 'sum'")
+	}
+
+	if cage.PuzzleID < 1 {
+		return errors.New("Cage has invalid value for property 'puzzle_id'")
+	}
+
+	return nil
+}
+
+// Positions returns the 1-based row and column of every cell of the cage
+// Symbols that are not values are returned as 0
+func (cage *Cage) Positions() [][2]int {
+	positions := [][2]int{}
+	cells := []rune(cage.Cells)
+
+	for i := 0; i+1 < len(cells); i += 2 {
+		positions = append(positions, [2]int{SymbolValue(cells[i]), SymbolValue(cells[i+1])})
+	}
+
+	return positions
+}
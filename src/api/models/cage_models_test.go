@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// ========= PrepareCage() ========= //
+func TestPrepareCageIfCellsSpaced(t *testing.T) {
+	testCage := Cage{Cells: "11 12 a1"}
+
+	testCage.PrepareCage()
+
+	if testCage.Cells != "1112A1" {
+		t.Errorf("Actual cells: %s, expected: 1112A1", testCage.Cells)
+	}
+}
+
+// ========= ValidateCage() ========= //
+func TestIfValidateCageSuccessful(t *testing.T) {
+	testCage := Cage{
+		Cells:    "111221",
+		Sum:      10,
+		PuzzleID: 1,
+	}
+
+	// Execute test function
+	err := testCage.ValidateCage(DefaultGridSize)
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+}
+
+func TestIfValidateCageUnsuccessfulForInvalidCells(t *testing.T) {
+	expectedErr := errors.New("Cage has invalid value for property 'cells'")
+
+	for _, cells := range []string{"", "111", "1A", "1111", "11121314151617181920"} {
+		testCage := Cage{
+			Cells:    cells,
+			Sum:      10,
+			PuzzleID: 1,
+		}
+
+		// Execute test function
+		err := testCage.ValidateCage(DefaultGridSize)
+
+		if err == nil {
+			t.Fatalf("No error for cells '%s', expected error", cells)
+		}
+
+		if err.Error() != expectedErr.Error() {
+			t.Errorf("Actual error for cells '%s': %s, expected error: %s", cells, err, expectedErr)
+		}
+	}
+}
+
+func TestIfValidateCageUnsuccessfulForMissingSum(t *testing.T) {
+	testCage := Cage{
+		Cells:    "1112",
+		PuzzleID: 1,
+	}
+
+	expectedErr := errors.New("Cage has invalid value for property 'sum'")
+
+	// Execute test function
+	err := testCage.ValidateCage(DefaultGridSize)
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultGridSize is the number of rows, columns and values of a classic puzzle
+const DefaultGridSize = 9
+
+// MaxGridSize is the number of rows, columns and values of the largest supported puzzle
+const MaxGridSize = 16
+
+// GridSizes lists the supported numbers of rows, columns and values of a puzzle
+var GridSizes = []int{4, 6, 9, 12, 16}
+
+// valueSymbols holds the character of each value from 1 to MaxGridSize, so that every cell is a single character
+// Values above 9 are written as the letters A to G
+const valueSymbols = "123456789ABCDEFG"
+
+// ValidGridSize checks that size is one of GridSizes
+func ValidGridSize(size int) bool {
+	for _, s := range GridSizes {
+		if s == size {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BoxShape returns the number of rows and columns in each box of a grid of size rows and columns
+// Boxes are as square as possible and wider than they are tall when they cannot be square, e.g. 2x3 for size 6
+func BoxShape(size int) (int, int) {
+	rows := 1
+
+	for r := 2; r*r <= size; r++ {
+		if size%r == 0 {
+			rows = r
+		}
+	}
+
+	return rows, size / rows
+}
+
+// ValueSymbol returns the character of a value from 1 to MaxGridSize, or '?' for any other value
+func ValueSymbol(value int) byte {
+	if value < 1 || value > MaxGridSize {
+		return '?'
+	}
+
+	return valueSymbols[value-1]
+}
+
+// SymbolValue returns the value of a character written by ValueSymbol, ignoring the case of letters
+// Returns 0 if ch is not the character of a value
+func SymbolValue(ch rune) int {
+	return strings.IndexRune(valueSymbols, unicode.ToUpper(ch)) + 1
+}
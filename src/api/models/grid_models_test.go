@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+// ========= BoxShape() ========= //
+func TestBoxShape(t *testing.T) {
+	expected := map[int][2]int{4: {2, 2}, 6: {2, 3}, 9: {3, 3}, 12: {3, 4}, 16: {4, 4}}
+
+	for size, shape := range expected {
+		if rows, cols := BoxShape(size); rows != shape[0] || cols != shape[1] {
+			t.Errorf("Actual box shape for size %d: %dx%d, expected: %dx%d", size, rows, cols, shape[0], shape[1])
+		}
+	}
+}
+
+// ========= SymbolValue() ========= //
+func TestSymbolValue(t *testing.T) {
+	for value := 1; value <= MaxGridSize; value++ {
+		if actual := SymbolValue(rune(ValueSymbol(value))); actual != value {
+			t.Errorf("Actual value of %q: %d, expected: %d", ValueSymbol(value), actual, value)
+		}
+	}
+
+	if SymbolValue('g') != 16 || SymbolValue('0') != 0 || SymbolValue('.') != 0 || SymbolValue('H') != 0 {
+		t.Errorf("Actual values of 'g', '0', '.' and 'H': %d, %d, %d, %d, expected: 16, 0, 0, 0",
+			SymbolValue('g'), SymbolValue('0'), SymbolValue('.'), SymbolValue('H'))
+	}
+}
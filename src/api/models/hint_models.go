@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Hint is a struct that defines fields in the db for a hint given to a user, so that the techniques
+// the user relies on can be counted
+type Hint struct {
+	ID        uint32    `gorm:"primary_key;auto_increment;unique" json:"id"`
+	Technique string    `gorm:"size:30;not null" json:"technique"`
+	CreatedAt time.Time `gorm:"default:current_timestamp()" json:"created_at"`
+	PuzzleID  uint32    `gorm:"not null" json:"puzzle_id"`
+	UserID    uint32    `gorm:"not null" json:"user_id"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Windows of time covered by a leaderboard
+const (
+	WindowDay  = "day"
+	WindowWeek = "week"
+	WindowAll  = "all"
+)
+
+// LeaderboardEntry is a row of a leaderboard, aggregating the completed sessions of a user
+// Rank starts from 1 and is assigned after the entries are sorted
+type LeaderboardEntry struct {
+	Rank          int     `gorm:"-" json:"rank"`
+	UserID        uint32  `json:"user_id"`
+	Username      string  `json:"username"`
+	Solved        int     `json:"solved"`
+	BestMillis    int64   `json:"best_millis"`
+	AverageMillis float64 `json:"average_millis"`
+}
+
+// WindowStart returns the start of the leaderboard window as of now, in UTC
+// Days start at midnight and weeks start on Monday. The all-time window returns the zero time
+func WindowStart(window string, now time.Time) (time.Time, error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch strings.ToLower(window) {
+	case WindowDay:
+		return day, nil
+	case WindowWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), nil
+	case WindowAll, "":
+		return time.Time{}, nil
+	}
+
+	return time.Time{}, errors.New("Leaderboard window must be one of 'day', 'week' or 'all'")
+}
+
+// RankLeaderboard assigns ranks to sorted entries, with tied entries sharing the same rank
+// tied reports whether two consecutive entries are tied
+func RankLeaderboard(entries []LeaderboardEntry, tied func(a, b LeaderboardEntry) bool) []LeaderboardEntry {
+	for i := range entries {
+		entries[i].Rank = i + 1
+
+		if i > 0 && tied(entries[i-1], entries[i]) {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+
+	return entries
+}
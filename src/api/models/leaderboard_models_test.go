@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// ========= WindowStart() ========= //
+func TestWindowStartForWeek(t *testing.T) {
+	// 2 January 2020 is a Thursday
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	start, err := WindowStart(WindowWeek, now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC); !start.Equal(expected) {
+		t.Errorf("Actual start: %v, expected: %v", start, expected)
+	}
+}
+
+func TestWindowStartIfInvalid(t *testing.T) {
+	if _, err := WindowStart("month", time.Now()); err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
+
+// ========= RankLeaderboard() ========= //
+func TestRankLeaderboardIfTied(t *testing.T) {
+	entries := []LeaderboardEntry{
+		LeaderboardEntry{UserID: 1, BestMillis: 1000},
+		LeaderboardEntry{UserID: 2, BestMillis: 1000},
+		LeaderboardEntry{UserID: 3, BestMillis: 2000},
+	}
+
+	RankLeaderboard(entries, func(a, b LeaderboardEntry) bool {
+		return a.BestMillis == b.BestMillis
+	})
+
+	if entries[0].Rank != 1 || entries[1].Rank != 1 || entries[2].Rank != 3 {
+		t.Errorf("Actual ranks: %d, %d, %d, expected: 1, 1, 3", entries[0].Rank, entries[1].Rank, entries[2].Rank)
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Kinds of moves in the move log
+const (
+	MoveSet  = "set"
+	MoveUndo = "undo"
+	MoveRedo = "redo"
+)
+
+// Move is an entry of the append-only log of changes to the value of a board
+// Undo and redo moves are appended with Ref set to the ID of the set move that they revert or repeat
+type Move struct {
+	ID        uint32    `gorm:"primary_key;auto_increment;unique" json:"id"`
+	Kind      string    `gorm:"size:4;not null" json:"kind"`
+	BoardRow  int       `gorm:"type:tinyint(1) unsigned; not null" json:"board_row"`
+	BoardCol  int       `gorm:"type:tinyint(1) unsigned; not null" json:"board_col"`
+	OldValue  int       `gorm:"type:tinyint(1) unsigned; not null" json:"old_value"`
+	NewValue  int       `gorm:"type:tinyint(1) unsigned; not null" json:"new_value"`
+	Ref       uint32    `gorm:"not null" json:"ref,omitempty"`
+	CreatedAt time.Time `gorm:"default:current_timestamp()" json:"created_at"`
+	PuzzleID  uint32    `gorm:"not null" json:"puzzle_id"`
+	UserID    uint32    `gorm:"not null" json:"user_id"`
+}
+
+// MoveStacks replays a move log in order and returns the set moves that are in effect and the set moves that
+// have been undone, with the next move to undo or redo last
+// A new set move discards the moves that could be redone
+func MoveStacks(moves []Move) ([]Move, []Move) {
+	done := []Move{}
+	undone := []Move{}
+
+	for _, move := range moves {
+		switch move.Kind {
+		case MoveSet:
+			done = append(done, move)
+			undone = []Move{}
+		case MoveUndo:
+			if len(done) > 0 {
+				undone = append(undone, done[len(done)-1])
+				done = done[:len(done)-1]
+			}
+		case MoveRedo:
+			if len(undone) > 0 {
+				done = append(done, undone[len(undone)-1])
+				undone = undone[:len(undone)-1]
+			}
+		}
+	}
+
+	return done, undone
+}
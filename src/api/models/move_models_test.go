@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+// ========= MoveStacks() ========= //
+func TestMoveStacks(t *testing.T) {
+	moves := []Move{
+		Move{ID: 1, Kind: MoveSet},
+		Move{ID: 2, Kind: MoveSet},
+		Move{ID: 3, Kind: MoveSet},
+		Move{ID: 4, Kind: MoveUndo, Ref: 3},
+		Move{ID: 5, Kind: MoveUndo, Ref: 2},
+		Move{ID: 6, Kind: MoveRedo, Ref: 2},
+	}
+
+	done, undone := MoveStacks(moves)
+
+	if len(done) != 2 || done[1].ID != 2 || len(undone) != 1 || undone[0].ID != 3 {
+		t.Fatalf("Actual done: %+v, undone: %+v, expected moves 1 and 2 done and move 3 undone", done, undone)
+	}
+
+	// A new move cannot be followed by a redo of older moves
+	done, undone = MoveStacks(append(moves, Move{ID: 7, Kind: MoveSet}))
+
+	if len(done) != 3 || len(undone) != 0 {
+		t.Errorf("Actual done: %+v, undone: %+v, expected 3 moves done and none undone", done, undone)
+	}
+}
@@ -8,18 +8,50 @@ import (
 )
 
 // Puzzle is a struct that defines fields in the db
+// Daily is set to the date of the daily puzzle for the copies of daily puzzles that users play
+// ShareCode is set while the owner shares the puzzle, and lets other users clone its givens. It is left out of the JSON
+// of the puzzle, since puzzles are served from shared caches, and is only returned to the owner by SharePuzzle
+// OriginID is set on clones to the puzzle that was first shared, so that all copies of a puzzle share a leaderboard
+// Size is the number of rows, columns and values of the grid, which is divided into boxes of BoxRows by BoxCols cells
+// Variants lists the variant rules of the puzzle, see variant_models.go, with the jigsaw regions in Regions and the
+// even and odd cells in Parity
 type Puzzle struct {
-	ID        uint32    `gorm:"primary_key;auto_increment;unique" json:"id"`
-	Name      string    `gorm:"size:20;not null;unique" json:"name"`
-	CreatedAt time.Time `gorm:"default:current_timestamp()" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:current_timestamp()" json:"updated_at"`
-	UserID    uint32    `gorm:"not null" json:"user_id"`
-	Boards    []Board   `gorm:"foreignkey:PuzzleID association_foreignkey:ID" json:"boards"`
+	ID         uint32    `gorm:"primary_key;auto_increment;unique" json:"id"`
+	Name       string    `gorm:"size:20;not null;unique" json:"name"`
+	Difficulty string    `gorm:"size:10" json:"difficulty,omitempty"`
+	Score      int       `gorm:"not null" json:"score"`
+	Seed       int64     `json:"seed,omitempty"`
+	Daily      string    `gorm:"size:10;index" json:"daily,omitempty"`
+	ShareCode  *string   `gorm:"size:24;unique_index" json:"-"`
+	OriginID   uint32    `gorm:"index" json:"origin_id,omitempty"`
+	Size       int       `gorm:"not null" json:"size"`
+	BoxRows    int       `gorm:"not null" json:"box_rows"`
+	BoxCols    int       `gorm:"not null" json:"box_cols"`
+	Variants   string    `gorm:"size:100;not null" json:"variants"`
+	Regions    string    `gorm:"size:256;not null" json:"regions,omitempty"`
+	Parity     string    `gorm:"size:256;not null" json:"parity,omitempty"`
+	CreatedAt  time.Time `gorm:"default:current_timestamp()" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"default:current_timestamp()" json:"updated_at"`
+	UserID     uint32    `gorm:"not null" json:"user_id"`
+	Boards     []Board   `gorm:"foreignkey:PuzzleID association_foreignkey:ID" json:"boards"`
+	Givens     [][]int   `gorm:"-" json:"givens,omitempty"`
 }
 
 // PreparePuzzle removes whitespaces from puzzle fields
+// A puzzle without a size takes the size of its givens, or DefaultGridSize, and the box shape of its size
 func (puzzle *Puzzle) PreparePuzzle() *Puzzle {
 	puzzle.Name = html.EscapeString(strings.TrimSpace(puzzle.Name))
+
+	puzzle.Size = puzzle.GridSize()
+
+	if puzzle.BoxRows == 0 && puzzle.BoxCols == 0 {
+		puzzle.BoxRows, puzzle.BoxCols = BoxShape(puzzle.Size)
+	}
+
+	puzzle.Variants = strings.Join(puzzle.VariantNames(), ",")
+	puzzle.Regions = strings.ToUpper(strings.TrimSpace(puzzle.Regions))
+	puzzle.Parity = strings.ToUpper(strings.TrimSpace(puzzle.Parity))
+
 	puzzle.CreatedAt = time.Now()
 	puzzle.UpdatedAt = time.Now()
 	return puzzle
@@ -38,6 +70,14 @@ func (puzzle *Puzzle) ValidatePuzzle(action string) error {
 		if puzzle.UserID < 1 {
 			return errors.New("Puzzle has invalid value for property 'user_id'")
 		}
+	case "givens":
+		if puzzle.Givens == nil {
+			return errors.New("Puzzle must have defined property 'givens'")
+		}
+
+		if !validGivens(puzzle.Givens, len(puzzle.Givens)) {
+			return errors.New("Puzzle has invalid value for property 'givens'")
+		}
 	default:
 		if puzzle.Name == "" {
 			return errors.New("Puzzle must have defined property 'name'")
@@ -46,8 +86,71 @@ func (puzzle *Puzzle) ValidatePuzzle(action string) error {
 		if puzzle.UserID < 1 {
 			return errors.New("Puzzle has invalid value for property 'user_id'")
 		}
+
+		size := puzzle.GridSize()
+
+		if !ValidGridSize(size) {
+			return errors.New("Puzzle has invalid value for property 'size'")
+		}
+
+		// Boxes are not set before PreparePuzzle, and must then match the size
+		boxRows, boxCols := BoxShape(size)
+
+		if (puzzle.BoxRows != 0 || puzzle.BoxCols != 0) && (puzzle.BoxRows != boxRows || puzzle.BoxCols != boxCols) {
+			return errors.New("Puzzle has invalid values for properties 'box_rows' and 'box_cols'")
+		}
+
+		if puzzle.Givens != nil && !validGivens(puzzle.Givens, size) {
+			return errors.New("Puzzle has invalid value for property 'givens'")
+		}
+
+		if !validVariants(puzzle.VariantNames()) {
+			return errors.New("Puzzle has invalid value for property 'variants'")
+		}
+
+		// Regions and parities are only stored for the variants that use them
+		if !validRegions(puzzle.Regions, size, puzzle.HasVariant(VariantJigsaw)) {
+			return errors.New("Puzzle has invalid value for property 'regions'")
+		}
+
+		if !validParity(puzzle.Parity, size, puzzle.HasVariant(VariantEvenOdd)) {
+			return errors.New("Puzzle has invalid value for property 'parity'")
+		}
 	}
 
 	err = nil
 	return err
 }
+
+// GridSize returns the size of the puzzle, defaulting to the size of its givens or DefaultGridSize
+func (puzzle *Puzzle) GridSize() int {
+	switch {
+	case puzzle.Size != 0:
+		return puzzle.Size
+	case puzzle.Givens != nil:
+		return len(puzzle.Givens)
+	}
+
+	return DefaultGridSize
+}
+
+// validGivens checks that givens is a grid of a supported size with values from 0 (empty) to size
+func validGivens(givens [][]int, size int) bool {
+	if !ValidGridSize(size) || len(givens) != size {
+		return false
+	}
+
+	for _, row := range givens {
+		if len(row) != size {
+			return false
+		}
+
+		for _, value := range row {
+			if value < 0 || value > size {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testGivens returns a 9x9 grid of empty givens
+func testGivens() [][]int {
+	givens := make([][]int, 9)
+
+	for i := range givens {
+		givens[i] = make([]int, 9)
+	}
+
+	return givens
+}
+
+// ========= ValidatePuzzle() ========= //
+func TestIfValidatePuzzleSuccessfulForDefaultWithGivens(t *testing.T) {
+	testPuzzle := Puzzle{
+		Name:   "testpuzzle1",
+		UserID: 100,
+		Givens: testGivens(),
+	}
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("")
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+}
+
+func TestIfValidatePuzzleUnsuccessfulForDefaultInvalidGivens(t *testing.T) {
+	testPuzzle := Puzzle{
+		Name:   "testpuzzle1",
+		UserID: 100,
+		Givens: testGivens(),
+	}
+
+	testPuzzle.Givens[4][4] = 10
+
+	expectedErr := errors.New("Puzzle has invalid value for property 'givens'")
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidatePuzzleUnsuccessfulForGivensMissingGivens(t *testing.T) {
+	testPuzzle := Puzzle{}
+
+	expectedErr := errors.New("Puzzle must have defined property 'givens'")
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("givens")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidatePuzzleUnsuccessfulForGivensInvalidSize(t *testing.T) {
+	testPuzzle := Puzzle{
+		Givens: testGivens()[:8],
+	}
+
+	expectedErr := errors.New("Puzzle has invalid value for property 'givens'")
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("givens")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidatePuzzleSuccessfulForDefaultSixBySix(t *testing.T) {
+	givens := make([][]int, 6)
+
+	for i := range givens {
+		givens[i] = make([]int, 6)
+	}
+
+	givens[5][5] = 6
+
+	testPuzzle := Puzzle{
+		Name:   "testpuzzle1",
+		UserID: 100,
+		Givens: givens,
+	}
+
+	// The size and boxes are taken from the givens
+	testPuzzle.PreparePuzzle()
+
+	if testPuzzle.Size != 6 || testPuzzle.BoxRows != 2 || testPuzzle.BoxCols != 3 {
+		t.Fatalf("Actual size: %d with %dx%d boxes, expected: 6 with 2x3 boxes", testPuzzle.Size, testPuzzle.BoxRows, testPuzzle.BoxCols)
+	}
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("")
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+}
+
+func TestIfValidatePuzzleUnsuccessfulForDefaultInvalidBoxes(t *testing.T) {
+	testPuzzle := Puzzle{
+		Name:    "testpuzzle1",
+		UserID:  100,
+		Size:    6,
+		BoxRows: 3,
+		BoxCols: 2,
+	}
+
+	expectedErr := errors.New("Puzzle has invalid values for properties 'box_rows' and 'box_cols'")
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("")
+
+	if err == nil {
+		t.Fatalf("No error, expected error")
+	}
+
+	if err.Error() != expectedErr.Error() {
+		t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+	}
+}
+
+func TestIfValidatePuzzleSuccessfulForDefaultVariants(t *testing.T) {
+	testPuzzle := Puzzle{
+		Name:     "testpuzzle1",
+		UserID:   100,
+		Size:     4,
+		Variants: " Jigsaw, x ,even_odd",
+		Regions:  "1111" + "2223" + "2433" + "4443",
+		Parity:   "e..." + "...." + "...." + "...o",
+	}
+
+	// Variants are trimmed and lower-cased, and parities are upper-cased
+	testPuzzle.PreparePuzzle()
+
+	if testPuzzle.Variants != "jigsaw,x,even_odd" || testPuzzle.Parity[0] != EvenCell || !testPuzzle.HasVariant(VariantX) {
+		t.Fatalf("Actual variants: %s with parity %s, expected: jigsaw,x,even_odd with upper-cased parity", testPuzzle.Variants, testPuzzle.Parity)
+	}
+
+	// Execute test function
+	err := testPuzzle.ValidatePuzzle("")
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+}
+
+func TestIfValidatePuzzleUnsuccessfulForDefaultInvalidVariants(t *testing.T) {
+	cases := []struct {
+		puzzle   Puzzle
+		property string
+	}{
+		{Puzzle{Variants: "x,sandwich"}, "variants"},
+		{Puzzle{Variants: "x,x"}, "variants"},
+		{Puzzle{Variants: "jigsaw", Regions: "123"}, "regions"},
+		{Puzzle{Regions: strings.Repeat("1", 81)}, "regions"},
+		{Puzzle{Variants: "even_odd", Parity: strings.Repeat("X", 81)}, "parity"},
+	}
+
+	for _, c := range cases {
+		c.puzzle.Name = "testpuzzle1"
+		c.puzzle.UserID = 100
+
+		expectedErr := fmt.Errorf("Puzzle has invalid value for property '%s'", c.property)
+
+		// Execute test function
+		err := c.puzzle.ValidatePuzzle("")
+
+		if err == nil {
+			t.Fatalf("No error for variants %q, expected error", c.puzzle.Variants)
+		}
+
+		if err.Error() != expectedErr.Error() {
+			t.Errorf("Actual error: %s, expected error: %s", err, expectedErr)
+		}
+	}
+}
+
+// ========= json.Marshal() ========= //
+func TestPuzzleJSONOmitsShareCode(t *testing.T) {
+	code := "testsharecode"
+	puzzle := Puzzle{ID: 1, Name: "testpuzzle1", ShareCode: &code}
+
+	// Execute test function
+	data, err := json.Marshal(puzzle)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), code) {
+		t.Errorf("Puzzle JSON %s contains the share code, expected it to be left out", data)
+	}
+}
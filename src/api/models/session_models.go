@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Statuses of a game session
+const (
+	SessionActive = "active"
+	SessionPaused = "paused"
+	SessionSolved = "solved"
+)
+
+// Session is a struct that defines fields in the db for a user playing a puzzle
+// PausedAt is set while the session is paused and PausedMillis is the total length of all finished pauses.
+// ElapsedMillis is the playing time without pauses, which is fixed once the session is completed
+type Session struct {
+	ID            uint32     `gorm:"primary_key;auto_increment;unique" json:"id"`
+	StartedAt     time.Time  `gorm:"not null" json:"started_at"`
+	PausedAt      *time.Time `json:"paused_at"`
+	PausedMillis  int64      `gorm:"not null" json:"paused_millis"`
+	ElapsedMillis int64      `gorm:"not null" json:"elapsed_millis"`
+	Moves         int        `gorm:"not null" json:"moves"`
+	Mistakes      int        `gorm:"not null" json:"mistakes"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	Status        string     `gorm:"-" json:"status"`
+	CreatedAt     time.Time  `gorm:"default:current_timestamp()" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"default:current_timestamp()" json:"updated_at"`
+	PuzzleID      uint32     `gorm:"not null" json:"puzzle_id"`
+	UserID        uint32     `gorm:"not null" json:"user_id"`
+}
+
+// PrepareSession starts the session and populates CreatedAt, UpdatedAt columns
+func (session *Session) PrepareSession() *Session {
+	session.StartedAt = time.Now()
+	session.CreatedAt = session.StartedAt
+	session.UpdatedAt = session.StartedAt
+	return session.Refresh(session.StartedAt)
+}
+
+// ValidateSession checks if any of the above fields are empty or out of range
+func (session *Session) ValidateSession(action string) error {
+	var err error
+
+	switch strings.ToLower(action) {
+	case "pause":
+		if session.CompletedAt != nil {
+			return errors.New("Session is already completed")
+		}
+		if session.PausedAt != nil {
+			return errors.New("Session is already paused")
+		}
+	case "resume":
+		if session.CompletedAt != nil {
+			return errors.New("Session is already completed")
+		}
+		if session.PausedAt == nil {
+			return errors.New("Session is not paused")
+		}
+	default:
+		if session.PuzzleID < 1 {
+			return errors.New("Session has invalid value for property 'puzzle_id'")
+		}
+		if session.UserID < 1 {
+			return errors.New("Session has invalid value for property 'user_id'")
+		}
+	}
+
+	err = nil
+	return err
+}
+
+// Pause stops the timer of the session at now
+func (session *Session) Pause(now time.Time) *Session {
+	session.PausedAt = &now
+	return session.Refresh(now)
+}
+
+// Resume restarts the timer of a paused session at now, adding the pause to PausedMillis
+func (session *Session) Resume(now time.Time) *Session {
+	if session.PausedAt != nil {
+		session.PausedMillis += now.Sub(*session.PausedAt).Nanoseconds() / int64(time.Millisecond)
+		session.PausedAt = nil
+	}
+
+	return session.Refresh(now)
+}
+
+// Complete marks the session as solved at now, resuming it first if it is paused
+func (session *Session) Complete(now time.Time) *Session {
+	session.Resume(now)
+	session.CompletedAt = &now
+	return session.Refresh(now)
+}
+
+// Refresh computes Status and ElapsedMillis as of now
+func (session *Session) Refresh(now time.Time) *Session {
+	end := now
+
+	switch {
+	case session.CompletedAt != nil:
+		end = *session.CompletedAt
+		session.Status = SessionSolved
+	case session.PausedAt != nil:
+		end = *session.PausedAt
+		session.Status = SessionPaused
+	default:
+		session.Status = SessionActive
+	}
+
+	session.ElapsedMillis = end.Sub(session.StartedAt).Nanoseconds()/int64(time.Millisecond) - session.PausedMillis
+
+	if session.ElapsedMillis < 0 {
+		session.ElapsedMillis = 0
+	}
+
+	return session
+}
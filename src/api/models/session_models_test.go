@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// ========= Pause() / Resume() / Complete() ========= //
+func TestSessionElapsedExcludesPauses(t *testing.T) {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	session := Session{StartedAt: start}
+
+	session.Pause(start.Add(30 * time.Second))
+
+	if session.Status != SessionPaused || session.ElapsedMillis != 30000 {
+		t.Fatalf("Actual status: %s, elapsed: %d, expected: %s, %d", session.Status, session.ElapsedMillis, SessionPaused, 30000)
+	}
+
+	// Time spent paused does not count
+	session.Refresh(start.Add(90 * time.Second))
+
+	if session.ElapsedMillis != 30000 {
+		t.Fatalf("Actual elapsed: %d, expected: %d", session.ElapsedMillis, 30000)
+	}
+
+	session.Resume(start.Add(90 * time.Second))
+	session.Complete(start.Add(100 * time.Second))
+
+	if session.Status != SessionSolved || session.ElapsedMillis != 40000 || session.PausedMillis != 60000 {
+		t.Errorf("Actual status: %s, elapsed: %d, paused: %d, expected: %s, %d, %d",
+			session.Status, session.ElapsedMillis, session.PausedMillis, SessionSolved, 40000, 60000)
+	}
+
+	// Elapsed time is fixed once the session is completed
+	session.Refresh(start.Add(time.Hour))
+
+	if session.ElapsedMillis != 40000 {
+		t.Errorf("Actual elapsed: %d, expected: %d", session.ElapsedMillis, 40000)
+	}
+}
+
+// ========= ValidateSession() ========= //
+func TestIfValidateSessionUnsuccessfulForPauseIfPaused(t *testing.T) {
+	now := time.Now()
+	session := Session{StartedAt: now, PausedAt: &now}
+
+	if err := session.ValidateSession("pause"); err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// AbandonAfter is how long an unfinished session can go without moves before it counts as abandoned
+const AbandonAfter = 7 * 24 * time.Hour
+
+// UserStats is the personal statistics of a user, aggregated from their puzzles, sessions and hints
+// Streaks count consecutive days with at least one solved puzzle. The current streak is kept alive until the end of
+// the day after the last solve
+type UserStats struct {
+	UserID        uint32            `json:"user_id"`
+	Created       int               `json:"created"`
+	Solved        int               `json:"solved"`
+	Abandoned     int               `json:"abandoned"`
+	Difficulties  []DifficultyStats `json:"difficulties"`
+	CurrentStreak int               `json:"current_streak"`
+	LongestStreak int               `json:"longest_streak"`
+	Hints         []HintUsage       `json:"hints"`
+}
+
+// DifficultyStats aggregates the completed sessions of a user on puzzles of a difficulty
+type DifficultyStats struct {
+	Difficulty    string  `json:"difficulty"`
+	Solved        int     `json:"solved"`
+	BestMillis    int64   `json:"best_millis"`
+	AverageMillis float64 `json:"average_millis"`
+}
+
+// HintUsage counts the hints given to a user that were found with a technique
+type HintUsage struct {
+	Technique string `json:"technique"`
+	Count     int    `json:"count"`
+}
+
+// Streaks returns the current and longest runs of consecutive days in days, as of now
+// Days and now are compared by their dates in UTC, so they may be in any time zone
+func Streaks(days []time.Time, now time.Time) (int, int) {
+	dates := make([]time.Time, len(days))
+
+	for i, day := range days {
+		dates[i] = date(day)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	current, longest, run := 0, 0, 0
+
+	for i, day := range dates {
+		switch {
+		case i > 0 && day.Equal(dates[i-1]):
+			continue
+		case i > 0 && day.Equal(dates[i-1].AddDate(0, 0, 1)):
+			run++
+		default:
+			run = 1
+		}
+
+		if run > longest {
+			longest = run
+		}
+	}
+
+	today := date(now)
+
+	if n := len(dates); n > 0 && (dates[n-1].Equal(today) || dates[n-1].Equal(today.AddDate(0, 0, -1))) {
+		current = run
+	}
+
+	return current, longest
+}
+
+// date returns midnight UTC of the date of t in UTC, so that times in any time zone fall on the same days
+func date(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// ========= Streaks() ========= //
+func TestStreaksIfSolvedYesterday(t *testing.T) {
+	now := time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 20, 0, 0, 0, time.UTC) }
+
+	// A run of 4 days, then a run of 2 days ending yesterday that is still alive, solved twice on one day
+	current, longest := Streaks([]time.Time{day(9), day(2), day(3), day(8), day(4), day(5), day(9)}, now)
+
+	if current != 2 || longest != 4 {
+		t.Errorf("Actual streaks: %d current, %d longest, expected: 2 current, 4 longest", current, longest)
+	}
+}
+
+func TestStreaksIfBroken(t *testing.T) {
+	now := time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	current, longest := Streaks([]time.Time{time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)}, now)
+
+	if current != 0 || longest != 1 {
+		t.Errorf("Actual streaks: %d current, %d longest, expected: 0 current, 1 longest", current, longest)
+	}
+
+	if current, longest = Streaks(nil, now); current != 0 || longest != 0 {
+		t.Errorf("Actual streaks: %d current, %d longest, expected none", current, longest)
+	}
+}
+
+func TestStreaksIfOtherTimeZone(t *testing.T) {
+	zone := time.FixedZone("UTC+8", 8*60*60)
+
+	// It is already the 11th in UTC+8, but still the 10th in UTC
+	now := time.Date(2020, 1, 11, 2, 0, 0, 0, zone)
+
+	// 01:00 on the 10th in UTC+8 is on the 9th in UTC, and 20:00 on the 10th in UTC+8 is on the 10th in UTC
+	current, longest := Streaks([]time.Time{time.Date(2020, 1, 10, 1, 0, 0, 0, zone), time.Date(2020, 1, 10, 20, 0, 0, 0, zone)}, now)
+
+	if current != 2 || longest != 2 {
+		t.Errorf("Actual streaks: %d current, %d longest, expected: 2 current, 2 longest", current, longest)
+	}
+}
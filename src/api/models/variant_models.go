@@ -0,0 +1,114 @@
+package models
+
+import "strings"
+
+// Names of the variants that a puzzle can declare in Variants, on top of the classic rules
+const (
+	// VariantX requires both main diagonals to hold every value once
+	VariantX = "x"
+	// VariantKiller adds cages of cells whose values are distinct and add up to the sum of the cage
+	VariantKiller = "killer"
+	// VariantJigsaw replaces the boxes with the irregular regions in Regions
+	VariantJigsaw = "jigsaw"
+	// VariantAntiKnight forbids a value from repeating a chess knight's move away
+	VariantAntiKnight = "anti_knight"
+	// VariantAntiKing forbids a value from repeating a chess king's move away
+	VariantAntiKing = "anti_king"
+	// VariantEvenOdd limits the cells marked in Parity to even or odd values
+	VariantEvenOdd = "even_odd"
+)
+
+// Variants lists the supported variants
+var Variants = []string{VariantX, VariantKiller, VariantJigsaw, VariantAntiKnight, VariantAntiKing, VariantEvenOdd}
+
+// Characters of the cells in Parity, where any other cell is written as '.'
+const (
+	EvenCell = 'E'
+	OddCell  = 'O'
+)
+
+// VariantNames returns the variants of the puzzle, which are stored in Variants separated by commas
+func (puzzle *Puzzle) VariantNames() []string {
+	names := []string{}
+
+	for _, name := range strings.Split(puzzle.Variants, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// HasVariant checks that the puzzle declares the variant name
+func (puzzle *Puzzle) HasVariant(name string) bool {
+	for _, n := range puzzle.VariantNames() {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validVariants checks that names only holds supported variants, each at most once
+func validVariants(names []string) bool {
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		supported := false
+
+		for _, v := range Variants {
+			supported = supported || v == name
+		}
+
+		if !supported || seen[name] {
+			return false
+		}
+
+		seen[name] = true
+	}
+
+	return true
+}
+
+// validRegions checks that regions holds the region symbol of every cell if required, and is empty otherwise
+// Whether the regions are connected and of the right size is checked by the sudoku package
+func validRegions(regions string, size int, required bool) bool {
+	if !required {
+		return regions == ""
+	}
+
+	if len(regions) != size*size {
+		return false
+	}
+
+	for _, ch := range regions {
+		if value := SymbolValue(ch); value < 1 || value > size {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validParity checks that parity marks every cell as EvenCell, OddCell or '.' if required, and is empty otherwise
+func validParity(parity string, size int, required bool) bool {
+	if !required {
+		return parity == ""
+	}
+
+	if len(parity) != size*size {
+		return false
+	}
+
+	for _, ch := range parity {
+		if ch != EvenCell && ch != OddCell && ch != '.' {
+			return false
+		}
+	}
+
+	return true
+}
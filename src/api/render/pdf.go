@@ -0,0 +1,153 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// pdfCanvas draws pages into a PDF 1.4 document with the standard Helvetica fonts, which every reader has, so that
+// no font needs to be embedded
+type pdfCanvas struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+// helveticaWidths holds the widths of the symbols of cell values in Helvetica, in thousandths of the font size
+// Digits are the same width in both weights
+var helveticaWidths = map[rune][2]float64{
+	'A': {667, 722}, 'B': {667, 722}, 'C': {722, 722}, 'D': {722, 722}, 'E': {667, 667}, 'F': {611, 611}, 'G': {778, 778},
+}
+
+func (c *pdfCanvas) beginPage() {
+	c.page = &bytes.Buffer{}
+	c.pages = append(c.pages, c.page)
+}
+
+func (c *pdfCanvas) line(x1, y1, x2, y2, width, gray float64, dashed bool) {
+	dash := "[] 0 d"
+
+	if dashed {
+		dash = fmt.Sprintf("[%.2f %.2f] 0 d", width*4, width*3)
+	}
+
+	fmt.Fprintf(c.page, "%.2f w %.3f G %s %.2f %.2f m %.2f %.2f l S\n", width, gray, dash, x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+func (c *pdfCanvas) fill(x, y, w, h, gray float64) {
+	fmt.Fprintf(c.page, "%.3f g %.2f %.2f %.2f %.2f re f\n", gray, x, pageHeight-y-h, w, h)
+}
+
+func (c *pdfCanvas) circle(cx, cy, r, gray float64) {
+	// A circle is four Bézier curves, each with its control points k of the radius away from its ends
+	k := 0.5523 * r
+	cy = pageHeight - cy
+
+	fmt.Fprintf(c.page, "%.3f g %.2f %.2f m ", gray, cx+r, cy)
+	fmt.Fprintf(c.page, "%.2f %.2f %.2f %.2f %.2f %.2f c ", cx+r, cy+k, cx+k, cy+r, cx, cy+r)
+	fmt.Fprintf(c.page, "%.2f %.2f %.2f %.2f %.2f %.2f c ", cx-k, cy+r, cx-r, cy+k, cx-r, cy)
+	fmt.Fprintf(c.page, "%.2f %.2f %.2f %.2f %.2f %.2f c ", cx-r, cy-k, cx-k, cy-r, cx, cy-r)
+	fmt.Fprintf(c.page, "%.2f %.2f %.2f %.2f %.2f %.2f c f\n", cx+k, cy-r, cx+r, cy-k, cx+r, cy)
+}
+
+func (c *pdfCanvas) text(x, y, size float64, bold bool, gray float64, centered bool, s string) {
+	font := "F1"
+
+	if bold {
+		font = "F2"
+	}
+
+	if centered {
+		x -= textWidth(s, size, bold) / 2
+	}
+
+	fmt.Fprintf(c.page, "BT /%s %.2f Tf %.3f g %.2f %.2f Td (%s) Tj ET\n", font, size, gray, x, pageHeight-y, pdfString(s))
+}
+
+func (c *pdfCanvas) finish(w io.Writer) error {
+	// Objects 1 to 4 are the catalog, the page tree and both fonts, followed by each page and its contents
+	doc := &bytes.Buffer{}
+	offsets := []int{}
+
+	object := func(format string, args ...interface{}) {
+		offsets = append(offsets, doc.Len())
+		fmt.Fprintf(doc, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(doc, format, args...)
+		doc.WriteString("\nendobj\n")
+	}
+
+	kids := &bytes.Buffer{}
+
+	for i := range c.pages {
+		fmt.Fprintf(kids, " %d 0 R", 5+2*i)
+	}
+
+	doc.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [%s ] /Count %d >>", kids.String(), len(c.pages))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range c.pages {
+		object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i)
+		object("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String())
+	}
+
+	xref := doc.Len()
+	fmt.Fprintf(doc, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(doc, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(doc, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// textWidth returns the width of s in Helvetica at size, to center the symbols of cell values
+// Other characters are taken to be as wide as a digit
+func textWidth(s string, size float64, bold bool) float64 {
+	width := 0.0
+
+	for _, ch := range s {
+		w, ok := helveticaWidths[ch]
+
+		switch {
+		case !ok:
+			width += 556
+		case bold:
+			width += w[1]
+		default:
+			width += w[0]
+		}
+	}
+
+	return width * size / 1000
+}
+
+// pdfString escapes s for a PDF string in a font with WinAnsi encoding
+// Characters that the encoding does not have are replaced with '?'
+func pdfString(s string) string {
+	b := &bytes.Buffer{}
+
+	for _, ch := range s {
+		switch {
+		case ch == '(' || ch == ')' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(ch)
+		case ch < ' ':
+			continue
+		case ch < 0x80:
+			b.WriteRune(ch)
+		case ch >= 0xa0 && ch <= 0xff:
+			fmt.Fprintf(b, "\\%03o", ch)
+		default:
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,401 @@
+// Package render draws puzzles as printable pages in SVG or PDF, using only the standard library
+package render
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// Format is a printable document format
+type Format string
+
+// Supported document formats
+const (
+	// FormatSVG draws every page below the previous one in a single SVG image
+	FormatSVG Format = "svg"
+	// FormatPDF draws one PDF page per page
+	FormatPDF Format = "pdf"
+)
+
+var (
+	// ErrInvalidFormat is returned when a document format is not supported
+	ErrInvalidFormat = errors.New("Render format must be one of 'svg' or 'pdf'")
+
+	// ErrInvalidPerPage is returned when the number of puzzles per page has no layout
+	ErrInvalidPerPage = errors.New("Puzzles per page must be one of 1, 2, 4 or 6")
+
+	// ErrInvalidGrid is returned when the givens of a puzzle are not a square grid of a supported size
+	ErrInvalidGrid = errors.New("Puzzle to render must have a grid of 4, 6, 9, 12 or 16 rows")
+
+	// ErrNoPuzzles is returned when there is nothing to draw
+	ErrNoPuzzles = errors.New("At least one puzzle is needed to render")
+
+	// ErrMissingSolution is returned when solution pages are requested for a puzzle without a solution
+	ErrMissingSolution = errors.New("Every puzzle needs a solution to render the solution pages")
+)
+
+// Pages are A4 in points, with a margin on every side
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 36.0
+	titleSize  = 12.0
+)
+
+// Line widths and shades of gray, from 0 for black to 1 for white
+const (
+	thinLine     = 0.5
+	thickLine    = 2.0
+	cageLine     = 0.6
+	entryGray    = 0.35
+	noteGray     = 0.3
+	shadeGray    = 0.85
+	diagonalGray = 0.6
+)
+
+// perPageLayouts holds the columns and rows of puzzles on a page for each supported number of puzzles per page
+var perPageLayouts = map[int][2]int{1: {1, 1}, 2: {1, 2}, 4: {2, 2}, 6: {2, 3}}
+
+// Puzzle is a puzzle to draw
+// Givens are drawn bold and the other values of Entries in a lighter weight. Notes holds the pencil marks of each cell
+// by 0-based row and column, and Solution fills the solution pages. Entries, Notes and Solution may be nil.
+// The jigsaw regions, diagonals, killer cages and even/odd cells of Rules are drawn on the grid
+type Puzzle struct {
+	Title    string
+	Givens   sudoku.Grid
+	Entries  sudoku.Grid
+	Notes    [][][]int
+	Solution sudoku.Grid
+	Rules    sudoku.Rules
+}
+
+// Options changes how puzzles are laid out
+type Options struct {
+	// PerPage is the number of puzzles on each page, one of 1, 2, 4 or 6, or 0 for 1
+	PerPage int
+	// Notes draws the pencil marks of the empty cells
+	Notes bool
+	// Solutions adds pages with the solution of every puzzle after the puzzles
+	Solutions bool
+}
+
+// Check returns ErrInvalidPerPage if there is no layout for opts.PerPage puzzles on a page
+func (opts Options) Check() error {
+	if _, ok := perPageLayouts[opts.PerPage]; !ok && opts.PerPage != 0 {
+		return ErrInvalidPerPage
+	}
+
+	return nil
+}
+
+// canvas draws shapes and text on pages, with the origin at the top left corner of the page and y pointing down
+type canvas interface {
+	beginPage()
+	line(x1, y1, x2, y2, width, gray float64, dashed bool)
+	fill(x, y, w, h, gray float64)
+	circle(cx, cy, r, gray float64)
+	text(x, y, size float64, bold bool, gray float64, centered bool, s string)
+	finish(w io.Writer) error
+}
+
+// ParseFormat converts s, which may be a file extension, into a Format
+func ParseFormat(s string) (Format, error) {
+	format := Format(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "."))
+
+	switch format {
+	case FormatSVG, FormatPDF:
+		return format, nil
+	}
+
+	return "", ErrInvalidFormat
+}
+
+// ContentType returns the media type of documents in format
+func (format Format) ContentType() string {
+	if format == FormatPDF {
+		return "application/pdf"
+	}
+
+	return "image/svg+xml"
+}
+
+// Write draws puzzles to w as a document in format, with opts.PerPage puzzles on each page followed by the pages
+// of solutions if opts.Solutions is set
+func Write(w io.Writer, format Format, puzzles []Puzzle, opts Options) error {
+	if err := opts.Check(); err != nil {
+		return err
+	}
+
+	if opts.PerPage == 0 {
+		opts.PerPage = 1
+	}
+
+	layout := perPageLayouts[opts.PerPage]
+
+	if len(puzzles) == 0 {
+		return ErrNoPuzzles
+	}
+
+	var c canvas
+
+	switch format {
+	case FormatSVG:
+		c = &svgCanvas{}
+	case FormatPDF:
+		c = &pdfCanvas{}
+	default:
+		return ErrInvalidFormat
+	}
+
+	for _, p := range puzzles {
+		if !models.ValidGridSize(p.Givens.Size()) {
+			return ErrInvalidGrid
+		}
+
+		for _, row := range p.Givens {
+			if len(row) != p.Givens.Size() {
+				return ErrInvalidGrid
+			}
+		}
+
+		if opts.Solutions && p.Solution.Size() != p.Givens.Size() {
+			return ErrMissingSolution
+		}
+	}
+
+	drawPages(c, puzzles, layout, opts.Notes, false)
+
+	if opts.Solutions {
+		drawPages(c, puzzles, layout, false, true)
+	}
+
+	return c.finish(w)
+}
+
+// drawPages draws puzzles on as many pages as needed for the columns and rows of layout
+func drawPages(c canvas, puzzles []Puzzle, layout [2]int, notes bool, solutions bool) {
+	cols, rows := layout[0], layout[1]
+	slotWidth := (pageWidth - 2*margin) / float64(cols)
+	slotHeight := (pageHeight - 2*margin) / float64(rows)
+
+	// Puzzles sharing a page keep some space between them
+	gap := 0.0
+
+	if cols*rows > 1 {
+		gap = 18
+	}
+
+	side := math.Min(slotWidth, slotHeight-2*titleSize) - gap
+
+	for i, p := range puzzles {
+		slot := i % (cols * rows)
+
+		if slot == 0 {
+			c.beginPage()
+		}
+
+		x := margin + float64(slot%cols)*slotWidth + (slotWidth-side)/2
+		y := margin + float64(slot/cols)*slotHeight + titleSize
+
+		title := p.Title
+
+		if solutions {
+			title += " - solution"
+		}
+
+		c.text(x, y, titleSize, true, 0, false, title)
+		drawPuzzle(c, p, x, y+titleSize*0.75, side, notes, solutions)
+	}
+}
+
+// drawPuzzle draws the grid of p with its top left corner at x and y and sides of side
+func drawPuzzle(c canvas, p Puzzle, x, y, side float64, notes bool, solution bool) {
+	size := p.Givens.Size()
+	cell := side / float64(size)
+	regions := boxRegions(p.Givens)
+	var cages []sudoku.Cage
+
+	for _, constraint := range p.Rules {
+		switch rule := constraint.(type) {
+		case sudoku.Jigsaw:
+			if len(rule.Regions) == size {
+				regions = rule.Regions
+			}
+		case sudoku.Killer:
+			cages = append(cages, rule.Cages...)
+		case sudoku.EvenOdd:
+			for _, cl := range rule.Even {
+				c.fill(x+float64(cl.Col-1)*cell, y+float64(cl.Row-1)*cell, cell, cell, shadeGray)
+			}
+
+			for _, cl := range rule.Odd {
+				c.circle(x+(float64(cl.Col)-0.5)*cell, y+(float64(cl.Row)-0.5)*cell, cell*0.4, shadeGray)
+			}
+		case sudoku.Diagonal:
+			c.line(x, y, x+side, y+side, thinLine, diagonalGray, false)
+			c.line(x+side, y, x, y+side, thinLine, diagonalGray, false)
+		}
+	}
+
+	// Cells are divided by thin lines, and regions by thick lines
+	for i := 1; i < size; i++ {
+		c.line(x+float64(i)*cell, y, x+float64(i)*cell, y+side, thinLine, 0, false)
+		c.line(x, y+float64(i)*cell, x+side, y+float64(i)*cell, thinLine, 0, false)
+	}
+
+	for r := 0; r < size; r++ {
+		for col := 0; col < size; col++ {
+			cx, cy := x+float64(col)*cell, y+float64(r)*cell
+
+			if col+1 < size && regions[r][col] != regions[r][col+1] {
+				c.line(cx+cell, cy, cx+cell, cy+cell, thickLine, 0, false)
+			}
+
+			if r+1 < size && regions[r][col] != regions[r+1][col] {
+				c.line(cx, cy+cell, cx+cell, cy+cell, thickLine, 0, false)
+			}
+		}
+	}
+
+	c.line(x, y, x+side, y, thickLine, 0, false)
+	c.line(x, y+side, x+side, y+side, thickLine, 0, false)
+	c.line(x, y, x, y+side, thickLine, 0, false)
+	c.line(x+side, y, x+side, y+side, thickLine, 0, false)
+
+	for _, cage := range cages {
+		drawCage(c, cage, x, y, cell)
+	}
+
+	valueSize := cell * 0.6
+
+	for r := 0; r < size; r++ {
+		for col := 0; col < size; col++ {
+			cx, cy := x+(float64(col)+0.5)*cell, y+(float64(r)+0.5)*cell
+			given := p.Givens[r][col]
+
+			if given != 0 {
+				c.text(cx, cy+valueSize*0.35, valueSize, true, 0, true, string(models.ValueSymbol(given)))
+				continue
+			}
+
+			value := 0
+
+			switch {
+			case solution:
+				value = p.Solution[r][col]
+			case p.Entries.Size() == size:
+				value = p.Entries[r][col]
+			}
+
+			if value != 0 {
+				c.text(cx, cy+valueSize*0.35, valueSize, false, entryGray, true, string(models.ValueSymbol(value)))
+				continue
+			}
+
+			if notes && r < len(p.Notes) && col < len(p.Notes[r]) {
+				drawNotes(c, p.Notes[r][col], x+float64(col)*cell, y+float64(r)*cell, cell, size)
+			}
+		}
+	}
+}
+
+// drawCage draws a dashed outline just inside the cells of cage and writes its sum in the corner of its first cell
+func drawCage(c canvas, cage sudoku.Cage, x, y, cell float64) {
+	in := map[sudoku.Cell]bool{}
+
+	for _, cl := range cage.Cells {
+		in[cl] = true
+	}
+
+	inset := cell * 0.08
+
+	for _, cl := range cage.Cells {
+		left, top := x+float64(cl.Col-1)*cell, y+float64(cl.Row-1)*cell
+		right, bottom := left+cell, top+cell
+
+		// Sides shared with another cell of the cage are open, so the outline runs on into that cell
+		openLeft := in[sudoku.Cell{Row: cl.Row, Col: cl.Col - 1}]
+		openRight := in[sudoku.Cell{Row: cl.Row, Col: cl.Col + 1}]
+		openTop := in[sudoku.Cell{Row: cl.Row - 1, Col: cl.Col}]
+		openBottom := in[sudoku.Cell{Row: cl.Row + 1, Col: cl.Col}]
+
+		x1, x2, y1, y2 := left, right, top, bottom
+
+		if !openLeft {
+			x1 += inset
+		}
+
+		if !openRight {
+			x2 -= inset
+		}
+
+		if !openTop {
+			y1 += inset
+			c.line(x1, y1, x2, y1, cageLine, 0, true)
+		}
+
+		if !openBottom {
+			y2 -= inset
+			c.line(x1, y2, x2, y2, cageLine, 0, true)
+		}
+
+		if !openLeft {
+			c.line(x1, y1, x1, y2, cageLine, 0, true)
+		}
+
+		if !openRight {
+			c.line(x2, y1, x2, y2, cageLine, 0, true)
+		}
+	}
+
+	if len(cage.Cells) > 0 {
+		first := cage.Cells[0]
+
+		for _, cl := range cage.Cells {
+			if cl.Row < first.Row || (cl.Row == first.Row && cl.Col < first.Col) {
+				first = cl
+			}
+		}
+
+		sumSize := cell * 0.22
+		c.text(x+float64(first.Col-1)*cell+inset*1.5, y+float64(first.Row-1)*cell+inset+sumSize, sumSize, false, 0, false, strconv.Itoa(cage.Sum))
+	}
+}
+
+// drawNotes draws the pencil marks of a cell in a small grid of the box shape of size, each value in its own place
+func drawNotes(c canvas, values []int, x, y, cell float64, size int) {
+	rows, cols := models.BoxShape(size)
+	noteSize := cell / float64(cols) * 0.6
+
+	for _, v := range values {
+		if v < 1 || v > size {
+			continue
+		}
+
+		cx := x + (float64((v-1)%cols)+0.5)*cell/float64(cols)
+		cy := y + (float64((v-1)/cols)+0.5)*cell/float64(rows)
+		c.text(cx, cy+noteSize*0.35, noteSize, false, noteGray, true, string(models.ValueSymbol(v)))
+	}
+}
+
+// boxRegions numbers the boxes of g, so that boxes can be drawn like jigsaw regions
+func boxRegions(g sudoku.Grid) [][]int {
+	boxRows, boxCols := g.BoxShape()
+	regions := make([][]int, g.Size())
+
+	for r := range regions {
+		regions[r] = make([]int, g.Size())
+
+		for c := range regions[r] {
+			regions[r][c] = (r/boxRows)*(g.Size()/boxCols) + c/boxCols + 1
+		}
+	}
+
+	return regions
+}
@@ -0,0 +1,173 @@
+package render
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+const (
+	testGivens   = "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	testSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+// parseGrid parses a grid string for a test
+func parseGrid(t *testing.T, s string) sudoku.Grid {
+	g, err := sudoku.Parse(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+// ========== ParseFormat() ========== //
+func TestParseFormat(t *testing.T) {
+	for input, expected := range map[string]Format{"svg": FormatSVG, ".PDF": FormatPDF, " pdf ": FormatPDF} {
+		format, err := ParseFormat(input)
+
+		if err != nil || format != expected {
+			t.Errorf("Actual format for '%s': %s, %v, expected: %s", input, format, err, expected)
+		}
+	}
+
+	if _, err := ParseFormat("png"); err != ErrInvalidFormat {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrInvalidFormat)
+	}
+}
+
+// ========== Write() ========== //
+func TestWriteSVGIfSuccessful(t *testing.T) {
+	givens := parseGrid(t, testGivens)
+	entries := givens.Clone()
+	entries[0][2] = 4
+
+	notes := make([][][]int, 9)
+
+	for r := range notes {
+		notes[r] = make([][]int, 9)
+	}
+
+	notes[0][3] = []int{6}
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, FormatSVG, []Puzzle{{Title: "Club <night>", Givens: givens, Entries: entries, Notes: notes}}, Options{Notes: true})
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	svg := buf.String()
+
+	if !strings.HasPrefix(svg, "<?xml") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("Error: unexpected svg document: %s", svg)
+	}
+
+	if !strings.Contains(svg, "Club &lt;night&gt;</text>") {
+		t.Errorf("Error: title is not escaped")
+	}
+
+	if count := strings.Count(svg, `font-weight="bold" fill="#000000" text-anchor="middle">`); count != givens.Filled() {
+		t.Errorf("Actual bold values: %d, expected: %d", count, givens.Filled())
+	}
+
+	if !strings.Contains(svg, `font-weight="normal" fill="#595959" text-anchor="middle">4</text>`) {
+		t.Errorf("Error: entered value is not drawn in the lighter weight")
+	}
+
+	if !strings.Contains(svg, `fill="#4d4d4d" text-anchor="middle">6</text>`) {
+		t.Errorf("Error: pencil mark is not drawn")
+	}
+}
+
+func TestWriteSVGIfKiller(t *testing.T) {
+	rules := sudoku.Rules{sudoku.Killer{Cages: []sudoku.Cage{
+		{Cells: []sudoku.Cell{{Row: 1, Col: 3}, {Row: 2, Col: 3}}, Sum: 11},
+	}}}
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, FormatSVG, []Puzzle{{Title: "Killer", Givens: parseGrid(t, testGivens), Rules: rules}}, Options{})
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	// The outline of a vertical cage of two cells has a top, a bottom and two sides in each cell
+	if count := strings.Count(buf.String(), "stroke-dasharray"); count != 6 {
+		t.Errorf("Actual dashed lines: %d, expected: 6", count)
+	}
+
+	if !strings.Contains(buf.String(), ">11</text>") {
+		t.Errorf("Error: cage sum is not drawn")
+	}
+}
+
+func TestWritePDFIfSuccessful(t *testing.T) {
+	puzzles := []Puzzle{}
+
+	for i := 0; i < 3; i++ {
+		puzzles = append(puzzles, Puzzle{
+			Title:    "Puzzle (" + strconv.Itoa(i+1) + ")",
+			Givens:   parseGrid(t, testGivens),
+			Solution: parseGrid(t, testSolution),
+		})
+	}
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, FormatPDF, puzzles, Options{PerPage: 2, Solutions: true})
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	pdf := buf.String()
+
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatalf("Error: unexpected pdf document")
+	}
+
+	// Three puzzles at two per page take two pages, and so do their solutions
+	if !strings.Contains(pdf, "/Count 4") {
+		t.Errorf("Error: expected 4 pages")
+	}
+
+	if !strings.Contains(pdf, `(Puzzle \(3\) - solution) Tj`) {
+		t.Errorf("Error: solution title is not escaped")
+	}
+
+	// Every object in the cross-reference table starts at its offset
+	offsets := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(pdf, -1)
+
+	if len(offsets) != 12 {
+		t.Fatalf("Actual objects: %d, expected: 12", len(offsets))
+	}
+
+	for i, offset := range offsets {
+		n, _ := strconv.Atoi(offset[1])
+
+		if !strings.HasPrefix(pdf[n:], strconv.Itoa(i+1)+" 0 obj") {
+			t.Errorf("Error: object %d is not at offset %d", i+1, n)
+		}
+	}
+}
+
+func TestWriteIfInvalidPerPage(t *testing.T) {
+	err := Write(&bytes.Buffer{}, FormatPDF, []Puzzle{{Givens: parseGrid(t, testGivens)}}, Options{PerPage: 3})
+
+	if err != ErrInvalidPerPage {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrInvalidPerPage)
+	}
+}
+
+func TestWriteIfMissingSolution(t *testing.T) {
+	err := Write(&bytes.Buffer{}, FormatSVG, []Puzzle{{Givens: parseGrid(t, testGivens)}}, Options{Solutions: true})
+
+	if err != ErrMissingSolution {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrMissingSolution)
+	}
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// svgCanvas draws pages into a single SVG image, each page below the previous one
+type svgCanvas struct {
+	body  bytes.Buffer
+	pages int
+}
+
+func (c *svgCanvas) beginPage() {
+	if c.pages > 0 {
+		c.body.WriteString("</g>\n")
+	}
+
+	fmt.Fprintf(&c.body, "<g transform=\"translate(0 %.2f)\">\n", float64(c.pages)*pageHeight)
+	fmt.Fprintf(&c.body, "<rect x=\"0\" y=\"0\" width=\"%.2f\" height=\"%.2f\" fill=\"#ffffff\"/>\n", pageWidth, pageHeight)
+	c.pages++
+}
+
+func (c *svgCanvas) line(x1, y1, x2, y2, width, gray float64, dashed bool) {
+	dash := ""
+
+	if dashed {
+		dash = fmt.Sprintf(" stroke-dasharray=\"%.2f %.2f\"", width*4, width*3)
+	}
+
+	fmt.Fprintf(&c.body, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"%s\" stroke-width=\"%.2f\" stroke-linecap=\"square\"%s/>\n",
+		x1, y1, x2, y2, svgColor(gray), width, dash)
+}
+
+func (c *svgCanvas) fill(x, y, w, h, gray float64) {
+	fmt.Fprintf(&c.body, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"%s\"/>\n", x, y, w, h, svgColor(gray))
+}
+
+func (c *svgCanvas) circle(cx, cy, r, gray float64) {
+	fmt.Fprintf(&c.body, "<circle cx=\"%.2f\" cy=\"%.2f\" r=\"%.2f\" fill=\"%s\"/>\n", cx, cy, r, svgColor(gray))
+}
+
+func (c *svgCanvas) text(x, y, size float64, bold bool, gray float64, centered bool, s string) {
+	weight, anchor := "normal", "start"
+
+	if bold {
+		weight = "bold"
+	}
+
+	if centered {
+		anchor = "middle"
+	}
+
+	fmt.Fprintf(&c.body, "<text x=\"%.2f\" y=\"%.2f\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"%.2f\" font-weight=\"%s\" fill=\"%s\" text-anchor=\"%s\">",
+		x, y, size, weight, svgColor(gray), anchor)
+	xml.EscapeText(&c.body, []byte(s))
+	c.body.WriteString("</text>\n")
+}
+
+func (c *svgCanvas) finish(w io.Writer) error {
+	height := float64(c.pages) * pageHeight
+
+	_, err := fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0fpt\" height=\"%.0fpt\" viewBox=\"0 0 %.0f %.0f\">\n",
+		pageWidth, height, pageWidth, height)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.body.WriteTo(w); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "</g>\n</svg>\n")
+	return err
+}
+
+// svgColor converts a shade of gray into an SVG color
+func svgColor(gray float64) string {
+	v := int(gray*255 + 0.5)
+	return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// Thumbnails are square, with sides from MinThumbnailSize to MaxThumbnailSize pixels
+const (
+	MinThumbnailSize     = 36
+	MaxThumbnailSize     = 720
+	DefaultThumbnailSize = 180
+)
+
+// ErrInvalidThumbnailSize is returned when the side of a thumbnail is out of range
+var ErrInvalidThumbnailSize = errors.New("Thumbnail size must be from 36 to 720 pixels")
+
+// Shades of the thumbnail, from 0 for black to 255 for white
+var (
+	thumbnailBackground = color.Gray{Y: 255}
+	thumbnailThinLine   = color.Gray{Y: 190}
+	thumbnailThickLine  = color.Gray{Y: 0}
+	thumbnailGiven      = color.Gray{Y: 0}
+	thumbnailEntry      = color.Gray{Y: 110}
+)
+
+// glyphs holds a 3 by 5 pixel glyph of every value symbol, one row of three bits per byte from the top
+// Thumbnails are too small for outline fonts, and the standard library has none
+var glyphs = map[byte][5]byte{
+	'1': {0x2, 0x6, 0x2, 0x2, 0x7}, '2': {0x7, 0x1, 0x7, 0x4, 0x7}, '3': {0x7, 0x1, 0x7, 0x1, 0x7},
+	'4': {0x5, 0x5, 0x7, 0x1, 0x1}, '5': {0x7, 0x4, 0x7, 0x1, 0x7}, '6': {0x7, 0x4, 0x7, 0x5, 0x7},
+	'7': {0x7, 0x1, 0x1, 0x1, 0x1}, '8': {0x7, 0x5, 0x7, 0x5, 0x7}, '9': {0x7, 0x5, 0x7, 0x1, 0x7},
+	'A': {0x2, 0x5, 0x7, 0x5, 0x5}, 'B': {0x6, 0x5, 0x6, 0x5, 0x6}, 'C': {0x3, 0x4, 0x4, 0x4, 0x3},
+	'D': {0x6, 0x5, 0x5, 0x5, 0x6}, 'E': {0x7, 0x4, 0x7, 0x4, 0x7}, 'F': {0x7, 0x4, 0x7, 0x4, 0x4},
+	'G': {0x3, 0x4, 0x5, 0x5, 0x3},
+}
+
+// WriteThumbnail draws the current values of p as a square grayscale PNG image with sides of side pixels
+// Givens are black and entered values gray. Cells too small for a legible glyph get a block of the same shade instead,
+// so that the preview still shows how far the puzzle has been filled. Only the regions of the rules of p are drawn
+func WriteThumbnail(w io.Writer, p Puzzle, side int) error {
+	if side < MinThumbnailSize || side > MaxThumbnailSize {
+		return ErrInvalidThumbnailSize
+	}
+
+	size := p.Givens.Size()
+
+	if !models.ValidGridSize(size) {
+		return ErrInvalidGrid
+	}
+
+	regions := boxRegions(p.Givens)
+
+	for _, constraint := range p.Rules {
+		if jigsaw, ok := constraint.(sudoku.Jigsaw); ok && len(jigsaw.Regions) == size {
+			regions = jigsaw.Regions
+		}
+	}
+
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Bounds(), image.NewUniform(thumbnailBackground), image.Point{}, draw.Src)
+
+	// edge returns the pixel at which cell i starts
+	edge := func(i int) int {
+		return i * side / size
+	}
+
+	thick := 1
+
+	if side >= 150 {
+		thick = 2
+	}
+
+	for r := 0; r < size; r++ {
+		for c := 0; c < size; c++ {
+			cell := image.Rect(edge(c), edge(r), edge(c+1), edge(r+1))
+			value, shade := p.Givens[r][c], thumbnailGiven
+
+			if value == 0 && p.Entries.Size() == size {
+				value, shade = p.Entries[r][c], thumbnailEntry
+			}
+
+			if value != 0 {
+				drawValue(img, cell, value, shade)
+			}
+		}
+	}
+
+	for i := 1; i < size; i++ {
+		fillRect(img, image.Rect(edge(i), 0, edge(i)+1, side), thumbnailThinLine)
+		fillRect(img, image.Rect(0, edge(i), side, edge(i)+1), thumbnailThinLine)
+	}
+
+	for r := 0; r < size; r++ {
+		for c := 0; c < size; c++ {
+			if c+1 < size && regions[r][c] != regions[r][c+1] {
+				fillRect(img, image.Rect(edge(c+1)-thick/2, edge(r), edge(c+1)-thick/2+thick, edge(r+1)+1), thumbnailThickLine)
+			}
+
+			if r+1 < size && regions[r][c] != regions[r+1][c] {
+				fillRect(img, image.Rect(edge(c), edge(r+1)-thick/2, edge(c+1)+1, edge(r+1)-thick/2+thick), thumbnailThickLine)
+			}
+		}
+	}
+
+	fillRect(img, image.Rect(0, 0, side, thick), thumbnailThickLine)
+	fillRect(img, image.Rect(0, side-thick, side, side), thumbnailThickLine)
+	fillRect(img, image.Rect(0, 0, thick, side), thumbnailThickLine)
+	fillRect(img, image.Rect(side-thick, 0, side, side), thumbnailThickLine)
+
+	return png.Encode(w, img)
+}
+
+// drawValue draws the glyph of value in the middle of cell, scaled to about 60% of its height
+func drawValue(img *image.Gray, cell image.Rectangle, value int, shade color.Gray) {
+	scale := cell.Dy() * 3 / 5 / 5
+	center := image.Pt((cell.Min.X+cell.Max.X)/2, (cell.Min.Y+cell.Max.Y)/2)
+
+	if scale < 1 {
+		fillRect(img, image.Rect(center.X-1, center.Y-1, center.X+1, center.Y+1), shade)
+		return
+	}
+
+	glyph := glyphs[models.ValueSymbol(value)]
+	origin := image.Pt(center.X-3*scale/2, center.Y-5*scale/2)
+
+	for y, bits := range glyph {
+		for x := 0; x < 3; x++ {
+			if bits&(0x4>>uint(x)) != 0 {
+				min := origin.Add(image.Pt(x*scale, y*scale))
+				fillRect(img, image.Rectangle{Min: min, Max: min.Add(image.Pt(scale, scale))}, shade)
+			}
+		}
+	}
+}
+
+// fillRect fills the part of r inside img with shade
+func fillRect(img *image.Gray, r image.Rectangle, shade color.Gray) {
+	draw.Draw(img, r.Intersect(img.Bounds()), image.NewUniform(shade), image.Point{}, draw.Src)
+}
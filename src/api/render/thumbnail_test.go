@@ -0,0 +1,90 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/sudoku"
+)
+
+// ========== WriteThumbnail() ========== //
+func TestWriteThumbnailIfSuccessful(t *testing.T) {
+	givens := parseGrid(t, testGivens)
+	entries := givens.Clone()
+	entries[0][2] = 4
+
+	buf := &bytes.Buffer{}
+	err := WriteThumbnail(buf, Puzzle{Givens: givens, Entries: entries}, 180)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	img, err := png.Decode(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bounds := img.Bounds(); bounds != image.Rect(0, 0, 180, 180) {
+		t.Fatalf("Actual bounds: %v, expected: 180 by 180", bounds)
+	}
+
+	// Cells are 20 pixels wide, and the top bar of a glyph at scale 2 starts 5 pixels from the top of the cell.
+	// The 5 of (1, 1) is a given and the 4 of (1, 3) is an entry
+	shade := func(x, y int) uint32 {
+		gray, _, _, _ := img.At(x, y).RGBA()
+		return gray >> 8
+	}
+
+	if y := shade(10, 5); y != 0 {
+		t.Errorf("Actual shade of given: %d, expected: 0", y)
+	}
+
+	if y := shade(47, 6); y != 110 {
+		t.Errorf("Actual shade of entry: %d, expected: 110", y)
+	}
+
+	if y := shade(70, 10); y != 255 {
+		t.Errorf("Actual shade of empty cell: %d, expected: 255", y)
+	}
+}
+
+func TestWriteThumbnailIfJigsaw(t *testing.T) {
+	givens := sudoku.NewGrid(4)
+
+	// Two regions of a row each on top, and the bottom half split down the middle like boxes
+	regions := [][]int{{1, 1, 1, 1}, {2, 2, 2, 2}, {3, 3, 4, 4}, {3, 3, 4, 4}}
+
+	buf := &bytes.Buffer{}
+	err := WriteThumbnail(buf, Puzzle{Givens: givens, Rules: sudoku.Rules{sudoku.Jigsaw{Regions: regions}}}, 40)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	img, err := png.Decode(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gray, _, _, _ := img.At(20, 5).RGBA()
+
+	// The middle of the top row has no region border, unlike the boxes of a classic 4 by 4 grid
+	if gray>>8 != 190 {
+		t.Errorf("Actual shade between cells of a region: %d, expected: 190", gray>>8)
+	}
+}
+
+func TestWriteThumbnailIfInvalidSize(t *testing.T) {
+	for _, side := range []int{MinThumbnailSize - 1, MaxThumbnailSize + 1} {
+		err := WriteThumbnail(&bytes.Buffer{}, Puzzle{Givens: parseGrid(t, testGivens)}, side)
+
+		if err != ErrInvalidThumbnailSize {
+			t.Errorf("Actual error for %d pixels: %v, expected: %s", side, err, ErrInvalidThumbnailSize)
+		}
+	}
+}
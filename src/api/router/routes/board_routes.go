@@ -14,6 +14,30 @@ var BoardRoutes = []Route{
 		Handler:      controllers.GetBoards,
 		AuthRequired: true,
 	},
+	Route{
+		URI:          "/boards/notes",
+		Method:       http.MethodPut,
+		Handler:      controllers.SetBoardNotes,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/boards/notes/toggle",
+		Method:       http.MethodPut,
+		Handler:      controllers.ToggleBoardNote,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/boards/notes/fill",
+		Method:       http.MethodPost,
+		Handler:      controllers.FillBoardNotes,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/boards/notes",
+		Method:       http.MethodDelete,
+		Handler:      controllers.ClearBoardNotes,
+		AuthRequired: true,
+	},
 	Route{
 		URI:          "/boards/{id}",
 		Method:       http.MethodGet,
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/controllers"
+)
+
+// CageRoutes is an array of Route instances which map paths to route handlers
+var CageRoutes = []Route{
+	Route{
+		URI:          "/cages",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetCages,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/cages",
+		Method:       http.MethodPost,
+		Handler:      controllers.CreateCage,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/cages/{id}",
+		Method:       http.MethodPut,
+		Handler:      controllers.UpdateCage,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/cages/{id}",
+		Method:       http.MethodDelete,
+		Handler:      controllers.DeleteCage,
+		AuthRequired: true,
+	},
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/controllers"
+)
+
+// DailyRoutes is an array of Route instances which map paths to route handlers
+var DailyRoutes = []Route{
+	Route{
+		URI:          "/daily",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetDailyPuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/daily",
+		Method:       http.MethodPost,
+		Handler:      controllers.StartDailyPuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/daily/{date}",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetDailyPuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/daily/{date}",
+		Method:       http.MethodPost,
+		Handler:      controllers.StartDailyPuzzle,
+		AuthRequired: true,
+	},
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/controllers"
+)
+
+// LeaderboardRoutes is an array of Route instances which map paths to route handlers
+var LeaderboardRoutes = []Route{
+	Route{
+		URI:          "/leaderboards/global",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetGlobalLeaderboard,
+		AuthRequired: false,
+	},
+	Route{
+		URI:          "/leaderboards/daily/{date}",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetDailyLeaderboard,
+		AuthRequired: false,
+	},
+	Route{
+		URI:          "/leaderboards/puzzles/{id}",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetPuzzleLeaderboard,
+		AuthRequired: false,
+	},
+}
@@ -14,6 +14,12 @@ var PuzzleRoutes = []Route{
 		Handler:      controllers.GetPuzzles,
 		AuthRequired: true,
 	},
+	Route{
+		URI:          "/puzzles/booklet",
+		Method:       http.MethodGet,
+		Handler:      controllers.RenderBooklet,
+		AuthRequired: true,
+	},
 	Route{
 		URI:          "/puzzles/{id}",
 		Method:       http.MethodGet,
@@ -26,6 +32,30 @@ var PuzzleRoutes = []Route{
 		Handler:      controllers.CreatePuzzle,
 		AuthRequired: true,
 	},
+	Route{
+		URI:          "/puzzles/generate",
+		Method:       http.MethodPost,
+		Handler:      controllers.GeneratePuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/import",
+		Method:       http.MethodPost,
+		Handler:      controllers.ImportPuzzles,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/clone/{code}",
+		Method:       http.MethodPost,
+		Handler:      controllers.ClonePuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/validate",
+		Method:       http.MethodPost,
+		Handler:      controllers.ValidatePuzzle,
+		AuthRequired: true,
+	},
 	Route{
 		URI:          "/puzzles/{id}",
 		Method:       http.MethodPut,
@@ -38,4 +68,88 @@ var PuzzleRoutes = []Route{
 		Handler:      controllers.DeletePuzzle,
 		AuthRequired: true,
 	},
+	Route{
+		URI:          "/puzzles/{id}/solve",
+		Method:       http.MethodPost,
+		Handler:      controllers.SolvePuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/hint",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetPuzzleHint,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/grid",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetPuzzleGrid,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/grid",
+		Method:       http.MethodPut,
+		Handler:      controllers.UpdatePuzzleGrid,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/export",
+		Method:       http.MethodGet,
+		Handler:      controllers.ExportPuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/render",
+		Method:       http.MethodGet,
+		Handler:      controllers.RenderPuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/thumbnail.png",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetPuzzleThumbnail,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/share",
+		Method:       http.MethodPost,
+		Handler:      controllers.SharePuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/share",
+		Method:       http.MethodDelete,
+		Handler:      controllers.RevokePuzzleShare,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/undo",
+		Method:       http.MethodPost,
+		Handler:      controllers.UndoMove,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/redo",
+		Method:       http.MethodPost,
+		Handler:      controllers.RedoMove,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/replay",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetReplay,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/coop",
+		Method:       http.MethodGet,
+		Handler:      controllers.CoopPuzzle,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/events",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetPuzzleEvents,
+		AuthRequired: true,
+	},
 }
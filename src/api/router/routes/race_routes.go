@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/controllers"
+)
+
+// RaceRoutes is an array of Route instances which map paths to route handlers
+var RaceRoutes = []Route{
+	Route{
+		URI:          "/races",
+		Method:       http.MethodPost,
+		Handler:      controllers.CreateRace,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/races/{id}",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetRace,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/races/{id}/join",
+		Method:       http.MethodPost,
+		Handler:      controllers.JoinRace,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/races/{id}/start",
+		Method:       http.MethodPost,
+		Handler:      controllers.StartRace,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/races/{id}/events",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetRaceEvents,
+		AuthRequired: true,
+	},
+}
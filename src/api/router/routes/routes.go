@@ -26,6 +26,11 @@ func Load() []Route {
 	routes = append(routes, PuzzleRoutes...)
 	routes = append(routes, LoginRoutes...)
 	routes = append(routes, BoardRoutes...)
+	routes = append(routes, CageRoutes...)
+	routes = append(routes, SessionRoutes...)
+	routes = append(routes, LeaderboardRoutes...)
+	routes = append(routes, DailyRoutes...)
+	routes = append(routes, RaceRoutes...)
 	return routes
 }
 
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/controllers"
+)
+
+// SessionRoutes is an array of Route instances which map paths to route handlers
+var SessionRoutes = []Route{
+	Route{
+		URI:          "/puzzles/{id}/sessions",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetSessions,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/puzzles/{id}/sessions",
+		Method:       http.MethodPost,
+		Handler:      controllers.StartSession,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/sessions/{id}",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetSession,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/sessions/{id}/pause",
+		Method:       http.MethodPut,
+		Handler:      controllers.PauseSession,
+		AuthRequired: true,
+	},
+	Route{
+		URI:          "/sessions/{id}/resume",
+		Method:       http.MethodPut,
+		Handler:      controllers.ResumeSession,
+		AuthRequired: true,
+	},
+}
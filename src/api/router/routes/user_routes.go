@@ -38,4 +38,10 @@ var UserRoutes = []Route{
 		Handler:      controllers.DeleteUser,
 		AuthRequired: true,
 	},
+	Route{
+		URI:          "/users/{id}/stats",
+		Method:       http.MethodGet,
+		Handler:      controllers.GetUserStats,
+		AuthRequired: true,
+	},
 }
@@ -0,0 +1,20 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// shareCodeBytes is the number of random bytes in a share code, which encode to 24 URL safe characters
+const shareCodeBytes = 18
+
+// NewShareCode returns an unguessable code from a cryptographically secure source, that is safe to use in URLs
+func NewShareCode() (string, error) {
+	b := make([]byte, shareCodeBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
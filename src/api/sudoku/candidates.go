@@ -0,0 +1,31 @@
+package sudoku
+
+// Candidates returns the values that may still be placed in each cell of g under the classic rules, indexed by
+// 0-based row and column
+// A value is a candidate if no peer of the cell holds it. Filled cells have no candidates
+// Returns ErrContradictory if two givens share a unit
+func Candidates(g Grid) ([][][]int, error) {
+	return Classic.Candidates(g)
+}
+
+// Candidates returns the values that may still be placed in each cell of g under rules
+// Variant rules can also limit the candidates of a cell, e.g. to even values or to the values that complete a cage
+func (rules Rules) Candidates(g Grid) ([][][]int, error) {
+	st, err := newState(g, rules)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cands := make([][][]int, st.size)
+
+	for r := range cands {
+		cands[r] = make([][]int, st.size)
+
+		for c := range cands[r] {
+			cands[r][c] = digits(st.cands[r*st.size+c])
+		}
+	}
+
+	return cands, nil
+}
@@ -0,0 +1,32 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ========== CANDIDATES() ========== //
+func TestCandidates(t *testing.T) {
+	cands, err := Candidates(gridFromString(testPuzzle))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// (1, 3) sees 5, 3 and 7 in its row, 8 in its column and 6, 9 and 8 in its box
+	if expected := []int{1, 2, 4}; !reflect.DeepEqual(cands[0][2], expected) {
+		t.Errorf("Actual candidates: %v, expected: %v", cands[0][2], expected)
+	}
+
+	if len(cands[0][0]) != 0 {
+		t.Errorf("Actual candidates: %v, expected none for a filled cell", cands[0][0])
+	}
+}
+
+func TestCandidatesIfContradictory(t *testing.T) {
+	_, err := Candidates(gridFromString("55" + testPuzzle[2:]))
+
+	if err != ErrContradictory {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrContradictory)
+	}
+}
@@ -0,0 +1,122 @@
+package sudoku
+
+// Names of the units reported in conflicts
+var unitNames = map[unitKind]string{
+	rowUnit:      "row",
+	colUnit:      "column",
+	boxUnit:      "box",
+	regionUnit:   "region",
+	diagonalUnit: "diagonal",
+	cageUnit:     "cage",
+	knightUnit:   "knight",
+	kingUnit:     "king",
+}
+
+// parityUnit is the unit reported when a cell of an even/odd puzzle holds a value of the wrong parity
+const parityUnit = "parity"
+
+// Conflict is a cell that holds the same value as the checked cell in one of their shared units
+type Conflict struct {
+	Row   int    `json:"board_row"`
+	Col   int    `json:"board_col"`
+	Value int    `json:"value"`
+	Unit  string `json:"unit"`
+}
+
+// Conflicts returns the cells that clash with the value at the 1-based row and col of g under the classic rules
+// A cell that shares several units with the checked cell is reported once per unit
+func Conflicts(g Grid, row, col int) []Conflict {
+	return Classic.Conflicts(g, row, col)
+}
+
+// Conflicts returns the cells that clash with the value at the 1-based row and col of g under rules
+// The checked cell itself is reported if its value breaks a rule of the cell, such as an odd value in an even cell
+func (rules Rules) Conflicts(g Grid, row, col int) []Conflict {
+	conflicts := []Conflict{}
+
+	if g.check() != nil || row < 1 || row > g.Size() || col < 1 || col > g.Size() {
+		return conflicts
+	}
+
+	value := g[row-1][col-1]
+
+	if value == 0 {
+		return conflicts
+	}
+
+	l, err := newLayout(g, rules)
+
+	if err != nil {
+		return conflicts
+	}
+
+	cell := (row-1)*l.size + col - 1
+
+	if l.allowed[cell]&(1<<uint(value)) == 0 {
+		conflicts = append(conflicts, Conflict{Row: row, Col: col, Value: value, Unit: parityUnit})
+	}
+
+	for _, u := range l.cellUnits[cell] {
+		for _, other := range l.units[u] {
+			if other != cell && g[other/l.size][other%l.size] == value {
+				conflicts = append(conflicts, Conflict{
+					Row:   other/l.size + 1,
+					Col:   other%l.size + 1,
+					Value: value,
+					Unit:  unitNames[l.kinds[u]],
+				})
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// CageError is a completed killer cage whose values do not add up to its sum or repeat a value
+// Total is the sum of the values in the cage and Repeated is true if a value appears more than once
+type CageError struct {
+	Cage
+	Total    int  `json:"total"`
+	Repeated bool `json:"repeated"`
+}
+
+// CageErrors returns the errors of the completed killer cages of rules that hold the cell at the 1-based row and col
+// of g. Cages with an empty cell are not checked, since they may still be completed correctly
+func (rules Rules) CageErrors(g Grid, row, col int) []CageError {
+	errs := []CageError{}
+
+	if g.check() != nil || row < 1 || row > g.Size() || col < 1 || col > g.Size() {
+		return errs
+	}
+
+	l, err := newLayout(g, rules)
+
+	if err != nil {
+		return errs
+	}
+
+	for _, u := range l.cellUnits[(row-1)*l.size+col-1] {
+		if l.kinds[u] != cageUnit {
+			continue
+		}
+
+		cageErr := CageError{Cage: Cage{Cells: []Cell{}, Sum: l.sums[u]}}
+		seen := map[int]bool{}
+		complete := true
+
+		for _, cell := range l.units[u] {
+			value := g[cell/l.size][cell%l.size]
+			complete = complete && value != 0
+			cageErr.Repeated = cageErr.Repeated || seen[value]
+			cageErr.Total += value
+			seen[value] = true
+			cageErr.Cells = append(cageErr.Cells, Cell{Row: cell/l.size + 1, Col: cell%l.size + 1})
+		}
+
+		if complete && (cageErr.Repeated || cageErr.Total != cageErr.Sum) {
+			errs = append(errs, cageErr)
+		}
+	}
+
+	return errs
+}
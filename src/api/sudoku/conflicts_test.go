@@ -0,0 +1,45 @@
+package sudoku
+
+import "testing"
+
+// ========== CONFLICTS() ========== //
+func TestConflictsIfNone(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[0][2] = 4
+
+	conflicts := Conflicts(g, 1, 3)
+
+	if len(conflicts) != 0 {
+		t.Errorf("Actual conflicts: %+v, expected none", conflicts)
+	}
+}
+
+func TestConflictsIfRowColumnAndBox(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[0][2] = 3 // (1, 2) already holds 3 and shares both the row and the box
+
+	conflicts := Conflicts(g, 1, 3)
+
+	expected := map[Conflict]bool{
+		{Row: 1, Col: 2, Value: 3, Unit: "row"}: true,
+		{Row: 1, Col: 2, Value: 3, Unit: "box"}: true,
+	}
+
+	if len(conflicts) != len(expected) {
+		t.Fatalf("Actual conflicts: %+v, expected %d", conflicts, len(expected))
+	}
+
+	for _, c := range conflicts {
+		if !expected[c] {
+			t.Errorf("Unexpected conflict: %+v", c)
+		}
+	}
+}
+
+func TestConflictsIfEmptyCell(t *testing.T) {
+	conflicts := Conflicts(gridFromString(testPuzzle), 1, 3)
+
+	if len(conflicts) != 0 {
+		t.Errorf("Actual conflicts: %+v, expected none", conflicts)
+	}
+}
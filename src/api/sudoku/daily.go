@@ -0,0 +1,23 @@
+package sudoku
+
+import "time"
+
+// DailyDateFormat is the layout of the calendar dates that identify daily puzzles
+const DailyDateFormat = "2006-01-02"
+
+// dailyDifficulty is the difficulty of the daily puzzle for each day of the week, getting harder towards the weekend
+var dailyDifficulty = map[time.Weekday]Difficulty{
+	time.Monday:    Easy,
+	time.Tuesday:   Easy,
+	time.Wednesday: Medium,
+	time.Thursday:  Medium,
+	time.Friday:    Hard,
+	time.Saturday:  Hard,
+	time.Sunday:    Expert,
+}
+
+// Daily returns the difficulty and seed of the daily puzzle for the calendar date of date
+// The seed is the date written as yyyymmdd, so every server generates the same puzzle for a date
+func Daily(date time.Time) (Difficulty, int64) {
+	return dailyDifficulty[date.Weekday()], int64(date.Year()*10000 + int(date.Month())*100 + date.Day())
+}
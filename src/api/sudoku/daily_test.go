@@ -0,0 +1,26 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+)
+
+// ========== DAILY() ========== //
+func TestDailyIfSuccessful(t *testing.T) {
+	// 5 January 2020 is a Sunday
+	date := time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	difficulty, seed := Daily(date)
+
+	if difficulty != Expert {
+		t.Errorf("Actual difficulty: %s, expected: %s", difficulty, Expert)
+	}
+
+	if seed != 20200105 {
+		t.Errorf("Actual seed: %d, expected: 20200105", seed)
+	}
+
+	if next, _ := Daily(date.AddDate(0, 0, 1)); next != Easy {
+		t.Errorf("Actual difficulty on Monday: %s, expected: %s", next, Easy)
+	}
+}
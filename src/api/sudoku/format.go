@@ -0,0 +1,307 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+)
+
+var (
+	// ErrInvalidLength is returned when a grid string does not hold exactly one character per cell of a grid of a
+	// supported size
+	ErrInvalidLength = errors.New("Grid string must have 16, 36, 81, 144 or 256 characters")
+
+	// ErrInvalidCharacter is returned when a grid string holds a character other than a value, '0' or '.'
+	ErrInvalidCharacter = errors.New("Grid string may only hold the digits 0 to 9, the letters A to G and '.'")
+
+	// ErrInvalidValue is returned when a grid string holds a value that is larger than the size of the grid
+	ErrInvalidValue = errors.New("Grid string holds a value larger than the size of the grid")
+)
+
+// Parse reads a grid from a string listing the cells row by row, with one character per cell
+// The length of the string sets the size of the grid, e.g. 81 characters for a 9x9 grid. Values above 9 are written
+// as the letters A to G in either case, and empty cells may be written as '.' or '0'. Surrounding whitespace is ignored
+func Parse(s string) (Grid, error) {
+	s = strings.TrimSpace(s)
+	size := 0
+
+	for _, n := range models.GridSizes {
+		if len(s) == n*n {
+			size = n
+		}
+	}
+
+	if size == 0 {
+		return nil, ErrInvalidLength
+	}
+
+	g := NewGrid(size)
+
+	for i, ch := range s {
+		if ch == '.' || ch == '0' {
+			continue
+		}
+
+		value := models.SymbolValue(ch)
+
+		switch {
+		case value == 0:
+			return nil, ErrInvalidCharacter
+		case value > size:
+			return nil, ErrInvalidValue
+		}
+
+		g[i/size][i%size] = value
+	}
+
+	return g, nil
+}
+
+// String writes the grid row by row as a single line, with empty cells written as '.' and values above 9
+// written as letters
+func (g Grid) String() string {
+	var b strings.Builder
+
+	for i := range g {
+		for _, d := range g[i] {
+			if d == 0 {
+				b.WriteByte('.')
+				continue
+			}
+
+			b.WriteByte(models.ValueSymbol(d))
+		}
+	}
+
+	return b.String()
+}
+
+// Format is a text format for exchanging puzzles
+type Format string
+
+// Supported puzzle formats
+const (
+	// FormatLine holds one puzzle per line as a grid string, optionally followed by whitespace and a name
+	FormatLine Format = "txt"
+	// FormatSDK is the SadMan Sudoku format of one line per row, preceded by optional '#' metadata lines
+	FormatSDK Format = "sdk"
+	// FormatSS is the SimpleSudoku format of one line per row with '|' between boxes and '-' lines between bands
+	FormatSS Format = "ss"
+)
+
+var (
+	// ErrInvalidFormat is returned when a puzzle format is not supported
+	ErrInvalidFormat = errors.New("Puzzle format must be one of 'txt', 'sdk' or 'ss'")
+
+	// ErrNoPuzzles is returned when a file does not hold any puzzle
+	ErrNoPuzzles = errors.New("File does not hold any puzzle")
+)
+
+// Entry is a puzzle read from a file, with the name given to it in the file if any
+type Entry struct {
+	Name string
+	Grid Grid
+}
+
+// ParseFormat converts s, which may be a file extension, into a Format
+func ParseFormat(s string) (Format, error) {
+	format := Format(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "."))
+
+	switch format {
+	case FormatLine, FormatSDK, FormatSS:
+		return format, nil
+	}
+
+	return "", ErrInvalidFormat
+}
+
+// DetectFormat guesses the format of data from its layout
+// Data is in the SadMan format if it has as many lines as the first one has characters, and the line format otherwise
+func DetectFormat(data string) Format {
+	if strings.Contains(data, "|") {
+		return FormatSS
+	}
+
+	lines := []string{}
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 || !models.ValidGridSize(len(lines[0])) || len(lines) != len(lines[0]) {
+		return FormatLine
+	}
+
+	for _, line := range lines {
+		if len(line) != len(lines[0]) {
+			return FormatLine
+		}
+	}
+
+	return FormatSDK
+}
+
+// Decode reads the puzzles held in data in the given format
+// Returns an error naming the line of the first puzzle that cannot be read
+func Decode(data string, format Format) ([]Entry, error) {
+	var entries []Entry
+	var err error
+
+	switch format {
+	case FormatLine:
+		entries, err = decodeLines(data)
+	case FormatSDK, FormatSS:
+		entries, err = decodeRows(data, format)
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrNoPuzzles
+	}
+
+	return entries, nil
+}
+
+// Encode writes g in the given format, with name as its title if the format supports one
+func Encode(g Grid, name string, format Format) (string, error) {
+	var b strings.Builder
+
+	switch format {
+	case FormatLine:
+		b.WriteString(g.String())
+
+		if name != "" {
+			b.WriteString(" " + name)
+		}
+
+		b.WriteString("\n")
+	case FormatSDK:
+		if name != "" {
+			b.WriteString("#D " + name + "\n")
+		}
+
+		s := g.String()
+		size := g.Size()
+
+		for r := 0; r < size; r++ {
+			b.WriteString(s[r*size:(r+1)*size] + "\n")
+		}
+	case FormatSS:
+		s := g.String()
+		size := g.Size()
+		boxRows, boxCols := g.BoxShape()
+
+		for r := 0; r < size; r++ {
+			if r > 0 && r%boxRows == 0 {
+				b.WriteString(strings.Repeat("-", size+size/boxCols-1) + "\n")
+			}
+
+			row := s[r*size : (r+1)*size]
+			boxes := []string{}
+
+			for c := 0; c < size; c += boxCols {
+				boxes = append(boxes, row[c:c+boxCols])
+			}
+
+			b.WriteString(strings.Join(boxes, "|") + "\n")
+		}
+	default:
+		return "", ErrInvalidFormat
+	}
+
+	return b.String(), nil
+}
+
+// decodeLines reads one puzzle from every line of data that is not blank or a '#' comment
+func decodeLines(data string) ([]Entry, error) {
+	entries := []Entry{}
+
+	for n, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		g, err := Parse(fields[0])
+
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", n+1, err)
+		}
+
+		entries = append(entries, Entry{Name: strings.Join(fields[1:], " "), Grid: g})
+	}
+
+	return entries, nil
+}
+
+// decodeRows reads a single puzzle written as one line per row, skipping '#' metadata lines and the
+// box separators of the SimpleSudoku format. The '#D' description of a SadMan file is used as its name
+func decodeRows(data string, format Format) ([]Entry, error) {
+	entry := Entry{}
+	var cells strings.Builder
+	last, size := 0, 0
+
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "#") {
+			if strings.HasPrefix(line, "#D") {
+				entry.Name = strings.TrimSpace(line[2:])
+			}
+
+			continue
+		}
+
+		if format == FormatSS {
+			if strings.Trim(line, "-+*") == "" {
+				continue
+			}
+
+			line = strings.Replace(line, "|", "", -1)
+		}
+
+		if line == "" {
+			continue
+		}
+
+		// The first row sets the size of the grid
+		if size == 0 {
+			size = len(line)
+		}
+
+		if len(line) != size {
+			return nil, fmt.Errorf("Line %d: Row must have %d cells", n+1, size)
+		}
+
+		cells.WriteString(line)
+		last = n + 1
+	}
+
+	if cells.Len() == 0 {
+		return []Entry{}, nil
+	}
+
+	g, err := Parse(cells.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("Line %d: %v", last, err)
+	}
+
+	entry.Grid = g
+
+	return []Entry{entry}, nil
+}
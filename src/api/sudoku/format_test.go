@@ -0,0 +1,163 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+// ========== PARSE() ========== //
+func TestParseIfSuccessful(t *testing.T) {
+	g, err := Parse(strings.Replace(testPuzzle, "0", ".", 10))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.String() != strings.Replace(testPuzzle, "0", ".", -1) {
+		t.Errorf("Actual grid: %s, expected: %s", g, testPuzzle)
+	}
+}
+
+func TestParseIfInvalidLength(t *testing.T) {
+	_, err := Parse(testPuzzle[1:])
+
+	if err != ErrInvalidLength {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrInvalidLength)
+	}
+}
+
+func TestParseIfInvalidCharacter(t *testing.T) {
+	_, err := Parse("x" + testPuzzle[1:])
+
+	if err != ErrInvalidCharacter {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrInvalidCharacter)
+	}
+}
+
+func TestParseIfHexadoku(t *testing.T) {
+	s := "123456789abcdefg" + strings.Repeat(".", 240)
+	g, err := Parse(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Size() != 16 || g[0][9] != 10 || g[0][15] != 16 {
+		t.Errorf("Actual grid of size %d: %v, expected values 10 and 16 at (1, 10) and (1, 16)", g.Size(), g[0])
+	}
+
+	if expected := strings.ToUpper(s); g.String() != expected {
+		t.Errorf("Actual string: %s, expected: %s", g.String(), expected)
+	}
+}
+
+func TestParseIfValueLargerThanSize(t *testing.T) {
+	_, err := Parse("1234" + "3415" + "2143" + "4321")
+
+	if err != ErrInvalidValue {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrInvalidValue)
+	}
+}
+
+// ========== DECODE() ========== //
+func TestDecodeIfLines(t *testing.T) {
+	data := "# library\n" + testPuzzle + " first puzzle\n\n" + strings.Replace(testPuzzle, "0", ".", -1) + "\n"
+
+	entries, err := Decode(data, FormatLine)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Actual entries: %d, expected: %d", len(entries), 2)
+	}
+
+	if entries[0].Name != "first puzzle" || entries[1].Name != "" {
+		t.Errorf("Actual names: %q and %q, expected: %q and %q", entries[0].Name, entries[1].Name, "first puzzle", "")
+	}
+
+	if entries[1].Grid.String() != entries[0].Grid.String() {
+		t.Errorf("Actual grid: %s, expected: %s", entries[1].Grid, entries[0].Grid)
+	}
+}
+
+func TestDecodeIfLinesHaveInvalidPuzzle(t *testing.T) {
+	_, err := Decode(testPuzzle+"\n"+testPuzzle[1:], FormatLine)
+
+	if err == nil || !strings.HasPrefix(err.Error(), "Line 2:") {
+		t.Errorf("Actual error: %v, expected an error for line 2", err)
+	}
+}
+
+func TestEncodeAndDecodeIfSDKAndSS(t *testing.T) {
+	g := gridFromString(testPuzzle)
+
+	for _, format := range []Format{FormatSDK, FormatSS} {
+		data, err := Encode(g, "classic", format)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if detected := DetectFormat(data); detected != format {
+			t.Errorf("Actual detected format: %s, expected: %s", detected, format)
+		}
+
+		entries, err := Decode(data, format)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 1 || entries[0].Grid.String() != g.String() {
+			t.Errorf("Actual entries for %s: %+v, expected grid: %s", format, entries, g)
+		}
+	}
+}
+
+func TestEncodeIfSS(t *testing.T) {
+	data, err := Encode(gridFromString(testPuzzle), "", FormatSS)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := strings.Split(data, "\n")
+
+	if rows[0] != "53.|.7.|..." || rows[3] != "-----------" {
+		t.Errorf("Actual rows: %q and %q, expected: %q and %q", rows[0], rows[3], "53.|.7.|...", "-----------")
+	}
+}
+
+func TestDecodeIfEmpty(t *testing.T) {
+	_, err := Decode("# no puzzles\n", FormatLine)
+
+	if err != ErrNoPuzzles {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrNoPuzzles)
+	}
+}
+
+func TestEncodeIfSSSixBySix(t *testing.T) {
+	g, err := Parse("123456" + "456123" + "231564" + "564231" + "312645" + "645312")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Encode(g, "", FormatSS)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := strings.Split(data, "\n")
+
+	if rows[0] != "123|456" || rows[2] != "-------" || rows[3] != "231|564" {
+		t.Errorf("Actual rows: %q, expected 2x3 boxes", rows)
+	}
+
+	if detected := DetectFormat(data); detected != FormatSS {
+		t.Errorf("Actual detected format: %s, expected: %s", detected, FormatSS)
+	}
+}
@@ -0,0 +1,132 @@
+package sudoku
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+)
+
+// Difficulty is the target difficulty of a generated puzzle
+type Difficulty string
+
+// Difficulty levels supported by Generate
+const (
+	Easy   Difficulty = "easy"
+	Medium Difficulty = "medium"
+	Hard   Difficulty = "hard"
+	Expert Difficulty = "expert"
+)
+
+// Difficulties lists the difficulty levels from easiest to hardest
+var Difficulties = []Difficulty{Easy, Medium, Hard, Expert}
+
+// ErrInvalidDifficulty is returned when a difficulty is not one of the supported levels
+var ErrInvalidDifficulty = errors.New("Difficulty must be one of 'easy', 'medium', 'hard' or 'expert'")
+
+// minGivens is the number of givens that Generate stops removing cells at for each difficulty
+// Fewer givens make harder techniques more likely to be needed, but do not guarantee it
+var minGivens = map[Difficulty]int{
+	Easy:   36,
+	Medium: 26,
+	Hard:   22,
+	Expert: 17,
+}
+
+// ParseDifficulty converts s into a Difficulty, ignoring case and surrounding whitespace
+func ParseDifficulty(s string) (Difficulty, error) {
+	d := Difficulty(strings.ToLower(strings.TrimSpace(s)))
+
+	if _, ok := minGivens[d]; !ok {
+		return "", ErrInvalidDifficulty
+	}
+
+	return d, nil
+}
+
+// maxAttempts is the number of puzzles Generate carves before settling for the closest difficulty
+const maxAttempts = 500
+
+// Generate returns the givens of a new uniquely solvable 9x9 puzzle that grades as difficulty
+// The same difficulty and seed always produce the same puzzle
+func Generate(difficulty Difficulty, seed int64) (Grid, error) {
+	target, ok := minGivens[difficulty]
+
+	if !ok {
+		return nil, ErrInvalidDifficulty
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	var best Grid
+	bestDistance := len(difficultyRank)
+
+	// Carve puzzles until one grades as the requested difficulty
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		g := carve(rng, target)
+		grade, err := Rate(g)
+
+		if err != nil {
+			return nil, err
+		}
+
+		distance := difficultyRank[grade.Difficulty] - difficultyRank[difficulty]
+
+		if distance < 0 {
+			distance = -distance
+		}
+
+		if distance == 0 {
+			return g, nil
+		}
+
+		if distance < bestDistance {
+			best, bestDistance = g, distance
+		}
+	}
+
+	return best, nil
+}
+
+// carve fills a random solution grid and removes cells from it in symmetric pairs for as long as
+// the solution stays unique, keeping at least target givens
+func carve(rng *rand.Rand, target int) Grid {
+	// Fill an empty grid with digits tried in random order to get a random solution
+	s, _ := newSolver(NewGrid(Size), Classic)
+	s.limit = 1
+	s.rng = rng
+	s.search()
+
+	g := s.grid(s.solution)
+	filled := Size * Size
+
+	for _, cell := range rng.Perm(Size * Size) {
+		r, c := cell/Size, cell%Size
+		mr, mc := Size-1-r, Size-1-c
+
+		if g[r][c] == 0 {
+			continue
+		}
+
+		removed := 1
+
+		if cell != mr*Size+mc {
+			removed = 2
+		}
+
+		if filled-removed < target {
+			continue
+		}
+
+		value, mirror := g[r][c], g[mr][mc]
+		g[r][c], g[mr][mc] = 0, 0
+
+		if count, _ := CountSolutions(g, 2); count != 1 {
+			g[r][c], g[mr][mc] = value, mirror
+			continue
+		}
+
+		filled -= removed
+	}
+
+	return g
+}
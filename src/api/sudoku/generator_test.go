@@ -0,0 +1,75 @@
+package sudoku
+
+import "testing"
+
+// ========== GENERATE() ========== //
+func TestGenerateIfSuccessful(t *testing.T) {
+	for _, difficulty := range []Difficulty{Easy, Medium, Hard, Expert} {
+		g, err := Generate(difficulty, 42)
+
+		if err != nil {
+			t.Fatalf("Error: %s, expected nil", err)
+		}
+
+		if err = Validate(g); err != nil {
+			t.Errorf("Difficulty %s: error: %s, expected nil", difficulty, err)
+		}
+
+		grade, err := Rate(g)
+
+		if err != nil {
+			t.Fatalf("Error: %s, expected nil", err)
+		}
+
+		if grade.Difficulty != difficulty {
+			t.Errorf("Actual grade: %s, expected: %s", grade.Difficulty, difficulty)
+		}
+
+		if g.Filled() < minGivens[difficulty] {
+			t.Errorf("Difficulty %s: actual givens: %d, expected at least %d", difficulty, g.Filled(), minGivens[difficulty])
+		}
+	}
+}
+
+func TestGenerateIfSameSeed(t *testing.T) {
+	first, err := Generate(Medium, 1234)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	second, err := Generate(Medium, 1234)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	for i := range first {
+		for j := range first[i] {
+			if first[i][j] != second[i][j] {
+				t.Fatalf("Actual cell (%d, %d): %d and %d, expected equal", i+1, j+1, first[i][j], second[i][j])
+			}
+		}
+	}
+}
+
+func TestGenerateIfInvalidDifficulty(t *testing.T) {
+	_, err := Generate(Difficulty("impossible"), 1)
+
+	if err != ErrInvalidDifficulty {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrInvalidDifficulty)
+	}
+}
+
+// ========== PARSEDIFFICULTY() ========== //
+func TestParseDifficultyIfSuccessful(t *testing.T) {
+	d, err := ParseDifficulty(" Hard ")
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if d != Hard {
+		t.Errorf("Actual difficulty: %s, expected: %s", d, Hard)
+	}
+}
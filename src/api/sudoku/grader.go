@@ -0,0 +1,66 @@
+package sudoku
+
+// unsolvedPenalty is added to the score of a puzzle that cannot be finished without guessing
+const unsolvedPenalty = 100
+
+// difficultyRank orders the difficulty levels from easiest to hardest
+var difficultyRank = map[Difficulty]int{
+	Easy:   0,
+	Medium: 1,
+	Hard:   2,
+	Expert: 3,
+}
+
+// Grade describes how hard a puzzle is for a human to solve
+// Score is the sum of the weights of every step taken, so both harder techniques and more of them raise it
+// Techniques counts how many times each technique was applied
+// Solved is false if the puzzle could not be finished with the supported techniques, i.e. it needs guessing
+type Grade struct {
+	Difficulty Difficulty     `json:"difficulty"`
+	Score      int            `json:"score"`
+	Techniques map[string]int `json:"techniques"`
+	Solved     bool           `json:"solved"`
+}
+
+// Rate solves g step by step under the classic rules, always using the easiest technique available, and grades it
+// by the hardest technique required
+func Rate(g Grid) (Grade, error) {
+	return Classic.Rate(g)
+}
+
+// Rate grades g like the package-level Rate, solving it under rules
+func (rules Rules) Rate(g Grid) (Grade, error) {
+	st, err := newState(g, rules)
+
+	if err != nil {
+		return Grade{}, err
+	}
+
+	grade := Grade{Difficulty: Easy, Techniques: map[string]int{}}
+	weights := map[string]technique{}
+
+	for _, t := range techniques {
+		weights[t.name] = t
+	}
+
+	for step := st.next(); step != nil; step = st.next() {
+		t := weights[step.Technique]
+		grade.Techniques[t.name]++
+		grade.Score += t.weight
+
+		if difficultyRank[t.difficulty] > difficultyRank[grade.Difficulty] {
+			grade.Difficulty = t.difficulty
+		}
+
+		st.apply(*step)
+	}
+
+	grade.Solved = st.solved()
+
+	if !grade.Solved {
+		grade.Difficulty = Expert
+		grade.Score += unsolvedPenalty
+	}
+
+	return grade, nil
+}
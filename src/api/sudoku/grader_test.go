@@ -0,0 +1,51 @@
+package sudoku
+
+import "testing"
+
+// ========== RATE() ========== //
+func TestRateIfSinglesOnly(t *testing.T) {
+	grade, err := Rate(gridFromString(testPuzzle))
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if grade.Difficulty != Easy {
+		t.Errorf("Actual difficulty: %s, expected: %s", grade.Difficulty, Easy)
+	}
+
+	if !grade.Solved {
+		t.Errorf("Actual solved: false, expected true")
+	}
+
+	// Every empty cell is filled by exactly one single
+	empty := Size*Size - gridFromString(testPuzzle).Filled()
+
+	if grade.Techniques[HiddenSingle]+grade.Techniques[NakedSingle] != empty || grade.Score != empty {
+		t.Errorf("Actual techniques: %v, score: %d, expected %d singles", grade.Techniques, grade.Score, empty)
+	}
+}
+
+func TestRateIfNeedsGuessing(t *testing.T) {
+	// An empty grid has no logical steps at all
+	grade, err := Rate(NewGrid(Size))
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if grade.Solved || grade.Difficulty != Expert || grade.Score != unsolvedPenalty {
+		t.Errorf("Actual grade: %+v, expected unsolved expert", grade)
+	}
+}
+
+func TestRateIfContradictoryGivens(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[0][2] = 5
+
+	_, err := Rate(g)
+
+	if err != ErrContradictory {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrContradictory)
+	}
+}
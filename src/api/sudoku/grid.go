@@ -0,0 +1,132 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+)
+
+// Size is the number of rows, columns and boxes in a classic sudoku grid
+const Size = models.DefaultGridSize
+
+// Grid holds the values of a sudoku board indexed by [row][col], starting from 0
+// A value of 0 marks an empty cell
+type Grid [][]int
+
+// NewGrid returns an empty grid with size rows and size columns
+func NewGrid(size int) Grid {
+	g := make(Grid, size)
+
+	for i := range g {
+		g[i] = make([]int, size)
+	}
+
+	return g
+}
+
+// FromBoards builds a grid of the size of puzzle from its board cells, leaving the cells without a board empty
+// Returns an error if the size or boxes of the puzzle are not supported, or if a cell lies outside the grid or holds
+// an invalid value
+func FromBoards(puzzle models.Puzzle, boards []models.Board) (Grid, error) {
+	return fromBoards(puzzle, boards, false)
+}
+
+// GivensFromBoards builds a grid of the givens of a puzzle from all its board cells, leaving the other cells empty
+func GivensFromBoards(puzzle models.Puzzle, boards []models.Board) (Grid, error) {
+	return fromBoards(puzzle, boards, true)
+}
+
+// fromBoards builds a grid of the size of puzzle from boards, skipping the boards that are not givens if givensOnly
+// is set
+func fromBoards(puzzle models.Puzzle, boards []models.Board, givensOnly bool) (Grid, error) {
+	size := puzzle.GridSize()
+
+	if !models.ValidGridSize(size) {
+		return nil, fmt.Errorf("Puzzle has invalid size %d", size)
+	}
+
+	// Grids take the box shape of their size, so puzzles with other boxes cannot be played
+	boxRows, boxCols := models.BoxShape(size)
+
+	if (puzzle.BoxRows != 0 || puzzle.BoxCols != 0) && (puzzle.BoxRows != boxRows || puzzle.BoxCols != boxCols) {
+		return nil, fmt.Errorf("Puzzle has boxes of %dx%d cells, expected %dx%d", puzzle.BoxRows, puzzle.BoxCols, boxRows, boxCols)
+	}
+
+	g := NewGrid(size)
+
+	for _, board := range boards {
+		if board.BoardRow < 1 || board.BoardRow > size || board.BoardCol < 1 || board.BoardCol > size {
+			return nil, fmt.Errorf("Board (%d, %d) lies outside the grid", board.BoardRow, board.BoardCol)
+		}
+
+		if board.Value < 0 || board.Value > size {
+			return nil, fmt.Errorf("Board (%d, %d) has invalid value %d", board.BoardRow, board.BoardCol, board.Value)
+		}
+
+		if givensOnly && !board.Given {
+			continue
+		}
+
+		g[board.BoardRow-1][board.BoardCol-1] = board.Value
+	}
+
+	return g, nil
+}
+
+// Size returns the number of rows in the grid
+func (g Grid) Size() int {
+	return len(g)
+}
+
+// BoxShape returns the number of rows and columns in each box of the grid
+func (g Grid) BoxShape() (int, int) {
+	return models.BoxShape(len(g))
+}
+
+// Clone returns a deep copy of the grid
+func (g Grid) Clone() Grid {
+	c := NewGrid(len(g))
+
+	for i := range g {
+		copy(c[i], g[i])
+	}
+
+	return c
+}
+
+// Filled returns the number of non-empty cells in the grid
+func (g Grid) Filled() int {
+	n := 0
+
+	for i := range g {
+		for j := range g[i] {
+			if g[i][j] != 0 {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// check returns an error if the grid is not square or if its size is not supported
+func (g Grid) check() error {
+	if !models.ValidGridSize(len(g)) {
+		return errors.New("Grid has invalid size")
+	}
+
+	for i := range g {
+		if len(g[i]) != len(g) {
+			return errors.New("Grid must have the same number of rows and columns")
+		}
+
+		for j := range g[i] {
+			if g[i][j] < 0 || g[i][j] > len(g) {
+				return fmt.Errorf("Cell (%d, %d) has invalid value %d", i+1, j+1, g[i][j])
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+)
+
+// boardsFromString returns a board for every cell of a 9 by 9 grid written as a string, with the values as givens
+func boardsFromString(s string) []models.Board {
+	boards := []models.Board{}
+
+	for i, ch := range s {
+		value := int(ch - '0')
+		boards = append(boards, models.Board{BoardRow: i/Size + 1, BoardCol: i%Size + 1, Value: value, Given: value != 0})
+	}
+
+	return boards
+}
+
+// ========== FROMBOARDS() ========== //
+func TestFromBoardsIfMissingBoard(t *testing.T) {
+	// The board of (1, 1) is missing, which leaves its cell empty rather than breaking the grid
+	boards := boardsFromString(testPuzzle)[1:]
+
+	g, err := FromBoards(models.Puzzle{Size: 9, BoxRows: 3, BoxCols: 3}, boards)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	expected := gridFromString(testPuzzle)
+	expected[0][0] = 0
+
+	if g.String() != expected.String() {
+		t.Errorf("Actual grid: %s, expected: %s", g, expected)
+	}
+}
+
+func TestFromBoardsIfSizeOfPuzzle(t *testing.T) {
+	boards := []models.Board{{BoardRow: 4, BoardCol: 4, Value: 2}}
+
+	g, err := FromBoards(models.Puzzle{Size: 4, BoxRows: 2, BoxCols: 2}, boards)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if g.Size() != 4 || g[3][3] != 2 || g.Filled() != 1 {
+		t.Errorf("Actual grid: %s, expected a 4 by 4 grid with 2 at (4, 4)", g)
+	}
+}
+
+func TestFromBoardsIfInvalidBoxes(t *testing.T) {
+	// Grids of size 6 have boxes of 2 rows by 3 columns
+	_, err := FromBoards(models.Puzzle{Size: 6, BoxRows: 3, BoxCols: 2}, nil)
+
+	if err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
+
+func TestFromBoardsIfOutsideGrid(t *testing.T) {
+	boards := []models.Board{{BoardRow: 5, BoardCol: 1, Value: 1}}
+
+	_, err := FromBoards(models.Puzzle{Size: 4, BoxRows: 2, BoxCols: 2}, boards)
+
+	if err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
+
+// ========== GIVENSFROMBOARDS() ========== //
+func TestGivensFromBoardsIfEntries(t *testing.T) {
+	boards := boardsFromString(testPuzzle)
+	boards[2].Value = 4 // an entry in the empty cell (1, 3)
+
+	g, err := GivensFromBoards(models.Puzzle{}, boards)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if g.String() != gridFromString(testPuzzle).String() {
+		t.Errorf("Actual grid: %s, expected the givens only", g)
+	}
+}
@@ -0,0 +1,87 @@
+package sudoku
+
+import "errors"
+
+var (
+	// ErrNoLogicalStep is returned when a grid cannot be progressed without guessing
+	ErrNoLogicalStep = errors.New("No logical step available")
+
+	// ErrSolved is returned when a hint is requested for a grid without empty cells
+	ErrSolved = errors.New("Puzzle is already solved")
+)
+
+// Hint is the next value that can be placed by logic alone
+// Steps are the deductions leading up to the placement, in order, and the last step holds the placement
+// Technique and Cells describe the hardest of the steps, and Eliminations collects the eliminations of every step
+type Hint struct {
+	Technique    string      `json:"technique"`
+	Cells        []Cell      `json:"cells"`
+	Eliminations []Candidate `json:"eliminations"`
+	Placement    *Candidate  `json:"placement"`
+	Steps        []Step      `json:"steps"`
+}
+
+// NextHint returns the next logical deduction for g under the classic rules, without ever guessing
+// Returns ErrUnsolvable if the values in g cannot lead to a solution, ErrSolved if g has no empty cells
+// and ErrNoLogicalStep if the next value can only be found by guessing
+func NextHint(g Grid) (Hint, error) {
+	return Classic.NextHint(g)
+}
+
+// NextHint returns the next logical deduction for g under rules, without ever guessing
+func (rules Rules) NextHint(g Grid) (Hint, error) {
+	count, err := rules.CountSolutions(g, 1)
+
+	if err != nil {
+		return Hint{}, err
+	}
+
+	if count == 0 {
+		return Hint{}, ErrUnsolvable
+	}
+
+	st, err := newState(g, rules)
+
+	if err != nil {
+		return Hint{}, err
+	}
+
+	if st.solved() {
+		return Hint{}, ErrSolved
+	}
+
+	hint := Hint{Eliminations: []Candidate{}, Steps: []Step{}}
+	hardest := -1
+
+	// Candidates are not stored between hints, so eliminations are carried forward until a value can be placed
+	for step := st.next(); step != nil; step = st.next() {
+		hint.Steps = append(hint.Steps, *step)
+		hint.Eliminations = append(hint.Eliminations, step.Eliminations...)
+
+		if rank := techniqueRank(step.Technique); rank > hardest {
+			hardest = rank
+			hint.Technique = step.Technique
+			hint.Cells = step.Cells
+		}
+
+		if step.Placement != nil {
+			hint.Placement = step.Placement
+			return hint, nil
+		}
+
+		st.apply(*step)
+	}
+
+	return Hint{}, ErrNoLogicalStep
+}
+
+// techniqueRank returns the position of a technique in techniques, from easiest to hardest
+func techniqueRank(name string) int {
+	for i, t := range techniques {
+		if t.name == name {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,56 @@
+package sudoku
+
+import "testing"
+
+// ========== NEXTHINT() ========== //
+func TestNextHintIfSuccessful(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	solution := gridFromString(testSolution)
+
+	hint, err := NextHint(g)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if hint.Placement == nil {
+		t.Fatalf("No placement, expected placement")
+	}
+
+	p := hint.Placement
+
+	if g[p.Row-1][p.Col-1] != 0 || solution[p.Row-1][p.Col-1] != p.Value {
+		t.Errorf("Actual placement: %+v, expected an empty cell with its solution", p)
+	}
+
+	if hint.Technique != HiddenSingle && hint.Technique != NakedSingle {
+		t.Errorf("Actual technique: %s, expected a single", hint.Technique)
+	}
+}
+
+func TestNextHintIfNoLogicalStep(t *testing.T) {
+	_, err := NextHint(NewGrid(Size))
+
+	if err != ErrNoLogicalStep {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrNoLogicalStep)
+	}
+}
+
+func TestNextHintIfSolved(t *testing.T) {
+	_, err := NextHint(gridFromString(testSolution))
+
+	if err != ErrSolved {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrSolved)
+	}
+}
+
+func TestNextHintIfWrongEntry(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[0][2] = 1 // solution is 4, and 1 leaves the puzzle without a solution
+
+	_, err := NextHint(g)
+
+	if err != ErrUnsolvable {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrUnsolvable)
+	}
+}
@@ -0,0 +1,147 @@
+package sudoku
+
+// layout describes the groups of cells in a grid that must hold distinct values, and the values each cell may hold
+// Cells are referred to by their index in row-major order, i.e. row*size + col
+type layout struct {
+	size      int
+	units     [][]int    // cells in each group of distinct values
+	kinds     []unitKind // the rule that each unit comes from
+	sums      []int      // the total of the values of each unit, or 0 if any total is allowed
+	allowed   []uint32   // bit d is set if digit d may be placed in the cell
+	cellUnits [][]int    // units that each cell belongs to
+	peers     [][]int    // cells that share at least one unit with each cell
+	isPeer    [][]bool   // isPeer[a][b] is true if b is in peers[a]
+}
+
+// unitKind describes the rule that a unit comes from
+type unitKind int
+
+const (
+	rowUnit unitKind = iota
+	colUnit
+	boxUnit
+	regionUnit
+	diagonalUnit
+	cageUnit
+	knightUnit
+	kingUnit
+)
+
+// newLayout returns the rows, columns and boxes of a grid, changed by the constraints of rules
+// Returns an error if a constraint does not fit the grid
+func newLayout(g Grid, rules Rules) (*layout, error) {
+	size := g.Size()
+	boxRows, boxCols := g.BoxShape()
+	l := &layout{size: size, allowed: make([]uint32, size*size)}
+
+	for cell := range l.allowed {
+		l.allowed[cell] = uint32(1<<uint(size+1)) - 2
+	}
+
+	for r := 0; r < size; r++ {
+		unit := []int{}
+
+		for c := 0; c < size; c++ {
+			unit = append(unit, r*size+c)
+		}
+
+		l.add(rowUnit, unit, 0)
+	}
+
+	for c := 0; c < size; c++ {
+		unit := []int{}
+
+		for r := 0; r < size; r++ {
+			unit = append(unit, r*size+c)
+		}
+
+		l.add(colUnit, unit, 0)
+	}
+
+	for br := 0; br < size; br += boxRows {
+		for bc := 0; bc < size; bc += boxCols {
+			unit := []int{}
+
+			for r := br; r < br+boxRows; r++ {
+				for c := bc; c < bc+boxCols; c++ {
+					unit = append(unit, r*size+c)
+				}
+			}
+
+			l.add(boxUnit, unit, 0)
+		}
+	}
+
+	for _, c := range rules {
+		if err := c.apply(l); err != nil {
+			return nil, err
+		}
+	}
+
+	l.index()
+	return l, nil
+}
+
+// add appends a unit of cells that must hold distinct values adding up to sum, or to any total if sum is 0
+func (l *layout) add(kind unitKind, cells []int, sum int) {
+	l.units = append(l.units, cells)
+	l.kinds = append(l.kinds, kind)
+	l.sums = append(l.sums, sum)
+}
+
+// removeKind removes every unit of kind, e.g. the boxes of a grid divided into irregular regions
+func (l *layout) removeKind(kind unitKind) {
+	units, kinds, sums := l.units[:0], l.kinds[:0], l.sums[:0]
+
+	for u := range l.units {
+		if l.kinds[u] != kind {
+			units = append(units, l.units[u])
+			kinds = append(kinds, l.kinds[u])
+			sums = append(sums, l.sums[u])
+		}
+	}
+
+	l.units, l.kinds, l.sums = units, kinds, sums
+}
+
+// house returns true if unit u holds every value once, rather than only distinct values
+func (l *layout) house(u int) bool {
+	switch l.kinds[u] {
+	case rowUnit, colUnit, boxUnit, regionUnit, diagonalUnit:
+		return true
+	}
+
+	return false
+}
+
+// block returns true if unit u is a box or an irregular region
+func (l *layout) block(u int) bool {
+	return l.kinds[u] == boxUnit || l.kinds[u] == regionUnit
+}
+
+// index populates cellUnits and peers from units
+func (l *layout) index() {
+	cells := l.size * l.size
+	l.cellUnits = make([][]int, cells)
+	l.peers = make([][]int, cells)
+	l.isPeer = make([][]bool, cells)
+
+	for u, unit := range l.units {
+		for _, cell := range unit {
+			l.cellUnits[cell] = append(l.cellUnits[cell], u)
+		}
+	}
+
+	for cell := 0; cell < cells; cell++ {
+		l.isPeer[cell] = make([]bool, cells)
+
+		for _, u := range l.cellUnits[cell] {
+			for _, peer := range l.units[u] {
+				if peer != cell && !l.isPeer[cell][peer] {
+					l.isPeer[cell][peer] = true
+					l.peers[cell] = append(l.peers[cell], peer)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package sudoku
+
+import "math/bits"
+
+// Cell is the position of a cell in a grid, with rows and columns starting from 1
+type Cell struct {
+	Row int `json:"board_row"`
+	Col int `json:"board_col"`
+}
+
+// Candidate is a value that may be placed in a cell
+type Candidate struct {
+	Row   int `json:"board_row"`
+	Col   int `json:"board_col"`
+	Value int `json:"value"`
+}
+
+// Step is a single logical deduction
+// Cells are the cells that make up the pattern of the technique, Eliminations are the
+// candidates that the pattern rules out and Placement is the value that can be placed, if any
+type Step struct {
+	Technique    string      `json:"technique"`
+	Cells        []Cell      `json:"cells"`
+	Eliminations []Candidate `json:"eliminations"`
+	Placement    *Candidate  `json:"placement,omitempty"`
+}
+
+// state holds the values and pencil mark candidates of a grid while it is solved by logic
+type state struct {
+	*layout
+	cells []int
+	cands []uint32 // bit d is set if digit d is a candidate of the cell
+}
+
+// newState computes the candidates of every empty cell of g under rules
+// Returns ErrContradictory if two givens share a unit or the givens break a variant rule
+func newState(g Grid, rules Rules) (*state, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+
+	l, err := newLayout(g, rules)
+
+	if err != nil {
+		return nil, err
+	}
+
+	st := &state{
+		layout: l,
+		cells:  make([]int, l.size*l.size),
+		cands:  append([]uint32{}, l.allowed...),
+	}
+
+	for r := range g {
+		for c, d := range g[r] {
+			if d == 0 {
+				continue
+			}
+
+			cell := r*l.size + c
+
+			if st.cands[cell]&(1<<uint(d)) == 0 {
+				return nil, ErrContradictory
+			}
+
+			st.place(cell, d)
+		}
+	}
+
+	for u := range st.units {
+		if st.sums[u] != 0 && !st.restrictSum(u) {
+			return nil, ErrContradictory
+		}
+	}
+
+	return st, nil
+}
+
+// restrictSum removes the candidates of the empty cells of unit u that cannot be part of any set of distinct digits
+// completing its sum, and returns false if the sum cannot be completed
+func (st *state) restrictSum(u int) bool {
+	total, empty := 0, 0
+	var used uint32
+
+	for _, cell := range st.units[u] {
+		if d := st.cells[cell]; d != 0 {
+			total += d
+			used |= 1 << uint(d)
+		} else {
+			empty++
+		}
+	}
+
+	if empty == 0 {
+		return total == st.sums[u]
+	}
+
+	var fit uint32
+
+	// Try every set of empty digits in ascending order, remembering the digits of the sets that complete the sum
+	var choose func(from, n, rest int, set uint32)
+
+	choose = func(from, n, rest int, set uint32) {
+		if n == 0 {
+			if rest == 0 {
+				fit |= set
+			}
+
+			return
+		}
+
+		for d := from; d <= st.size && d <= rest; d++ {
+			if used&(1<<uint(d)) == 0 {
+				choose(d+1, n-1, rest-d, set|1<<uint(d))
+			}
+		}
+	}
+
+	choose(1, empty, st.sums[u]-total, 0)
+
+	for _, cell := range st.units[u] {
+		st.cands[cell] &= fit
+	}
+
+	return fit != 0
+}
+
+// place sets the value of cell and removes d from the candidates of its peers
+func (st *state) place(cell, d int) {
+	st.cells[cell] = d
+	st.cands[cell] = 0
+
+	for _, peer := range st.peers[cell] {
+		st.cands[peer] &^= 1 << uint(d)
+	}
+}
+
+// apply carries out the placement and eliminations of step
+func (st *state) apply(step Step) {
+	for _, e := range step.Eliminations {
+		st.cands[st.index(e.Row, e.Col)] &^= 1 << uint(e.Value)
+	}
+
+	if step.Placement != nil {
+		st.place(st.index(step.Placement.Row, step.Placement.Col), step.Placement.Value)
+	}
+}
+
+// solved returns true if every cell has a value
+func (st *state) solved() bool {
+	for _, d := range st.cells {
+		if d == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// broken returns true if an empty cell has no candidates left
+func (st *state) broken() bool {
+	for cell, d := range st.cells {
+		if d == 0 && st.cands[cell] == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// index converts a 1-based row and column into a cell index
+func (st *state) index(row, col int) int {
+	return (row-1)*st.size + col - 1
+}
+
+// cell converts a cell index into a 1-based Cell
+func (st *state) cell(cell int) Cell {
+	return Cell{Row: cell/st.size + 1, Col: cell%st.size + 1}
+}
+
+// candidate converts a cell index and digit into a 1-based Candidate
+func (st *state) candidate(cell, d int) Candidate {
+	return Candidate{Row: cell/st.size + 1, Col: cell%st.size + 1, Value: d}
+}
+
+// positions returns the empty cells of unit u that have d as a candidate
+func (st *state) positions(u, d int) []int {
+	cells := []int{}
+
+	for _, cell := range st.units[u] {
+		if st.cands[cell]&(1<<uint(d)) != 0 {
+			cells = append(cells, cell)
+		}
+	}
+
+	return cells
+}
+
+// count returns the number of candidates of cell
+func (st *state) count(cell int) int {
+	return bits.OnesCount32(st.cands[cell])
+}
+
+// digits returns the digits set in mask in ascending order
+func digits(mask uint32) []int {
+	ds := []int{}
+
+	for ; mask != 0; mask &= mask - 1 {
+		ds = append(ds, bits.TrailingZeros32(mask))
+	}
+
+	return ds
+}
@@ -0,0 +1,36 @@
+package sudoku
+
+import "fmt"
+
+// Constraint is a rule of a sudoku variant, on top of the rows and columns that every grid has
+// A constraint adds groups of cells that must hold distinct values or limits the values that cells may hold, so
+// that solving, validation, conflicts and hints all follow it without knowing about the variant
+type Constraint interface {
+	// Variant returns the name of the variant that the constraint belongs to, e.g. "x" or "killer"
+	Variant() string
+
+	// apply adds the constraint to the layout of a grid, or returns an error if it does not fit the grid
+	apply(l *layout) error
+}
+
+// Rules is the set of constraints of a puzzle
+type Rules []Constraint
+
+// Classic is the set of constraints of a classic sudoku, which only has rows, columns and boxes
+var Classic = Rules{}
+
+// Check returns an error if a constraint of rules does not fit a grid of size rows and columns
+func (rules Rules) Check(size int) error {
+	_, err := newLayout(NewGrid(size), rules)
+	return err
+}
+
+// index converts a 1-based Cell into a cell index of a grid of size rows and columns
+// Returns an error if the cell lies outside the grid
+func (c Cell) index(size int) (int, error) {
+	if c.Row < 1 || c.Row > size || c.Col < 1 || c.Col > size {
+		return 0, fmt.Errorf("Cell (%d, %d) lies outside the grid", c.Row, c.Col)
+	}
+
+	return (c.Row-1)*size + c.Col - 1, nil
+}
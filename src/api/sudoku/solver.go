@@ -0,0 +1,263 @@
+package sudoku
+
+import (
+	"errors"
+	"math/bits"
+	"math/rand"
+)
+
+var (
+	// ErrContradictory is returned when the givens of a grid break the row, column, box or variant rules
+	ErrContradictory = errors.New("Puzzle givens are contradictory")
+
+	// ErrUnsolvable is returned when a grid has valid givens but no solution
+	ErrUnsolvable = errors.New("Puzzle has no solution")
+)
+
+// Solution is the result of solving a grid
+// Unique is false when the grid has more than one solution, in which case Grid holds the first one found
+type Solution struct {
+	Grid   Grid `json:"grid"`
+	Unique bool `json:"unique"`
+}
+
+// solver is a backtracking solver that tracks the digits used in every unit as bitmasks
+type solver struct {
+	*layout
+	cells    []int
+	used     []uint32   // bit d is set if digit d is placed in the unit
+	totals   []int      // total of the digits placed in the unit
+	filled   []int      // number of digits placed in the unit
+	limit    int        // stop searching once this many solutions are found
+	rng      *rand.Rand // if set, digits are tried in random order
+	count    int
+	solution []int
+}
+
+// newSolver places the givens of g into a new solver that follows rules
+// Returns ErrContradictory if two givens share a unit or a given breaks a variant rule
+func newSolver(g Grid, rules Rules) (*solver, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+
+	l, err := newLayout(g, rules)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &solver{
+		layout: l,
+		cells:  make([]int, l.size*l.size),
+		used:   make([]uint32, len(l.units)),
+		totals: make([]int, len(l.units)),
+		filled: make([]int, len(l.units)),
+	}
+
+	for r := range g {
+		for c, d := range g[r] {
+			if d == 0 {
+				continue
+			}
+
+			cell := r*l.size + c
+
+			if s.candidates(cell)&(1<<uint(d)) == 0 {
+				return nil, ErrContradictory
+			}
+
+			s.place(cell, d)
+		}
+	}
+
+	return s, nil
+}
+
+// Solve returns the solution of g under the classic rules and whether it is unique
+// Returns ErrContradictory if the givens break the rules and ErrUnsolvable if there is no solution
+func Solve(g Grid) (Solution, error) {
+	return Classic.Solve(g)
+}
+
+// Solve returns the solution of g under rules and whether it is unique
+func (rules Rules) Solve(g Grid) (Solution, error) {
+	s, err := newSolver(g, rules)
+
+	if err != nil {
+		return Solution{}, err
+	}
+
+	s.limit = 2
+	s.search()
+
+	if s.count == 0 {
+		return Solution{}, ErrUnsolvable
+	}
+
+	return Solution{Grid: s.grid(s.solution), Unique: s.count == 1}, nil
+}
+
+// CountSolutions returns the number of solutions of g under the classic rules, counting no further than limit
+func CountSolutions(g Grid, limit int) (int, error) {
+	return Classic.CountSolutions(g, limit)
+}
+
+// CountSolutions returns the number of solutions of g under rules, counting no further than limit
+func (rules Rules) CountSolutions(g Grid, limit int) (int, error) {
+	s, err := newSolver(g, rules)
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.limit = limit
+	s.search()
+
+	return s.count, nil
+}
+
+// candidates returns the digits that can be placed in cell as a bitmask
+func (s *solver) candidates(cell int) uint32 {
+	var used uint32
+
+	for _, u := range s.cellUnits[cell] {
+		used |= s.used[u]
+	}
+
+	mask := s.allowed[cell] &^ used
+
+	for _, u := range s.cellUnits[cell] {
+		if s.sums[u] != 0 {
+			mask = s.sumCandidates(u, mask)
+		}
+	}
+
+	return mask
+}
+
+// sumCandidates returns the digits of mask that leave the total of unit u able to reach its sum
+func (s *solver) sumCandidates(u int, mask uint32) uint32 {
+	var fit uint32
+	left := len(s.units[u]) - s.filled[u] - 1
+
+	for _, d := range digits(mask) {
+		if reachable(s.sums[u]-s.totals[u]-d, left, s.used[u]|1<<uint(d), s.size) {
+			fit |= 1 << uint(d)
+		}
+	}
+
+	return fit
+}
+
+// reachable returns true if n distinct digits from 1 to size that are not set in used can add up to total
+func reachable(total, n int, used uint32, size int) bool {
+	free := []int{}
+
+	for d := 1; d <= size; d++ {
+		if used&(1<<uint(d)) == 0 {
+			free = append(free, d)
+		}
+	}
+
+	if n > len(free) {
+		return false
+	}
+
+	low, high := 0, 0
+
+	for i := 0; i < n; i++ {
+		low += free[i]
+		high += free[len(free)-1-i]
+	}
+
+	return low <= total && total <= high
+}
+
+func (s *solver) place(cell, d int) {
+	s.cells[cell] = d
+
+	for _, u := range s.cellUnits[cell] {
+		s.used[u] |= 1 << uint(d)
+		s.totals[u] += d
+		s.filled[u]++
+	}
+}
+
+func (s *solver) remove(cell int) {
+	d := s.cells[cell]
+	s.cells[cell] = 0
+
+	for _, u := range s.cellUnits[cell] {
+		s.used[u] &^= 1 << uint(d)
+		s.totals[u] -= d
+		s.filled[u]--
+	}
+}
+
+// search fills the empty cell with the fewest candidates first and backtracks on dead ends
+func (s *solver) search() {
+	best, bestCount := -1, s.size+1
+	var bestMask uint32
+
+	for cell, d := range s.cells {
+		if d != 0 {
+			continue
+		}
+
+		mask := s.candidates(cell)
+		n := bits.OnesCount32(mask)
+
+		if n < bestCount {
+			best, bestCount, bestMask = cell, n, mask
+
+			if n <= 1 {
+				break
+			}
+		}
+	}
+
+	// No empty cells left, grid is solved
+	if best == -1 {
+		s.count++
+
+		if s.solution == nil {
+			s.solution = append([]int{}, s.cells...)
+		}
+
+		return
+	}
+
+	digits := []int{}
+
+	for mask := bestMask; mask != 0; mask &= mask - 1 {
+		digits = append(digits, bits.TrailingZeros32(mask))
+	}
+
+	if s.rng != nil {
+		s.rng.Shuffle(len(digits), func(i, j int) {
+			digits[i], digits[j] = digits[j], digits[i]
+		})
+	}
+
+	for _, d := range digits {
+		if s.count >= s.limit {
+			return
+		}
+
+		s.place(best, d)
+		s.search()
+		s.remove(best)
+	}
+}
+
+// grid converts a slice of cells in row-major order into a Grid
+func (s *solver) grid(cells []int) Grid {
+	g := NewGrid(s.size)
+
+	for i, d := range cells {
+		g[i/s.size][i%s.size] = d
+	}
+
+	return g
+}
@@ -0,0 +1,128 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+const (
+	testPuzzle   = "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	testSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+// gridFromString builds a 9x9 grid from an 81 character string of digits
+func gridFromString(s string) Grid {
+	g := NewGrid(Size)
+
+	for i, ch := range s {
+		if ch >= '1' && ch <= '9' {
+			g[i/Size][i%Size] = int(ch - '0')
+		}
+	}
+
+	return g
+}
+
+// ========== SOLVE() ========== //
+func TestSolveIfSuccessful(t *testing.T) {
+	solution, err := Solve(gridFromString(testPuzzle))
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	expected := gridFromString(testSolution)
+
+	for i := range expected {
+		for j := range expected[i] {
+			if solution.Grid[i][j] != expected[i][j] {
+				t.Fatalf("Actual cell (%d, %d): %d, expected: %d", i+1, j+1, solution.Grid[i][j], expected[i][j])
+			}
+		}
+	}
+
+	if !solution.Unique {
+		t.Errorf("Actual unique: false, expected true")
+	}
+}
+
+func TestSolveIfMultipleSolutions(t *testing.T) {
+	solution, err := Solve(NewGrid(Size))
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if solution.Unique {
+		t.Errorf("Actual unique: true, expected false")
+	}
+
+	if solution.Grid.Filled() != Size*Size {
+		t.Errorf("Actual filled cells: %d, expected %d", solution.Grid.Filled(), Size*Size)
+	}
+}
+
+func TestSolveIfSixBySix(t *testing.T) {
+	expected := "123456" + "456123" + "231564" + "564231" + "312645" + "645312"
+	g, err := Parse("1..4.6" + "4.6..3" + ".3.5.4" + "5.4.3." + "3..6.5" + "6.5..2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solution, err := Solve(g)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if solution.Grid.String() != expected {
+		t.Errorf("Actual solution: %s, expected: %s", solution.Grid, expected)
+	}
+}
+
+func TestSolveIfHexadoku(t *testing.T) {
+	solution, err := Solve(NewGrid(16))
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if !Solved(solution.Grid) || solution.Grid.Size() != 16 {
+		t.Errorf("Actual solution: %s, expected a valid filled 16x16 grid", solution.Grid)
+	}
+}
+
+func TestSolveIfContradictoryGivens(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[0][2] = 5 // duplicates the 5 at (1, 1)
+
+	_, err := Solve(g)
+
+	if err != ErrContradictory {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrContradictory)
+	}
+}
+
+func TestSolveIfUnsolvable(t *testing.T) {
+	// Row 1 leaves only 9 for (1, 9) but column 9 already holds a 9
+	g := gridFromString("12345678.........9")
+
+	_, err := Solve(g)
+
+	if err != ErrUnsolvable {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrUnsolvable)
+	}
+}
+
+// ========== COUNTSOLUTIONS() ========== //
+func TestCountSolutionsIfLimited(t *testing.T) {
+	count, err := CountSolutions(NewGrid(Size), 5)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	if count != 5 {
+		t.Errorf("Actual count: %d, expected 5", count)
+	}
+}
@@ -0,0 +1,468 @@
+package sudoku
+
+import "math/bits"
+
+// Names of the solving techniques used in steps, from easiest to hardest
+const (
+	HiddenSingle     = "hidden_single"
+	NakedSingle      = "naked_single"
+	Pointing         = "pointing"
+	BoxLineReduction = "box_line_reduction"
+	NakedPair        = "naked_pair"
+	HiddenPair       = "hidden_pair"
+	XWing            = "x_wing"
+	XYWing           = "xy_wing"
+	Swordfish        = "swordfish"
+	XYChain          = "xy_chain"
+)
+
+// maxChainLength is the number of cells after which XY-chains are no longer extended
+const maxChainLength = 6
+
+// technique is a logical solving technique and how much it adds to the difficulty of a puzzle
+type technique struct {
+	name       string
+	weight     int
+	difficulty Difficulty
+	find       func(*state) *Step
+}
+
+// techniques are tried in order, so that the easiest applicable step is always found first
+var techniques = []technique{
+	{HiddenSingle, 1, Easy, findHiddenSingle},
+	{NakedSingle, 1, Easy, findNakedSingle},
+	{Pointing, 4, Medium, func(st *state) *Step { return findLockedCandidates(st, true) }},
+	{BoxLineReduction, 4, Medium, func(st *state) *Step { return findLockedCandidates(st, false) }},
+	{NakedPair, 5, Medium, findNakedPair},
+	{HiddenPair, 6, Medium, findHiddenPair},
+	{XWing, 10, Hard, func(st *state) *Step { return findFish(st, 2, XWing) }},
+	{XYWing, 12, Hard, findXYWing},
+	{Swordfish, 15, Hard, func(st *state) *Step { return findFish(st, 3, Swordfish) }},
+	{XYChain, 20, Expert, findXYChain},
+}
+
+// next returns the easiest logical step available, or nil if there is none
+func (st *state) next() *Step {
+	if st.broken() {
+		return nil
+	}
+
+	for _, t := range techniques {
+		if step := t.find(st); step != nil {
+			return step
+		}
+	}
+
+	return nil
+}
+
+// findHiddenSingle finds a digit that fits in only one cell of a unit that holds every digit
+func findHiddenSingle(st *state) *Step {
+	for u := range st.units {
+		if !st.house(u) {
+			continue
+		}
+
+		for d := 1; d <= st.size; d++ {
+			pos := st.positions(u, d)
+
+			if len(pos) == 1 {
+				placement := st.candidate(pos[0], d)
+
+				return &Step{
+					Technique:    HiddenSingle,
+					Cells:        []Cell{st.cell(pos[0])},
+					Eliminations: []Candidate{},
+					Placement:    &placement,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findNakedSingle finds a cell with only one candidate left
+func findNakedSingle(st *state) *Step {
+	for cell, d := range st.cells {
+		if d == 0 && st.count(cell) == 1 {
+			placement := st.candidate(cell, bits.TrailingZeros32(st.cands[cell]))
+
+			return &Step{
+				Technique:    NakedSingle,
+				Cells:        []Cell{st.cell(cell)},
+				Eliminations: []Candidate{},
+				Placement:    &placement,
+			}
+		}
+	}
+
+	return nil
+}
+
+// findLockedCandidates finds a digit whose candidates in one unit all lie in a second unit,
+// so that the digit can be removed from the rest of the second unit
+// The first unit must hold every digit. If fromBox is true, it is a box or jigsaw region (pointing), otherwise it is
+// a row, column or diagonal (box/line reduction)
+func findLockedCandidates(st *state, fromBox bool) *Step {
+	name := BoxLineReduction
+
+	if fromBox {
+		name = Pointing
+	}
+
+	for a := range st.units {
+		if !st.house(a) || st.block(a) != fromBox {
+			continue
+		}
+
+		for d := 1; d <= st.size; d++ {
+			pos := st.positions(a, d)
+
+			if len(pos) < 2 {
+				continue
+			}
+
+			for _, b := range st.cellUnits[pos[0]] {
+				if b == a || !st.containsAll(b, pos) {
+					continue
+				}
+
+				elims := []Candidate{}
+
+				for _, cell := range st.units[b] {
+					if st.cands[cell]&(1<<uint(d)) != 0 && !contains(pos, cell) {
+						elims = append(elims, st.candidate(cell, d))
+					}
+				}
+
+				if len(elims) > 0 {
+					return &Step{Technique: name, Cells: st.cellList(pos), Eliminations: elims}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findNakedPair finds two cells in a unit with the same two candidates, which can be removed
+// from the other cells of the unit
+func findNakedPair(st *state) *Step {
+	for u := range st.units {
+		unit := st.units[u]
+
+		for i, a := range unit {
+			if st.cells[a] != 0 || st.count(a) != 2 {
+				continue
+			}
+
+			for _, b := range unit[i+1:] {
+				if st.cells[b] != 0 || st.cands[b] != st.cands[a] {
+					continue
+				}
+
+				elims := []Candidate{}
+
+				for _, cell := range unit {
+					if cell == a || cell == b {
+						continue
+					}
+
+					for _, d := range digits(st.cands[cell] & st.cands[a]) {
+						elims = append(elims, st.candidate(cell, d))
+					}
+				}
+
+				if len(elims) > 0 {
+					return &Step{Technique: NakedPair, Cells: st.cellList([]int{a, b}), Eliminations: elims}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findHiddenPair finds two digits that only fit in the same two cells of a unit that holds every digit, so that
+// the other candidates of those cells can be removed
+func findHiddenPair(st *state) *Step {
+	for u := range st.units {
+		if !st.house(u) {
+			continue
+		}
+
+		for d1 := 1; d1 <= st.size; d1++ {
+			p1 := st.positions(u, d1)
+
+			if len(p1) != 2 {
+				continue
+			}
+
+			for d2 := d1 + 1; d2 <= st.size; d2++ {
+				p2 := st.positions(u, d2)
+
+				if len(p2) != 2 || p1[0] != p2[0] || p1[1] != p2[1] {
+					continue
+				}
+
+				mask := uint32(1)<<uint(d1) | uint32(1)<<uint(d2)
+				elims := []Candidate{}
+
+				for _, cell := range p1 {
+					for _, d := range digits(st.cands[cell] &^ mask) {
+						elims = append(elims, st.candidate(cell, d))
+					}
+				}
+
+				if len(elims) > 0 {
+					return &Step{Technique: HiddenPair, Cells: st.cellList(p1), Eliminations: elims}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findFish finds n rows (or columns) in which the candidates of a digit all lie in the same n columns (or rows),
+// so that the digit can be removed from the rest of those columns (or rows)
+// An X-wing has n = 2 and a swordfish has n = 3
+func findFish(st *state, n int, name string) *Step {
+	for d := 1; d <= st.size; d++ {
+		for _, kinds := range [][2]unitKind{{rowUnit, colUnit}, {colUnit, rowUnit}} {
+			base, cover := kinds[0], kinds[1]
+			lines := []int{}
+			masks := []uint32{}
+
+			for u := range st.units {
+				if st.kinds[u] != base {
+					continue
+				}
+
+				pos := st.positions(u, d)
+
+				if len(pos) < 2 || len(pos) > n {
+					continue
+				}
+
+				var mask uint32
+
+				for _, cell := range pos {
+					mask |= 1 << uint(st.lineOf(cell, cover)%st.size)
+				}
+
+				lines = append(lines, u)
+				masks = append(masks, mask)
+			}
+
+			var choose func(start int, chosen []int, mask uint32) *Step
+
+			choose = func(start int, chosen []int, mask uint32) *Step {
+				if len(chosen) == n {
+					if bits.OnesCount32(mask) != n {
+						return nil
+					}
+
+					elims := []Candidate{}
+
+					for _, i := range digits(mask) {
+						u := i
+
+						if cover == colUnit {
+							u += st.size
+						}
+
+						for _, cell := range st.units[u] {
+							if st.cands[cell]&(1<<uint(d)) != 0 && !contains(chosen, st.lineOf(cell, base)) {
+								elims = append(elims, st.candidate(cell, d))
+							}
+						}
+					}
+
+					if len(elims) == 0 {
+						return nil
+					}
+
+					cells := []int{}
+
+					for _, u := range chosen {
+						cells = append(cells, st.positions(u, d)...)
+					}
+
+					return &Step{Technique: name, Cells: st.cellList(cells), Eliminations: elims}
+				}
+
+				for i := start; i < len(lines); i++ {
+					m := mask | masks[i]
+
+					if bits.OnesCount32(m) > n {
+						continue
+					}
+
+					if step := choose(i+1, append(chosen, lines[i]), m); step != nil {
+						return step
+					}
+				}
+
+				return nil
+			}
+
+			if step := choose(0, []int{}, 0); step != nil {
+				return step
+			}
+		}
+	}
+
+	return nil
+}
+
+// findXYWing finds a pivot cell with candidates xy that sees two pincer cells with candidates xz and yz,
+// so that z can be removed from every cell that sees both pincers
+func findXYWing(st *state) *Step {
+	for p := range st.cells {
+		if st.cells[p] != 0 || st.count(p) != 2 {
+			continue
+		}
+
+		for _, a := range st.peers[p] {
+			if st.cells[a] != 0 || st.count(a) != 2 || st.cands[a] == st.cands[p] {
+				continue
+			}
+
+			shared := st.cands[a] & st.cands[p]
+
+			if bits.OnesCount32(shared) != 1 {
+				continue
+			}
+
+			z := st.cands[a] &^ shared
+			want := st.cands[p]&^shared | z
+
+			for _, b := range st.peers[p] {
+				if b == a || st.cells[b] != 0 || st.cands[b] != want {
+					continue
+				}
+
+				elims := []Candidate{}
+
+				for _, cell := range st.peers[a] {
+					if cell != p && cell != b && st.isPeer[b][cell] && st.cands[cell]&z != 0 {
+						elims = append(elims, st.candidate(cell, bits.TrailingZeros32(z)))
+					}
+				}
+
+				if len(elims) > 0 {
+					return &Step{Technique: XYWing, Cells: st.cellList([]int{p, a, b}), Eliminations: elims}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findXYChain finds a chain of cells with two candidates each, where consecutive cells see each other
+// and share a candidate, that starts and ends with the same digit z
+// Either end of the chain must hold z, so z can be removed from every cell that sees both ends
+func findXYChain(st *state) *Step {
+	for start := range st.cells {
+		if st.cells[start] != 0 || st.count(start) != 2 {
+			continue
+		}
+
+		for _, z := range digits(st.cands[start]) {
+			zbit := uint32(1) << uint(z)
+			chain := []int{start}
+			visited := map[int]bool{start: true}
+
+			var extend func(cur int, out uint32) *Step
+
+			extend = func(cur int, out uint32) *Step {
+				for _, next := range st.peers[cur] {
+					if visited[next] || st.cells[next] != 0 || st.count(next) != 2 || st.cands[next]&out == 0 {
+						continue
+					}
+
+					nextOut := st.cands[next] &^ out
+					chain = append(chain, next)
+					visited[next] = true
+
+					if nextOut == zbit && len(chain) >= 3 {
+						elims := []Candidate{}
+
+						for _, cell := range st.peers[start] {
+							if !visited[cell] && st.isPeer[next][cell] && st.cands[cell]&zbit != 0 {
+								elims = append(elims, st.candidate(cell, z))
+							}
+						}
+
+						if len(elims) > 0 {
+							return &Step{Technique: XYChain, Cells: st.cellList(chain), Eliminations: elims}
+						}
+					}
+
+					if len(chain) < maxChainLength {
+						if step := extend(next, nextOut); step != nil {
+							return step
+						}
+					}
+
+					chain = chain[:len(chain)-1]
+					visited[next] = false
+				}
+
+				return nil
+			}
+
+			if step := extend(start, st.cands[start]&^zbit); step != nil {
+				return step
+			}
+		}
+	}
+
+	return nil
+}
+
+// lineOf returns the index of the row or column unit that cell lies in
+// Rows are the first size units of a layout, followed by the columns
+func (st *state) lineOf(cell int, kind unitKind) int {
+	if kind == rowUnit {
+		return cell / st.size
+	}
+
+	return st.size + cell%st.size
+}
+
+// containsAll returns true if every cell in cells belongs to unit u
+func (st *state) containsAll(u int, cells []int) bool {
+	for _, cell := range cells {
+		if !contains(st.cellUnits[cell], u) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cellList converts cell indices into 1-based Cells
+func (st *state) cellList(cells []int) []Cell {
+	list := []Cell{}
+
+	for _, cell := range cells {
+		list = append(list, st.cell(cell))
+	}
+
+	return list
+}
+
+// contains returns true if x is in xs
+func contains(xs []int, x int) bool {
+	for _, y := range xs {
+		if y == x {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package sudoku
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkSteps solves g step by step and fails if any step removes or places a digit
+// that disagrees with the solution
+func checkSteps(t *testing.T, g Grid) map[string]int {
+	solution, err := Solve(g)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := newState(g, Classic)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	used := map[string]int{}
+
+	for step := st.next(); step != nil; step = st.next() {
+		used[step.Technique]++
+
+		for _, e := range step.Eliminations {
+			if solution.Grid[e.Row-1][e.Col-1] == e.Value {
+				t.Fatalf("Step %s eliminates %d from (%d, %d), which is the solution", step.Technique, e.Value, e.Row, e.Col)
+			}
+		}
+
+		if p := step.Placement; p != nil && solution.Grid[p.Row-1][p.Col-1] != p.Value {
+			t.Fatalf("Step %s places %d in (%d, %d), expected %d", step.Technique, p.Value, p.Row, p.Col, solution.Grid[p.Row-1][p.Col-1])
+		}
+
+		st.apply(*step)
+	}
+
+	return used
+}
+
+func TestStepsIfConsistentWithSolution(t *testing.T) {
+	used := map[string]int{}
+
+	for seed := int64(0); seed < 100; seed++ {
+		for technique, n := range checkSteps(t, carve(rand.New(rand.NewSource(seed)), 17)) {
+			used[technique] += n
+		}
+	}
+
+	for _, technique := range techniques {
+		if used[technique.name] == 0 {
+			t.Logf("Technique %s was not used", technique.name)
+		}
+	}
+}
+
+func TestFindFishIfSwordfish(t *testing.T) {
+	st, err := newState(NewGrid(Size), Classic)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restrict 1 to columns 1, 4 and 7 in rows 1, 4 and 7
+	allowed := map[int][]int{0: {0, 3}, 3: {3, 6}, 6: {0, 6}}
+
+	for r, cols := range allowed {
+		for c := 0; c < Size; c++ {
+			if !contains(cols, c) {
+				st.cands[r*Size+c] &^= 1 << 1
+			}
+		}
+	}
+
+	step := findFish(st, 3, Swordfish)
+
+	if step == nil {
+		t.Fatalf("No step, expected %s", Swordfish)
+	}
+
+	// 1 is removed from the other 6 rows of each of the 3 columns
+	if len(step.Eliminations) != 18 {
+		t.Errorf("Actual eliminations: %d, expected 18", len(step.Eliminations))
+	}
+
+	for _, e := range step.Eliminations {
+		if e.Value != 1 || !contains([]int{1, 4, 7}, e.Col) || contains([]int{1, 4, 7}, e.Row) {
+			t.Errorf("Unexpected elimination: %+v", e)
+		}
+	}
+}
+
+func TestFindXYWingIfSuccessful(t *testing.T) {
+	st, err := newState(NewGrid(Size), Classic)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pivot (1, 1) = {1, 2}, pincers (1, 5) = {1, 3} and (2, 2) = {2, 3}
+	st.cands[st.index(1, 1)] = 1<<1 | 1<<2
+	st.cands[st.index(1, 5)] = 1<<1 | 1<<3
+	st.cands[st.index(2, 2)] = 1<<2 | 1<<3
+
+	step := findXYWing(st)
+
+	if step == nil {
+		t.Fatalf("No step, expected %s", XYWing)
+	}
+
+	// 3 is removed from the cells that see both pincers: (1, 2), (1, 3) and (2, 4), (2, 5), (2, 6)
+	expected := map[Cell]bool{{1, 2}: true, {1, 3}: true, {2, 4}: true, {2, 5}: true, {2, 6}: true}
+
+	if len(step.Eliminations) != len(expected) {
+		t.Fatalf("Actual eliminations: %+v, expected %d", step.Eliminations, len(expected))
+	}
+
+	for _, e := range step.Eliminations {
+		if e.Value != 3 || !expected[Cell{e.Row, e.Col}] {
+			t.Errorf("Unexpected elimination: %+v", e)
+		}
+	}
+}
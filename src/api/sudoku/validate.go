@@ -0,0 +1,46 @@
+package sudoku
+
+import "errors"
+
+// ErrMultipleSolutions is returned when a grid has more than one solution
+var ErrMultipleSolutions = errors.New("Puzzle has more than one solution")
+
+// Validate checks that the givens of g obey the row, column and box rules and that g has exactly one solution
+// Returns ErrContradictory, ErrUnsolvable or ErrMultipleSolutions if the grid is not a valid puzzle
+func Validate(g Grid) error {
+	return Classic.Validate(g)
+}
+
+// Validate checks that the givens of g obey rules and that g has exactly one solution under them
+func (rules Rules) Validate(g Grid) error {
+	count, err := rules.CountSolutions(g, 2)
+
+	if err != nil {
+		return err
+	}
+
+	switch count {
+	case 0:
+		return ErrUnsolvable
+	case 1:
+		return nil
+	default:
+		return ErrMultipleSolutions
+	}
+}
+
+// Solved reports whether every cell of g is filled without repeating a value in a row, column or box
+func Solved(g Grid) bool {
+	return Classic.Solved(g)
+}
+
+// Solved reports whether every cell of g is filled without breaking rules
+func (rules Rules) Solved(g Grid) bool {
+	if g.check() != nil || g.Filled() != g.Size()*g.Size() {
+		return false
+	}
+
+	_, err := newState(g, rules)
+
+	return err == nil
+}
@@ -0,0 +1,63 @@
+package sudoku
+
+import "testing"
+
+// ========== VALIDATE() ========== //
+func TestValidateIfSuccessful(t *testing.T) {
+	err := Validate(gridFromString(testPuzzle))
+
+	if err != nil {
+		t.Errorf("Error: %s, expected nil", err)
+	}
+}
+
+func TestValidateIfMultipleSolutions(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[0][0] = 0
+	g[0][1] = 0
+	g[0][4] = 0
+
+	err := Validate(g)
+
+	if err != ErrMultipleSolutions {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrMultipleSolutions)
+	}
+}
+
+func TestValidateIfContradictoryGivens(t *testing.T) {
+	g := gridFromString(testPuzzle)
+	g[8][0] = 5 // duplicates the 5 at (1, 1)
+
+	err := Validate(g)
+
+	if err != ErrContradictory {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrContradictory)
+	}
+}
+
+func TestValidateIfInvalidSize(t *testing.T) {
+	err := Validate(NewGrid(5))
+
+	if err == nil {
+		t.Errorf("No error, expected error")
+	}
+}
+
+// ========== SOLVED() ========== //
+func TestSolved(t *testing.T) {
+	g := gridFromString(testSolution)
+
+	if !Solved(g) {
+		t.Errorf("Actual solved: false, expected: true")
+	}
+
+	g[0][0], g[0][1] = g[0][1], g[0][0] // swapping breaks the columns
+
+	if Solved(g) {
+		t.Errorf("Actual solved: true, expected: false")
+	}
+
+	if Solved(gridFromString(testPuzzle)) {
+		t.Errorf("Actual solved: true for an unfilled grid, expected: false")
+	}
+}
@@ -0,0 +1,338 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+)
+
+var (
+	// ErrInvalidRegions is returned when jigsaw regions do not divide the grid into connected regions of one cell
+	// per value
+	ErrInvalidRegions = errors.New("Jigsaw regions must divide the grid into connected regions of one cell per value")
+
+	// ErrInvalidCage is returned when a killer cage has more cells than values or a sum that its cells cannot reach
+	ErrInvalidCage = errors.New("Killer cage must have distinct cells and a sum that its cells can reach")
+
+	// ErrCageNotContiguous is returned when the cells of a killer cage do not form a single connected group
+	ErrCageNotContiguous = errors.New("Killer cage must be a single group of orthogonally touching cells")
+
+	// ErrCagesOverlap is returned when a cell belongs to more than one killer cage
+	ErrCagesOverlap = errors.New("Killer cages must not overlap")
+)
+
+// Diagonal requires both main diagonals to hold every value once, as in X-sudoku
+type Diagonal struct{}
+
+// Variant returns the name of the X-sudoku variant
+func (Diagonal) Variant() string {
+	return models.VariantX
+}
+
+func (Diagonal) apply(l *layout) error {
+	main, anti := []int{}, []int{}
+
+	for i := 0; i < l.size; i++ {
+		main = append(main, i*l.size+i)
+		anti = append(anti, i*l.size+l.size-1-i)
+	}
+
+	l.add(diagonalUnit, main, 0)
+	l.add(diagonalUnit, anti, 0)
+	return nil
+}
+
+// Jigsaw replaces the boxes with irregular regions
+// Regions holds the region of every cell by 0-based row and column, numbered from 1 to the size of the grid, and
+// every region must be a connected group of as many cells as there are values
+type Jigsaw struct {
+	Regions [][]int
+}
+
+// Variant returns the name of the jigsaw variant
+func (Jigsaw) Variant() string {
+	return models.VariantJigsaw
+}
+
+func (j Jigsaw) apply(l *layout) error {
+	if len(j.Regions) != l.size {
+		return ErrInvalidRegions
+	}
+
+	regions := make([][]int, l.size)
+
+	for r, row := range j.Regions {
+		if len(row) != l.size {
+			return ErrInvalidRegions
+		}
+
+		for c, region := range row {
+			if region < 1 || region > l.size {
+				return ErrInvalidRegions
+			}
+
+			regions[region-1] = append(regions[region-1], r*l.size+c)
+		}
+	}
+
+	for _, cells := range regions {
+		if len(cells) != l.size || !connected(cells, l.size) {
+			return ErrInvalidRegions
+		}
+	}
+
+	l.removeKind(boxUnit)
+
+	for _, cells := range regions {
+		l.add(regionUnit, cells, 0)
+	}
+
+	return nil
+}
+
+// AntiKnight forbids a value from repeating a chess knight's move away
+type AntiKnight struct{}
+
+// Variant returns the name of the anti-knight variant
+func (AntiKnight) Variant() string {
+	return models.VariantAntiKnight
+}
+
+func (AntiKnight) apply(l *layout) error {
+	l.addMoves(knightUnit, [][2]int{{1, -2}, {1, 2}, {2, -1}, {2, 1}})
+	return nil
+}
+
+// AntiKing forbids a value from repeating a chess king's move away
+// Only diagonal moves add pairs, since orthogonally touching cells already share a row or column
+type AntiKing struct{}
+
+// Variant returns the name of the anti-king variant
+func (AntiKing) Variant() string {
+	return models.VariantAntiKing
+}
+
+func (AntiKing) apply(l *layout) error {
+	l.addMoves(kingUnit, [][2]int{{1, -1}, {1, 1}})
+	return nil
+}
+
+// EvenOdd limits the cells in Even to even values and the cells in Odd to odd values
+type EvenOdd struct {
+	Even []Cell
+	Odd  []Cell
+}
+
+// Variant returns the name of the even/odd variant
+func (EvenOdd) Variant() string {
+	return models.VariantEvenOdd
+}
+
+func (e EvenOdd) apply(l *layout) error {
+	var even uint32
+
+	for d := 2; d <= l.size; d += 2 {
+		even |= 1 << uint(d)
+	}
+
+	odd := (uint32(1<<uint(l.size+1)) - 2) &^ even
+
+	for _, group := range []struct {
+		cells []Cell
+		mask  uint32
+	}{{e.Even, even}, {e.Odd, odd}} {
+		for _, c := range group.cells {
+			cell, err := c.index(l.size)
+
+			if err != nil {
+				return err
+			}
+
+			if l.allowed[cell]&group.mask == 0 {
+				return fmt.Errorf("Cell (%d, %d) cannot be both even and odd", c.Row, c.Col)
+			}
+
+			l.allowed[cell] &= group.mask
+		}
+	}
+
+	return nil
+}
+
+// Cage is a group of cells whose values must be distinct and add up to Sum
+type Cage struct {
+	Cells []Cell `json:"cells"`
+	Sum   int    `json:"sum"`
+}
+
+// Killer adds cages to the grid
+// Every cage must be a connected group of cells, and no cell may belong to two cages
+type Killer struct {
+	Cages []Cage
+}
+
+// Variant returns the name of the killer variant
+func (Killer) Variant() string {
+	return models.VariantKiller
+}
+
+func (k Killer) apply(l *layout) error {
+	caged := map[int]bool{}
+
+	for _, cage := range k.Cages {
+		cells := []int{}
+		seen := map[int]bool{}
+
+		for _, c := range cage.Cells {
+			cell, err := c.index(l.size)
+
+			if err != nil {
+				return err
+			}
+
+			if seen[cell] {
+				return ErrInvalidCage
+			}
+
+			seen[cell] = true
+			cells = append(cells, cell)
+		}
+
+		n := len(cells)
+
+		// The smallest total of n distinct values is 1 + ... + n, and the largest is size + ... + (size-n+1)
+		if n == 0 || n > l.size || cage.Sum < n*(n+1)/2 || cage.Sum > n*(2*l.size-n+1)/2 {
+			return ErrInvalidCage
+		}
+
+		if !connected(cells, l.size) {
+			return ErrCageNotContiguous
+		}
+
+		for _, cell := range cells {
+			if caged[cell] {
+				return ErrCagesOverlap
+			}
+
+			caged[cell] = true
+		}
+
+		l.add(cageUnit, cells, cage.Sum)
+	}
+
+	return nil
+}
+
+// addMoves adds a unit for every pair of cells that are one of moves apart
+// Moves only point down the grid, so that every pair is added once
+func (l *layout) addMoves(kind unitKind, moves [][2]int) {
+	for r := 0; r < l.size; r++ {
+		for c := 0; c < l.size; c++ {
+			for _, m := range moves {
+				mr, mc := r+m[0], c+m[1]
+
+				if mr < l.size && mc >= 0 && mc < l.size {
+					l.add(kind, []int{r*l.size + c, mr*l.size + mc}, 0)
+				}
+			}
+		}
+	}
+}
+
+// connected returns true if cells form a single group of orthogonally touching cells in a grid of size columns
+func connected(cells []int, size int) bool {
+	in := map[int]bool{}
+
+	for _, cell := range cells {
+		in[cell] = true
+	}
+
+	visited := map[int]bool{cells[0]: true}
+	queue := []int{cells[0]}
+
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+
+		for _, next := range []int{cell - size, cell + size, cell - 1, cell + 1} {
+			// Cells to the left and right must stay on the same row
+			if (next == cell-1 || next == cell+1) && next/size != cell/size {
+				continue
+			}
+
+			if in[next] && !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return len(visited) == len(cells)
+}
+
+// PuzzleRules returns the constraints of the variants declared by puzzle
+// Jigsaw regions and even/odd cells are read from the Regions and Parity strings of the puzzle, and the cages of a
+// killer puzzle from cages, which are stored apart from the puzzle
+func PuzzleRules(puzzle models.Puzzle, cages []models.Cage) (Rules, error) {
+	size := puzzle.GridSize()
+	rules := Rules{}
+
+	for _, name := range puzzle.VariantNames() {
+		switch name {
+		case models.VariantX:
+			rules = append(rules, Diagonal{})
+		case models.VariantKiller:
+			killer := Killer{}
+
+			for _, c := range cages {
+				cage := Cage{Cells: []Cell{}, Sum: c.Sum}
+
+				for _, p := range c.Positions() {
+					cage.Cells = append(cage.Cells, Cell{Row: p[0], Col: p[1]})
+				}
+
+				killer.Cages = append(killer.Cages, cage)
+			}
+
+			rules = append(rules, killer)
+		case models.VariantAntiKnight:
+			rules = append(rules, AntiKnight{})
+		case models.VariantAntiKing:
+			rules = append(rules, AntiKing{})
+		case models.VariantJigsaw:
+			if len(puzzle.Regions) != size*size {
+				return nil, ErrInvalidRegions
+			}
+
+			regions := make([][]int, size)
+
+			for i, ch := range puzzle.Regions {
+				regions[i/size] = append(regions[i/size], models.SymbolValue(ch))
+			}
+
+			rules = append(rules, Jigsaw{Regions: regions})
+		case models.VariantEvenOdd:
+			parity := EvenOdd{}
+
+			for i, ch := range puzzle.Parity {
+				switch ch {
+				case models.EvenCell:
+					parity.Even = append(parity.Even, Cell{Row: i/size + 1, Col: i%size + 1})
+				case models.OddCell:
+					parity.Odd = append(parity.Odd, Cell{Row: i/size + 1, Col: i%size + 1})
+				}
+			}
+
+			rules = append(rules, parity)
+		default:
+			return nil, fmt.Errorf("Puzzle variant '%s' is not supported", name)
+		}
+	}
+
+	if err := rules.Check(size); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
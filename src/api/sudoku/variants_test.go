@@ -0,0 +1,179 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/models"
+)
+
+// ========== SOLVE() ========== //
+func TestSolveIfVariants(t *testing.T) {
+	cases := []struct {
+		name  string
+		size  int
+		rules Rules
+	}{
+		{"x", Size, Rules{Diagonal{}}},
+		{"anti_knight", Size, Rules{AntiKnight{}}},
+		{"anti_king", Size, Rules{AntiKing{}}},
+		{"jigsaw", 4, Rules{Jigsaw{Regions: [][]int{{1, 1, 1, 1}, {2, 2, 2, 3}, {2, 4, 3, 3}, {4, 4, 4, 3}}}}},
+		{"even_odd", 4, Rules{EvenOdd{Even: []Cell{{1, 1}, {2, 2}}, Odd: []Cell{{1, 2}}}}},
+		{"killer", 4, Rules{Killer{Cages: []Cage{{Cells: []Cell{{1, 1}, {1, 2}}, Sum: 7}, {Cells: []Cell{{2, 1}, {3, 1}}, Sum: 3}}}}},
+	}
+
+	for _, c := range cases {
+		solution, err := c.rules.Solve(NewGrid(c.size))
+
+		if err != nil {
+			t.Fatalf("Error for %s: %s, expected nil", c.name, err)
+		}
+
+		if !c.rules.Solved(solution.Grid) {
+			t.Errorf("Actual solution for %s: %s, expected a grid that obeys the rules", c.name, solution.Grid)
+		}
+	}
+}
+
+func TestSolveIfCageSumBroken(t *testing.T) {
+	rules := Rules{Killer{Cages: []Cage{{Cells: []Cell{{1, 1}, {1, 2}}, Sum: 3}}}}
+	g := NewGrid(4)
+	g[0][0] = 3
+
+	_, err := rules.Solve(g)
+
+	if err != ErrContradictory {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrContradictory)
+	}
+}
+
+// ========== SOLVED() ========== //
+func TestSolvedIfDiagonalRepeats(t *testing.T) {
+	g := gridFromString(testSolution)
+
+	if !Solved(g) {
+		t.Fatalf("Actual solved: false, expected true under the classic rules")
+	}
+
+	// The main diagonal of the solution holds 5 and 7 twice
+	if (Rules{Diagonal{}}).Solved(g) {
+		t.Errorf("Actual solved: true, expected false under the X-sudoku rules")
+	}
+}
+
+// ========== CONFLICTS() ========== //
+func TestConflictsIfVariants(t *testing.T) {
+	rules := Rules{AntiKnight{}, EvenOdd{Even: []Cell{{5, 5}}}}
+	g := NewGrid(Size)
+	g[4][4] = 5
+	g[5][6] = 5
+
+	conflicts := rules.Conflicts(g, 5, 5)
+
+	if len(conflicts) != 2 || conflicts[0].Unit != parityUnit || conflicts[1].Unit != "knight" || conflicts[1].Col != 7 {
+		t.Errorf("Actual conflicts: %+v, expected the cell itself for its parity and (6, 7) for the knight's move", conflicts)
+	}
+}
+
+// ========== CANDIDATES() ========== //
+func TestCandidatesIfCage(t *testing.T) {
+	// Two cells adding up to 3 can only hold 1 and 2
+	rules := Rules{Killer{Cages: []Cage{{Cells: []Cell{{1, 1}, {1, 2}}, Sum: 3}}}}
+
+	cands, err := rules.Candidates(NewGrid(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cands[0][0]) != 2 || cands[0][0][0] != 1 || cands[0][0][1] != 2 {
+		t.Errorf("Actual candidates: %v, expected [1 2]", cands[0][0])
+	}
+}
+
+// ========== PUZZLERULES() ========== //
+func TestPuzzleRulesIfSuccessful(t *testing.T) {
+	puzzle := models.Puzzle{
+		Size:     4,
+		Variants: "x,jigsaw,even_odd",
+		Regions:  "1111" + "2223" + "2433" + "4443",
+		Parity:   "E..." + "...." + "...." + "...O",
+	}
+
+	rules, err := PuzzleRules(puzzle, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rules) != 3 {
+		t.Fatalf("Actual rules: %+v, expected 3 constraints", rules)
+	}
+
+	if parity, ok := rules[2].(EvenOdd); !ok || len(parity.Even) != 1 || parity.Odd[0] != (Cell{Row: 4, Col: 4}) {
+		t.Errorf("Actual constraint: %+v, expected (1, 1) even and (4, 4) odd", rules[2])
+	}
+}
+
+func TestPuzzleRulesIfRegionsDisconnected(t *testing.T) {
+	// Region 1 is split in two
+	puzzle := models.Puzzle{Size: 4, Variants: "jigsaw", Regions: "1221" + "1221" + "3344" + "3344"}
+
+	_, err := PuzzleRules(puzzle, nil)
+
+	if err != ErrInvalidRegions {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrInvalidRegions)
+	}
+}
+
+func TestPuzzleRulesIfCagesOverlap(t *testing.T) {
+	puzzle := models.Puzzle{Size: 4, Variants: "killer"}
+	cages := []models.Cage{{Cells: "1112", Sum: 3}, {Cells: "1222", Sum: 7}}
+
+	_, err := PuzzleRules(puzzle, cages)
+
+	if err != ErrCagesOverlap {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrCagesOverlap)
+	}
+}
+
+func TestPuzzleRulesIfCageNotContiguous(t *testing.T) {
+	puzzle := models.Puzzle{Size: 4, Variants: "killer"}
+	cages := []models.Cage{{Cells: "1122", Sum: 3}}
+
+	_, err := PuzzleRules(puzzle, cages)
+
+	if err != ErrCageNotContiguous {
+		t.Errorf("Actual error: %v, expected: %v", err, ErrCageNotContiguous)
+	}
+}
+
+// ========== CAGEERRORS() ========== //
+func TestCageErrorsIfWrongSum(t *testing.T) {
+	rules := Rules{Killer{Cages: []Cage{{Cells: []Cell{{1, 1}, {1, 2}}, Sum: 7}, {Cells: []Cell{{2, 1}, {2, 2}}, Sum: 5}}}}
+	g := NewGrid(4)
+	g[0][0], g[0][1] = 1, 2
+	g[1][0] = 3
+
+	errs := rules.CageErrors(g, 1, 2)
+
+	if len(errs) != 1 || errs[0].Total != 3 || errs[0].Sum != 7 || errs[0].Repeated {
+		t.Errorf("Actual errors: %+v, expected a total of 3 for the cage of 7", errs)
+	}
+
+	// The cage of (2, 1) is not complete yet
+	if errs = rules.CageErrors(g, 2, 1); len(errs) != 0 {
+		t.Errorf("Actual errors: %+v, expected none", errs)
+	}
+}
+
+func TestCageErrorsIfRepeated(t *testing.T) {
+	rules := Rules{Killer{Cages: []Cage{{Cells: []Cell{{1, 1}, {2, 1}}, Sum: 4}}}}
+	g := NewGrid(4)
+	g[0][0], g[1][0] = 2, 2
+
+	errs := rules.CageErrors(g, 2, 1)
+
+	if len(errs) != 1 || !errs[0].Repeated || errs[0].Total != 4 {
+		t.Errorf("Actual errors: %+v, expected the repeated 2 to be reported", errs)
+	}
+}
@@ -0,0 +1,424 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/quattad/sudokubuddy-backend/src/api/responses"
+)
+
+// Message types of data frames, and the control frames that are handled by ReadMessage
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	closeMessage  = 8
+	pingMessage   = 9
+	pongMessage   = 10
+
+	continuationFrame = 0
+)
+
+// Status codes sent in close frames
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseTooLarge      = 1009
+)
+
+// MaxMessageSize is the largest message that ReadMessage accepts, since messages are buffered in memory
+const MaxMessageSize = 1 << 20
+
+// acceptGUID is appended to the key of a handshake to compute Sec-WebSocket-Accept, as defined by RFC 6455
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	// ErrClosed is returned by ReadMessage once a close frame is received, and by WriteMessage after Close
+	ErrClosed = errors.New("WebSocket connection is closed")
+
+	errBadHandshake   = errors.New("Request is not a WebSocket handshake")
+	errBadVersion     = errors.New("WebSocket version must be 13")
+	errNotHijackable  = errors.New("Response does not support WebSocket connections")
+	errProtocol       = errors.New("WebSocket frame is malformed")
+	errMessageTooLong = fmt.Errorf("WebSocket message is larger than %d bytes", MaxMessageSize)
+)
+
+// Conn is a WebSocket connection, as returned by Upgrade on the server or Dial on the client
+// ReadMessage must be called from one goroutine at a time, while WriteMessage may be called concurrently
+type Conn struct {
+	conn     net.Conn
+	br       *bufio.Reader
+	isServer bool
+
+	wmu    sync.Mutex
+	closed bool
+}
+
+// Upgrade completes the WebSocket handshake of r and takes over its connection
+// If r is not a valid handshake, Upgrade responds with status code 400 and returns the error
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		responses.ERROR(w, http.StatusBadRequest, errBadHandshake)
+		return nil, errBadHandshake
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		responses.ERROR(w, http.StatusBadRequest, errBadVersion)
+		return nil, errBadVersion
+	}
+
+	hj, ok := w.(http.Hijacker)
+
+	if !ok {
+		responses.ERROR(w, http.StatusInternalServerError, errNotHijackable)
+		return nil, errNotHijackable
+	}
+
+	conn, brw, err := hj.Hijack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+
+	if err == nil {
+		err = brw.Flush()
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: conn, br: brw.Reader, isServer: true}, nil
+}
+
+// Dial opens a WebSocket connection to rawurl, which uses the ws or http scheme, sending header with the handshake
+// If the server refuses the handshake, the response is returned with the error so its status code can be checked
+func Dial(rawurl string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawurl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u.Scheme = strings.Replace(u.Scheme, "ws", "http", 1)
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	nonce := make([]byte, 16)
+
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	conn, err := net.Dial("tcp", u.Host)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, resp, errBadHandshake
+	}
+
+	return &Conn{conn: conn, br: br}, resp, nil
+}
+
+// ReadMessage returns the type and payload of the next text or binary message, joining fragmented frames
+// Pings are answered with pongs while waiting. Once the peer closes the connection, the close is echoed and
+// ErrClosed is returned
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	messageType := 0
+	var message []byte
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case pingMessage:
+			if err = c.writeFrame(pongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case pongMessage:
+			continue
+		case closeMessage:
+			code := CloseNormal
+
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+
+			c.WriteClose(code)
+			return 0, nil, ErrClosed
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.fail(CloseProtocolError, errProtocol)
+			}
+			messageType = opcode
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.fail(CloseProtocolError, errProtocol)
+			}
+		default:
+			return 0, nil, c.fail(CloseProtocolError, errProtocol)
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return 0, nil, c.fail(CloseTooLarge, errMessageTooLong)
+		}
+
+		message = append(message, payload...)
+
+		if fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+// ReadJSON reads the next message and unmarshals it into v
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, message, err := c.ReadMessage()
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(message, v)
+}
+
+// WriteMessage sends data as a single frame of messageType
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return errProtocol
+	}
+
+	return c.writeFrame(messageType, data)
+}
+
+// WriteJSON marshals v and sends it as a text message
+func (c *Conn) WriteJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	return c.WriteMessage(TextMessage, b)
+}
+
+// WriteClose sends a close frame with code, after which no more messages can be written
+func (c *Conn) WriteClose(code int) error {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, uint16(code))
+
+	err := c.writeFrame(closeMessage, payload)
+
+	c.wmu.Lock()
+	c.closed = true
+	c.wmu.Unlock()
+
+	return err
+}
+
+// Close closes the underlying connection without a close frame
+func (c *Conn) Close() error {
+	c.wmu.Lock()
+	c.closed = true
+	c.wmu.Unlock()
+
+	return c.conn.Close()
+}
+
+// readFrame reads a single frame and unmasks its payload
+// Frames from clients must be masked and frames from servers must not be
+func (c *Conn) readFrame() (bool, int, []byte, error) {
+	header := make([]byte, 2)
+
+	if _, err := io.ReadFull(c.br, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+
+	// Extensions are never negotiated, so the reserved bits must be clear
+	if header[0]&0x70 != 0 || masked != c.isServer {
+		return false, 0, nil, c.fail(CloseProtocolError, errProtocol)
+	}
+
+	// Control frames cannot be fragmented and carry at most 125 bytes
+	if opcode >= closeMessage && (!fin || length > 125) {
+		return false, 0, nil, c.fail(CloseProtocolError, errProtocol)
+	}
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > MaxMessageSize {
+		return false, 0, nil, c.fail(CloseTooLarge, errMessageTooLong)
+	}
+
+	mask := make([]byte, 4)
+
+	if masked {
+		if _, err := io.ReadFull(c.br, mask); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame sends payload as a single final frame of opcode, masking it if c is a client
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	frame := []byte{0x80 | byte(opcode)}
+	length := len(payload)
+	maskBit := byte(0)
+
+	if !c.isServer {
+		maskBit = 0x80
+	}
+
+	switch {
+	case length <= 125:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, maskBit|126, byte(length>>8), byte(length))
+	default:
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(length))
+		frame = append(append(frame, maskBit|127), ext...)
+	}
+
+	if c.isServer {
+		frame = append(frame, payload...)
+	} else {
+		mask := make([]byte, 4)
+
+		if _, err := rand.Read(mask); err != nil {
+			return err
+		}
+
+		frame = append(frame, mask...)
+
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// fail sends a close frame with code after a violation of the protocol, and returns err
+func (c *Conn) fail(code int, err error) error {
+	c.WriteClose(code)
+	return err
+}
+
+// acceptKey computes the Sec-WebSocket-Accept header for the Sec-WebSocket-Key of a handshake
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContains checks if the comma separated header name contains token, ignoring case
+func headerContains(header http.Header, name string, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
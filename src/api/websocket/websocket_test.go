@@ -0,0 +1,178 @@
+package websocket
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newEchoServer starts a server that sends every message it reads back to the client
+func newEchoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		for {
+			messageType, message, err := conn.ReadMessage()
+
+			if err != nil {
+				return
+			}
+
+			if err = conn.WriteMessage(messageType, message); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+// ========== ACCEPTKEY() ========== //
+func TestAcceptKeyIfSuccessful(t *testing.T) {
+	// Example handshake from RFC 6455
+	if key := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); key != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Actual accept key: %s, expected: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", key)
+	}
+}
+
+// ========== UPGRADE() ========== //
+func TestUpgradeIfSuccessful(t *testing.T) {
+	server := newEchoServer(t)
+	defer server.Close()
+
+	conn, _, err := Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	defer conn.Close()
+
+	// Short, 16-bit and 64-bit payload lengths are all echoed
+	for _, size := range []int{5, 300, 70000} {
+		message := bytes.Repeat([]byte("x"), size)
+
+		if err = conn.WriteMessage(BinaryMessage, message); err != nil {
+			t.Fatal(err)
+		}
+
+		messageType, echo, err := conn.ReadMessage()
+
+		if err != nil {
+			t.Fatalf("Error: %s, expected nil", err)
+		}
+
+		if messageType != BinaryMessage || !bytes.Equal(echo, message) {
+			t.Errorf("Actual echo: type %d with %d bytes, expected: type %d with %d bytes", messageType, len(echo), BinaryMessage, size)
+		}
+	}
+}
+
+func TestUpgradeIfNotHandshake(t *testing.T) {
+	server := newEchoServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Actual status code: %d, expected: %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+// ========== READMESSAGE() ========== //
+func TestReadMessageIfFragmentedWithPing(t *testing.T) {
+	server := newEchoServer(t)
+	defer server.Close()
+
+	conn, _, err := Dial(server.URL, nil)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	defer conn.Close()
+
+	// A ping between the fragments of a message is answered before the message is echoed
+	frames := []struct {
+		fin     bool
+		opcode  int
+		payload string
+	}{
+		{false, TextMessage, "hel"},
+		{true, pingMessage, "are you there"},
+		{true, continuationFrame, "lo"},
+	}
+
+	for _, f := range frames {
+		if err = writeTestFrame(conn, f.fin, f.opcode, []byte(f.payload)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fin, opcode, payload, err := conn.readFrame()
+
+	if err != nil || !fin || opcode != pongMessage || string(payload) != "are you there" {
+		t.Fatalf("Actual frame: %v %d %q %v, expected pong", fin, opcode, payload, err)
+	}
+
+	messageType, message, err := conn.ReadMessage()
+
+	if err != nil || messageType != TextMessage || string(message) != "hello" {
+		t.Errorf("Actual message: %d %q %v, expected text hello", messageType, message, err)
+	}
+}
+
+func TestReadMessageIfClosed(t *testing.T) {
+	server := newEchoServer(t)
+	defer server.Close()
+
+	conn, _, err := Dial(server.URL, nil)
+
+	if err != nil {
+		t.Fatalf("Error: %s, expected nil", err)
+	}
+
+	defer conn.Close()
+
+	if err = conn.WriteClose(CloseGoingAway); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server echoes the close frame
+	if _, _, err = conn.ReadMessage(); err != ErrClosed {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrClosed)
+	}
+
+	if err = conn.WriteMessage(TextMessage, []byte("late")); err != ErrClosed {
+		t.Errorf("Actual error: %v, expected: %s", err, ErrClosed)
+	}
+}
+
+// writeTestFrame writes a masked client frame, which may be a fragment
+func writeTestFrame(c *Conn, fin bool, opcode int, payload []byte) error {
+	b0 := byte(opcode)
+
+	if fin {
+		b0 |= 0x80
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	frame := append([]byte{b0, 0x80 | byte(len(payload))}, mask...)
+
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}